@@ -2,7 +2,8 @@ package main
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,66 +17,352 @@ import (
 	"github.com/vasujain275/bookbridge-api/internal/config"
 	"github.com/vasujain275/bookbridge-api/internal/database"
 	"github.com/vasujain275/bookbridge-api/internal/handler"
+	"github.com/vasujain275/bookbridge-api/internal/logger"
+	"github.com/vasujain275/bookbridge-api/internal/mail"
+	"github.com/vasujain275/bookbridge-api/internal/metrics"
 	"github.com/vasujain275/bookbridge-api/internal/middleware"
 	"github.com/vasujain275/bookbridge-api/internal/repository"
 	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/storage"
+	"github.com/vasujain275/bookbridge-api/internal/tracing"
+	"github.com/vasujain275/bookbridge-api/migrations"
 )
 
+// @securityDefinitions.basic BasicAuth
 func main() {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		fatal("Failed to load configuration", err)
 	}
 
+	// Log structured records, as JSON in production
+	log := logger.New(cfg.Environment, cfg.LogLevel)
+	slog.SetDefault(log)
+
 	// Set Gin mode based on environment
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Export traces
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracingExporter)
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
+
 	// Initialize database connection
-	db, err := database.New(cfg.PostgresConnectionString())
+	db, err := database.New(cfg.PostgresConnectionString(), log)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal("Failed to connect to database", err)
 	}
 	defer db.Close()
 
 	// Initialize repository
 	repo := repository.New(db.Pool)
 
+	// Initialize cover storage
+	coverStorage, err := newCoverStorage(cfg)
+	if err != nil {
+		fatal("Failed to initialize cover storage", err)
+	}
+
 	// Initialize services
-	userService := service.NewUserService(repo)
+	mailSender := mail.NewSMTPSender(mail.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+	})
+	notificationService := service.NewNotificationService(repo, mailSender, service.NotificationPolicy{
+		From:            cfg.MailFrom,
+		DueReminderDays: cfg.DueReminderDays,
+		MaxAttempts:     cfg.NotificationMaxAttempts,
+	})
+	webhookService := service.NewWebhookService(repo, service.WebhookPolicy{
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		Timeout:     cfg.WebhookTimeout,
+	})
+	eventStreamService := service.NewEventStreamService(db, repo, cfg.EventStreamBuffer)
+	outboxService := service.NewOutboxService(db, repo, webhookService, notificationService)
+	userService := service.NewUserService(db, repo)
+	if cfg.AdminUsername != "" && cfg.AdminPassword != "" {
+		err := userService.EnsureAdmin(context.Background(), repository.CreateUserParams{
+			Username:     cfg.AdminUsername,
+			Email:        cfg.AdminEmail,
+			PasswordHash: cfg.AdminPassword,
+			FirstName:    "Library",
+			LastName:     "Admin",
+		})
+		if err != nil {
+			fatal("Failed to create admin user", err)
+		}
+	}
+	auditService := service.NewAuditService(repo)
 	openLibraryService := service.NewOpenLibraryService()
-	bookService := service.NewBookService(repo, openLibraryService)
+	migrationVersion, err := migrations.Latest()
+	if err != nil {
+		fatal("Failed to read migrations", err)
+	}
+	healthService := service.NewHealthService(db, openLibraryService, service.HealthPolicy{
+		MigrationVersion: migrationVersion,
+		CheckOpenLibrary: cfg.ReadinessCheckOpenLibrary,
+		Timeout:          cfg.ReadinessTimeout,
+	})
+	coverService := service.NewCoverService(repo, coverStorage)
+	bookService := service.NewBookService(db, repo, openLibraryService, coverService, cfg.CallNumberScheme)
+	marcService := service.NewMarcService(db, repo, cfg.CallNumberScheme)
+	citationService := service.NewCitationService(repo)
+	workService := service.NewWorkService(repo)
+	holdService := service.NewHoldService(db, repo)
+	seriesService := service.NewSeriesService(repo)
+	authorService := service.NewAuthorService(repo)
+	mergeService := service.NewMergeService(db, repo)
+	categoryService := service.NewCategoryService(db, repo)
+	shelfService := service.NewShelfService(repo, cfg.CallNumberScheme)
+	reviewService := service.NewReviewService(db, repo, service.ReviewPolicy{
+		BlockedWords:    cfg.ReviewBlockedWords,
+		RejectionLimit:  cfg.ReviewRejectionLimit,
+		RejectionWindow: cfg.ReviewRejectionWindow,
+	})
+	readingListService := service.NewReadingListService(db, repo, holdService)
+	recommendationService := service.NewRecommendationService(db, repo)
+	overdueService := service.NewOverdueService(db, repo)
 
 	// Initialize router
-	router := gin.Default()
+	router := gin.New()
+
+	// Register health routes ahead of the global middleware, so probes are not traced, logged,
+	// authenticated or audited
+	healthHandler := handler.NewHealthHandler(healthService)
+	router.GET("/healthz", healthHandler.Liveness) // GET /healthz
+	router.GET("/readyz", healthHandler.Readiness) // GET /readyz
 
 	// Setup global middleware
-	middleware.SetupGlobalMiddleware(router)
+	middleware.SetupGlobalMiddleware(router, log)
+	router.Use(middleware.Authenticate(userService))
+	router.Use(middleware.Audit(auditService))
 
 	// Register Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// Register user routes
 	userHandler := handler.NewUserHandler(userService)
+	requireAdmin := middleware.RequireRole(service.RoleAdmin)
+	requireUser := middleware.RequireRole(service.RoleAdmin, service.RoleMember)
 	userRoutes := router.Group("/users")
 	{
-		userRoutes.GET("/:id", userHandler.GetUser)       // GET /users/{id}
-		userRoutes.GET("", userHandler.ListUsers)         // GET /users?limit=&offset=
-		userRoutes.POST("", userHandler.CreateUser)       // POST /users
-		userRoutes.PUT("/:id", userHandler.UpdateUser)    // PUT /users/{id}
-		userRoutes.DELETE("/:id", userHandler.DeleteUser) // DELETE /users/{id}
+		userRoutes.GET("/:id", requireUser, userHandler.GetUser)        // GET /users/{id}
+		userRoutes.GET("", requireAdmin, userHandler.ListUsers)         // GET /users?limit=&offset=
+		userRoutes.POST("", requireAdmin, userHandler.CreateUser)       // POST /users
+		userRoutes.PUT("/:id", requireUser, userHandler.UpdateUser)     // PUT /users/{id}
+		userRoutes.DELETE("/:id", requireAdmin, userHandler.DeleteUser) // DELETE /users/{id}
 	}
 
 	// Register book routes
 	bookHandler := handler.NewBookHandler(bookService)
 	bookRoutes := router.Group("/books")
 	{
-		bookRoutes.GET("/:id", bookHandler.GetBook)              // GET /books/{id}
-		bookRoutes.GET("", bookHandler.ListBooks)                // GET /books?limit=&offset=
-		bookRoutes.POST("", bookHandler.CreateBook)              // POST /books
-		bookRoutes.GET("/isbn/:isbn", bookHandler.GetBookByISBN) // GET /books/isbn/{isbn}
+		bookRoutes.GET("/:id", bookHandler.GetBook)                // GET /books/{id}
+		bookRoutes.GET("", bookHandler.ListBooks)                  // GET /books?sort=&limit=&offset=
+		bookRoutes.POST("", bookHandler.CreateBook)                // POST /books
+		bookRoutes.GET("/isbn/:isbn", bookHandler.GetBookByISBN)   // GET /books/isbn/{isbn}
+		bookRoutes.GET("/:id/details", bookHandler.GetBookDetails) // GET /books/{id}/details
+	}
+
+	// Register MARC import/export routes
+	marcHandler := handler.NewMarcHandler(marcService)
+	bookRoutes.GET("/:id/marc", marcHandler.GetBookMarc)              // GET /books/{id}/marc?format=
+	router.GET("/export/marc", marcHandler.ExportMarc)                // GET /export/marc?format=
+	router.POST("/import/marc", requireAdmin, marcHandler.ImportMarc) // POST /import/marc?format=
+
+	// Register cover routes
+	coverHandler := handler.NewCoverHandler(coverService)
+	bookRoutes.GET("/:id/cover", coverHandler.GetCover)                   // GET /books/{id}/cover?size=
+	bookRoutes.POST("/:id/cover", requireAdmin, coverHandler.UploadCover) // POST /books/{id}/cover
+
+	// Register citation routes
+	citationHandler := handler.NewCitationHandler(citationService)
+	bookRoutes.GET("/:id/cite", citationHandler.GetBookCitation) // GET /books/{id}/cite?format=
+	bookRoutes.POST("/cite", citationHandler.CiteBooks)          // POST /books/cite
+
+	// Register work routes
+	workHandler := handler.NewWorkHandler(workService)
+	holdHandler := handler.NewHoldHandler(holdService)
+	workRoutes := router.Group("/works")
+	{
+		workRoutes.GET("/:id", workHandler.GetWork)                          // GET /works/{id}
+		workRoutes.GET("", workHandler.SearchWorks)                          // GET /works?q=&limit=&offset=
+		workRoutes.GET("/:id/holds", requireUser, holdHandler.ListWorkHolds) // GET /works/{id}/holds
+	}
+
+	// Register hold routes
+	holdRoutes := router.Group("/holds", requireUser)
+	{
+		holdRoutes.GET("/:id", holdHandler.GetHold)       // GET /holds/{id}
+		holdRoutes.POST("", holdHandler.PlaceHold)        // POST /holds
+		holdRoutes.DELETE("/:id", holdHandler.CancelHold) // DELETE /holds/{id}
+	}
+	userRoutes.GET("/:id/holds", requireUser, holdHandler.ListUserHolds) // GET /users/{id}/holds?limit=&offset=
+
+	// Register series routes
+	seriesHandler := handler.NewSeriesHandler(seriesService)
+	seriesRoutes := router.Group("/series")
+	{
+		seriesRoutes.GET("/:id", seriesHandler.GetSeries)                                       // GET /series/{id}
+		seriesRoutes.GET("", seriesHandler.ListSeries)                                          // GET /series?limit=&offset=
+		seriesRoutes.POST("", requireAdmin, seriesHandler.CreateSeries)                         // POST /series
+		seriesRoutes.PUT("/:id", requireAdmin, seriesHandler.UpdateSeries)                      // PUT /series/{id}
+		seriesRoutes.DELETE("/:id", requireAdmin, seriesHandler.DeleteSeries)                   // DELETE /series/{id}
+		seriesRoutes.PUT("/:id/works/:workId", requireAdmin, seriesHandler.SetSeriesVolume)     // PUT /series/{id}/works/{workId}
+		seriesRoutes.DELETE("/:id/works/:workId", requireAdmin, seriesHandler.RemoveSeriesWork) // DELETE /series/{id}/works/{workId}
+	}
+
+	// Register author routes
+	authorHandler := handler.NewAuthorHandler(authorService)
+	authorRoutes := router.Group("/authors")
+	{
+		authorRoutes.GET("/:id", authorHandler.GetAuthor)                     // GET /authors/{id}
+		authorRoutes.GET("", authorHandler.ListAuthors)                       // GET /authors?limit=&offset=
+		authorRoutes.POST("", requireAdmin, authorHandler.CreateAuthor)       // POST /authors
+		authorRoutes.PUT("/:id", requireAdmin, authorHandler.UpdateAuthor)    // PUT /authors/{id}
+		authorRoutes.DELETE("/:id", requireAdmin, authorHandler.DeleteAuthor) // DELETE /authors/{id}
+		authorRoutes.GET("/:id/books", authorHandler.GetAuthorBooks)          // GET /authors/{id}/books
+	}
+	bookRoutes.GET("/:id/authors", authorHandler.ListBookAuthors)                             // GET /books/{id}/authors
+	bookRoutes.POST("/:id/authors", requireAdmin, authorHandler.AttachBookAuthor)             // POST /books/{id}/authors
+	bookRoutes.DELETE("/:id/authors/:authorId", requireAdmin, authorHandler.DetachBookAuthor) // DELETE /books/{id}/authors/{authorId}
+
+	// Register duplicate detection and merge routes
+	mergeHandler := handler.NewMergeHandler(mergeService)
+	authorRoutes.GET("/duplicates", requireAdmin, mergeHandler.ListAuthorDuplicates) // GET /authors/duplicates?min_similarity=&limit=&offset=
+	authorRoutes.POST("/merge", requireAdmin, mergeHandler.MergeAuthors)             // POST /authors/merge
+	bookRoutes.GET("/duplicates", requireAdmin, mergeHandler.ListBookDuplicates)     // GET /books/duplicates?min_similarity=&limit=&offset=
+	bookRoutes.POST("/merge", requireAdmin, mergeHandler.MergeBooks)                 // POST /books/merge
+
+	// Register category routes
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	categoryRoutes := router.Group("/categories")
+	{
+		categoryRoutes.GET("/tree", categoryHandler.GetCategoryTree)                  // GET /categories/tree
+		categoryRoutes.GET("/:id", categoryHandler.GetCategory)                       // GET /categories/{id}
+		categoryRoutes.GET("", categoryHandler.ListCategories)                        // GET /categories?limit=&offset=
+		categoryRoutes.GET("/:id/subtree", categoryHandler.GetCategorySubtree)        // GET /categories/{id}/subtree
+		categoryRoutes.GET("/:id/books", categoryHandler.ListCategoryBooks)           // GET /categories/{id}/books?limit=&offset=
+		categoryRoutes.POST("", requireAdmin, categoryHandler.CreateCategory)         // POST /categories
+		categoryRoutes.PUT("/:id", requireAdmin, categoryHandler.RenameCategory)      // PUT /categories/{id}
+		categoryRoutes.PUT("/:id/parent", requireAdmin, categoryHandler.MoveCategory) // PUT /categories/{id}/parent
+		categoryRoutes.DELETE("/:id", requireAdmin, categoryHandler.DeleteCategory)   // DELETE /categories/{id}
+	}
+	bookRoutes.GET("/:id/categories", categoryHandler.ListBookCategories)                              // GET /books/{id}/categories
+	bookRoutes.POST("/:id/categories", requireAdmin, categoryHandler.AttachBookCategory)               // POST /books/{id}/categories
+	bookRoutes.DELETE("/:id/categories/:categoryId", requireAdmin, categoryHandler.DetachBookCategory) // DELETE /books/{id}/categories/{categoryId}
+
+	// Register classification and shelf routes
+	shelfHandler := handler.NewShelfHandler(shelfService)
+	router.GET("/shelf", shelfHandler.BrowseShelf)                                 // GET /shelf?from=&to=&scheme=&limit=&offset=
+	bookRoutes.PUT("/:id/classification", requireAdmin, shelfHandler.ClassifyBook) // PUT /books/{id}/classification
+
+	// Register review routes
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	bookRoutes.GET("/:id/reviews", reviewHandler.ListBookReviews)                                  // GET /books/{id}/reviews?sort=&limit=&offset=
+	bookRoutes.GET("/:id/reviews/:reviewId", reviewHandler.GetBookReview)                          // GET /books/{id}/reviews/{reviewId}
+	bookRoutes.POST("/:id/reviews", requireUser, reviewHandler.CreateBookReview)                   // POST /books/{id}/reviews
+	bookRoutes.PUT("/:id/reviews/:reviewId", requireUser, reviewHandler.UpdateBookReview)          // PUT /books/{id}/reviews/{reviewId}
+	bookRoutes.DELETE("/:id/reviews/:reviewId", requireUser, reviewHandler.DeleteBookReview)       // DELETE /books/{id}/reviews/{reviewId}
+	bookRoutes.PUT("/:id/reviews/:reviewId/vote", requireUser, reviewHandler.VoteBookReview)       // PUT /books/{id}/reviews/{reviewId}/vote
+	bookRoutes.DELETE("/:id/reviews/:reviewId/vote", requireUser, reviewHandler.UnvoteBookReview)  // DELETE /books/{id}/reviews/{reviewId}/vote
+	bookRoutes.POST("/:id/reviews/:reviewId/reports", requireUser, reviewHandler.ReportBookReview) // POST /books/{id}/reviews/{reviewId}/reports
+	userRoutes.GET("/:id/reviews", reviewHandler.ListUserReviews)                                  // GET /users/{id}/reviews?limit=&offset=
+
+	// Register review moderation routes
+	moderationRoutes := router.Group("/moderation", requireAdmin)
+	{
+		moderationRoutes.GET("/reviews", reviewHandler.GetModerationQueue)  // GET /moderation/reviews?limit=&offset=
+		moderationRoutes.POST("/reviews/:id", reviewHandler.ModerateReview) // POST /moderation/reviews/{id}
+	}
+
+	// Register reading list routes
+	readingListHandler := handler.NewReadingListHandler(readingListService)
+	readingListRoutes := router.Group("/reading-lists")
+	{
+		readingListRoutes.GET("/shared/:token", readingListHandler.GetSharedReadingList)                      // GET /reading-lists/shared/{token}
+		readingListRoutes.GET("/:id", readingListHandler.GetReadingList)                                      // GET /reading-lists/{id}
+		readingListRoutes.POST("", requireUser, readingListHandler.CreateReadingList)                         // POST /reading-lists
+		readingListRoutes.PUT("/:id", requireUser, readingListHandler.UpdateReadingList)                      // PUT /reading-lists/{id}
+		readingListRoutes.DELETE("/:id", requireUser, readingListHandler.DeleteReadingList)                   // DELETE /reading-lists/{id}
+		readingListRoutes.POST("/:id/items", requireUser, readingListHandler.AddReadingListItem)              // POST /reading-lists/{id}/items
+		readingListRoutes.PUT("/:id/items/:itemId", requireUser, readingListHandler.UpdateReadingListItem)    // PUT /reading-lists/{id}/items/{itemId}
+		readingListRoutes.DELETE("/:id/items/:itemId", requireUser, readingListHandler.RemoveReadingListItem) // DELETE /reading-lists/{id}/items/{itemId}
+		readingListRoutes.PUT("/:id/order", requireUser, readingListHandler.ReorderReadingList)               // PUT /reading-lists/{id}/order
+		readingListRoutes.POST("/:id/holds", requireUser, readingListHandler.HoldReadingList)                 // POST /reading-lists/{id}/holds
+	}
+	userRoutes.GET("/:id/reading-lists", readingListHandler.ListUserReadingLists) // GET /users/{id}/reading-lists?limit=&offset=
+
+	// Register reading statistics and history routes
+	userRoutes.GET("/:id/stats", requireUser, userHandler.GetUserStats)                    // GET /users/{id}/stats?year=
+	userRoutes.PUT("/:id/history-retention", requireUser, userHandler.SetHistoryRetention) // PUT /users/{id}/history-retention
+
+	// Register recommendation routes
+	recommendationHandler := handler.NewRecommendationHandler(recommendationService)
+	userRoutes.GET("/:id/recommendations", requireUser, recommendationHandler.GetUserRecommendations)   // GET /users/{id}/recommendations?limit=&offset=
+	bookRoutes.GET("/:id/also-borrowed", recommendationHandler.ListAlsoBorrowed)                        // GET /books/{id}/also-borrowed?limit=
+	router.POST("/recommendations/refresh", requireAdmin, recommendationHandler.RefreshRecommendations) // POST /recommendations/refresh
+
+	// Register notification routes
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	userRoutes.GET("/:id/notification-preferences", requireUser, notificationHandler.GetNotificationPreferences)    // GET /users/{id}/notification-preferences
+	userRoutes.PUT("/:id/notification-preferences", requireUser, notificationHandler.UpdateNotificationPreferences) // PUT /users/{id}/notification-preferences
+	userRoutes.GET("/:id/notifications", requireUser, notificationHandler.ListUserNotifications)                    // GET /users/{id}/notifications?limit=&offset=
+	notificationRoutes := router.Group("/notifications", requireAdmin)
+	{
+		notificationRoutes.GET("", notificationHandler.ListNotifications)            // GET /notifications?status=&limit=&offset=
+		notificationRoutes.POST("/:id/retry", notificationHandler.RetryNotification) // POST /notifications/{id}/retry
+	}
+
+	// Register webhook routes
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	webhookRoutes := router.Group("/webhooks", requireAdmin)
+	{
+		webhookRoutes.GET("", webhookHandler.ListWebhooks)                                                   // GET /webhooks
+		webhookRoutes.GET("/:id", webhookHandler.GetWebhook)                                                 // GET /webhooks/{id}
+		webhookRoutes.POST("", webhookHandler.CreateWebhook)                                                 // POST /webhooks
+		webhookRoutes.PUT("/:id", webhookHandler.UpdateWebhook)                                              // PUT /webhooks/{id}
+		webhookRoutes.DELETE("/:id", webhookHandler.DeleteWebhook)                                           // DELETE /webhooks/{id}
+		webhookRoutes.GET("/:id/deliveries", webhookHandler.ListWebhookDeliveries)                           // GET /webhooks/{id}/deliveries?status=&limit=&offset=
+		webhookRoutes.POST("/:id/deliveries/:deliveryId/redeliver", webhookHandler.RedeliverWebhookDelivery) // POST /webhooks/{id}/deliveries/{deliveryId}/redeliver
+	}
+
+	// Register audit log routes
+	auditHandler := handler.NewAuditHandler(auditService)
+	router.GET("/audit", requireAdmin, auditHandler.ListAuditEntries) // GET /audit?entity=&entity_id=&actor=&from=&to=&limit=&offset=
+
+	// Register live event stream routes
+	if cfg.EventStreamPollInterval > 0 {
+		eventStreamHandler := handler.NewEventStreamHandler(eventStreamService)
+		router.GET("/events/stream", requireAdmin, eventStreamHandler.StreamEvents) // GET /events/stream?type=
+	}
+
+	// Start background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if cfg.RecommendationRefreshInterval > 0 {
+		go recommendationService.Run(jobsCtx, cfg.RecommendationRefreshInterval)
+	}
+	if cfg.NotificationInterval > 0 {
+		go notificationService.Run(jobsCtx, cfg.NotificationInterval)
+	}
+	if cfg.OverdueCheckInterval > 0 {
+		go overdueService.Run(jobsCtx, cfg.OverdueCheckInterval)
+	}
+	if cfg.OutboxPollInterval > 0 {
+		go outboxService.Run(jobsCtx, cfg.OutboxPollInterval)
+	}
+	if cfg.WebhookPollInterval > 0 {
+		go webhookService.Run(jobsCtx, cfg.WebhookPollInterval)
+	}
+	if cfg.EventStreamPollInterval > 0 {
+		go eventStreamService.Run(jobsCtx, cfg.EventStreamPollInterval)
 	}
 
 	// Create server
@@ -86,17 +373,42 @@ func main() {
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("Starting server on port %s", cfg.ServerPort)
+		log.Info("Starting server", "port", cfg.ServerPort)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+			fatal("Failed to start server", err)
 		}
 	}()
 
+	// Serve metrics on their own listener, kept off the public API
+	var metricsSrv *http.Server
+	if cfg.MetricsAddr != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("GET /metrics", metrics.Handler(db, repo)) // GET /metrics
+		metricsSrv = &http.Server{
+			Addr:    cfg.MetricsAddr,
+			Handler: metricsMux,
+		}
+		go func() {
+			log.Info("Starting metrics server", "addr", cfg.MetricsAddr)
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				fatal("Failed to start metrics server", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
+	log.Info("Shutting down server...")
+
+	// Report not ready straight away, and keep serving while load balancers stop sending requests
+	healthService.ShutDown()
+	time.Sleep(cfg.ShutdownDrainDelay)
+
+	stopJobs()
+	// End open event streams, which would otherwise keep Shutdown waiting
+	eventStreamService.Close()
 
 	// Create context with timeout for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -104,8 +416,42 @@ func main() {
 
 	// Shutdown server
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		fatal("Server forced to shutdown", err)
 	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(ctx); err != nil {
+			log.Error("Failed to shut down metrics server", "error", err)
+		}
+	}
+
+	// Flush the spans still buffered
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error("Failed to flush traces", "error", err)
+	}
+
+	log.Info("Server exited properly")
+}
+
+// fatal logs an error that stops the server and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
 
-	log.Println("Server exited properly")
+// newCoverStorage creates the cover storage backend selected by the configuration
+func newCoverStorage(cfg *config.Config) (storage.Storage, error) {
+	switch cfg.CoverStorage {
+	case "local":
+		return storage.NewLocalStorage(cfg.CoverStoragePath)
+	case "s3":
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown cover storage backend %q", cfg.CoverStorage)
+	}
 }
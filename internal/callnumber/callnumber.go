@@ -0,0 +1,271 @@
+package callnumber
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Supported classification schemes
+const (
+	SchemeDewey = "dewey"
+	SchemeLC    = "lc"
+)
+
+var (
+	deweyNumber = regexp.MustCompile(`^(\d{1,3})(\.\d+)?`)
+	lcClass     = regexp.MustCompile(`^([A-Z]{1,3})\s*(\d+)(\.\d+)?`)
+	// A cutter follows the class number as a dot and a letter, e.g. the ".R34" in "PR6066.R34"
+	lcCutter  = regexp.MustCompile(`^[A-Z]{1,3}\s*\d+(\.\d+)?\s*\.[A-Z]`)
+	yearAtEnd = regexp.MustCompile(`\b\d{4}[a-z]?$`)
+	keySplit  = regexp.MustCompile(`[\s.]+`)
+)
+
+// Input holds what a call number is built from
+type Input struct {
+	Dewey     string // Dewey Decimal class, e.g. "823.914" or "823/.914"
+	LC        string // Library of Congress classification, e.g. "PR6066.R34 C6"
+	MainEntry string // Main author's name, or the title when there is none
+	Year      string
+}
+
+// CallNumber is a generated call number with the scheme it belongs to and its shelf-order sort key
+type CallNumber struct {
+	Scheme string
+	Value  string
+	Key    string
+}
+
+// Build generates a call number in the preferred scheme, falling back to the other one.
+// It returns false when the input has no usable classification.
+func Build(preferred string, in Input) (CallNumber, bool) {
+	dewey := NormalizeDewey(in.Dewey)
+	lc := NormalizeLC(in.LC)
+
+	schemes := []string{SchemeDewey, SchemeLC}
+	if preferred == SchemeLC {
+		schemes = []string{SchemeLC, SchemeDewey}
+	}
+	for _, scheme := range schemes {
+		var value string
+		switch {
+		case scheme == SchemeDewey && dewey != "":
+			value = joinParts(dewey, deweyCutter(in.MainEntry), in.Year)
+		case scheme == SchemeLC && lc != "":
+			value = lcCallNumber(lc, in.MainEntry, in.Year)
+		default:
+			continue
+		}
+		return CallNumber{Scheme: scheme, Value: value, Key: ShelfKey(scheme, value)}, true
+	}
+	return CallNumber{}, false
+}
+
+// NormalizeDewey strips the prime marks and brackets Open Library keeps in Dewey classes, e.g. "823/.914" becomes "823.914"
+func NormalizeDewey(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\'', '[', ']':
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// NormalizeLC upper-cases an LC classification and collapses its whitespace
+func NormalizeLC(s string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(s)), " ")
+}
+
+// Copies gives each copy of a book its own call number by adding a copy number.
+// A single copy keeps the plain call number.
+func Copies(callNumber string, total int) []string {
+	if callNumber == "" || total < 1 {
+		return []string{}
+	}
+	if total == 1 {
+		return []string{callNumber}
+	}
+	copies := make([]string, total)
+	for i := range copies {
+		copies[i] = fmt.Sprintf("%s c.%d", callNumber, i+1)
+	}
+	return copies
+}
+
+// InferScheme guesses the scheme of a call number: Dewey classes start with a digit, LC classes with letters
+func InferScheme(s string) string {
+	s = strings.TrimSpace(s)
+	if s != "" && s[0] >= '0' && s[0] <= '9' {
+		return SchemeDewey
+	}
+	return SchemeLC
+}
+
+// ShelfKey normalizes a call number, or the start of one, into a key whose byte order is shelf order.
+// Dewey numbers sort numerically before any LC class; cutters sort as decimals, so ".R3" comes before ".R34".
+func ShelfKey(scheme, callNumber string) string {
+	s := strings.ToUpper(strings.TrimSpace(callNumber))
+	if scheme == SchemeDewey {
+		s = NormalizeDewey(s)
+		var b strings.Builder
+		b.WriteString("D")
+		if m := deweyNumber.FindStringSubmatch(s); m != nil {
+			fmt.Fprintf(&b, " %03s%s", m[1], m[2])
+			s = s[len(m[0]):]
+		}
+		writeTokens(&b, s)
+		return b.String()
+	}
+
+	var b strings.Builder
+	b.WriteString("L")
+	if m := lcClass.FindStringSubmatch(s); m != nil {
+		// Pad the class letters so "Q" files before "QA", and the class number so 76 files before 345
+		fmt.Fprintf(&b, " %-3s %04s%s", m[1], m[2], m[3])
+		s = s[len(m[0]):]
+	} else if letters := leadingLetters(s); letters != "" {
+		fmt.Fprintf(&b, " %-3s", letters)
+		s = s[len(letters):]
+	}
+	writeTokens(&b, s)
+	return strings.TrimRight(b.String(), " ")
+}
+
+// Cutter derives a two-figure Cutter number from a name using the Library of Congress Cutter table,
+// e.g. "Rowling" becomes "R69"
+func Cutter(name string) string {
+	letters := []rune{}
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' {
+			letters = append(letters, r)
+		}
+	}
+	if len(letters) == 0 {
+		return ""
+	}
+
+	first := letters[0]
+	rest := letters[1:]
+	// The letters the second figure is read from; the third comes from the letter after them
+	used := 1
+	var digit int
+	switch {
+	case strings.ContainsRune("aeiou", first):
+		digit = tableDigit(rest, []string{"b", "d", "l", "n", "p", "r", "s", "u"}, 2)
+	case first == 's':
+		digit = tableDigit(rest, []string{"a", "ch", "e", "h", "m", "t", "u", "w"}, 2)
+		if strings.HasPrefix(string(rest), "ch") {
+			used = 2
+		}
+	case first == 'q' && len(rest) > 0 && rest[0] == 'u':
+		rest = rest[1:]
+		digit = tableDigit(rest, []string{"a", "e", "i", "o", "r", "t", "y"}, 3)
+	case first == 'q':
+		digit = 2
+	default:
+		digit = tableDigit(rest, []string{"a", "e", "i", "o", "r", "u", "y"}, 3)
+	}
+
+	cutter := fmt.Sprintf("%c%d", unicode.ToUpper(first), digit)
+	if len(rest) > used {
+		cutter += fmt.Sprint(tableDigit(rest[used:], []string{"a", "e", "i", "m", "p", "t", "w"}, 3))
+	}
+	return cutter
+}
+
+// MainEntry picks the word a cutter is taken from: the surname of the first author,
+// or the title without a leading article when there is no author
+func MainEntry(author, title string) string {
+	if author = strings.TrimSpace(author); author != "" {
+		// "Rowling, J. K." is already inverted; "J. K. Rowling" ends with the surname
+		if family, _, ok := strings.Cut(author, ","); ok {
+			return strings.TrimSpace(family)
+		}
+		fields := strings.Fields(author)
+		return fields[len(fields)-1]
+	}
+
+	fields := strings.Fields(title)
+	if len(fields) > 1 {
+		switch strings.ToLower(fields[0]) {
+		case "the", "a", "an":
+			fields = fields[1:]
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// tableDigit looks up the letters following the initial in one column of the Cutter table.
+// Entries are in alphabetical order and each covers the letters up to the next one.
+func tableDigit(letters []rune, entries []string, start int) int {
+	if len(letters) == 0 {
+		return start
+	}
+	s := string(letters)
+	digit := start
+	for i, entry := range entries {
+		if s >= entry {
+			digit = start + i
+		}
+	}
+	return digit
+}
+
+func deweyCutter(mainEntry string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(mainEntry) {
+		if r < 'A' || r > 'Z' {
+			continue
+		}
+		b.WriteRune(r)
+		if b.Len() == 3 {
+			break
+		}
+	}
+	return b.String()
+}
+
+func lcCallNumber(lc, mainEntry, year string) string {
+	if !lcCutter.MatchString(lc) {
+		if cutter := Cutter(mainEntry); cutter != "" {
+			lc = strings.TrimRight(lc, " .") + "." + cutter
+		}
+	}
+	if yearAtEnd.MatchString(lc) {
+		return lc
+	}
+	return joinParts(lc, year)
+}
+
+func joinParts(parts ...string) string {
+	nonEmpty := parts[:0:0]
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
+
+func writeTokens(b *strings.Builder, s string) {
+	for _, token := range keySplit.Split(s, -1) {
+		if token != "" {
+			b.WriteString(" ")
+			b.WriteString(token)
+		}
+	}
+}
+
+func leadingLetters(s string) string {
+	i := 0
+	for i < len(s) && i < 3 && s[i] >= 'A' && s[i] <= 'Z' {
+		i++
+	}
+	return s[:i]
+}
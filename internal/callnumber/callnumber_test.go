@@ -0,0 +1,214 @@
+package callnumber
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestShelfKeyOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme string
+		shelf  []string // Call numbers in shelf order
+	}{
+		{
+			name:   "dewey",
+			scheme: SchemeDewey,
+			shelf: []string{
+				"5.2 ABC",
+				"20",
+				"20.5",
+				"100 SMI",
+				"823.9",
+				"823.91 ABC",
+				"823/.914",
+				"823.914 ROW",
+				"823.914 ROW 2001",
+				"823.914 SMI",
+			},
+		},
+		{
+			name:   "lc",
+			scheme: SchemeLC,
+			shelf: []string{
+				"P35 .K5",
+				"PR6066.R34 C6 1990",
+				"PR6066.R4",
+				"Q76 .A1",
+				"Q345 .A1",
+				"QA9 .B2",
+				"QA76",
+				"QA76 .A1",
+				"QA76.5 .A1",
+				"QA76.73.G63 D66 2016",
+				"QA76.73.G63 D66 2020",
+				"QA76.73.P98 L88",
+				"QA76.9 .D3",
+				"qa345 .c1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 1; i < len(tt.shelf); i++ {
+				prev, next := ShelfKey(tt.scheme, tt.shelf[i-1]), ShelfKey(tt.scheme, tt.shelf[i])
+				if prev >= next {
+					t.Errorf("ShelfKey(%q) = %q, want it before ShelfKey(%q) = %q", tt.shelf[i-1], prev, tt.shelf[i], next)
+				}
+			}
+		})
+	}
+}
+
+func TestShelfKeyDeweyBeforeLC(t *testing.T) {
+	dewey := ShelfKey(SchemeDewey, "999.99 ZZZ")
+	lc := ShelfKey(SchemeLC, "A1 .A1")
+	if dewey >= lc {
+		t.Errorf("ShelfKey of Dewey %q, want it before LC %q", dewey, lc)
+	}
+}
+
+func TestShelfKeyNormalizes(t *testing.T) {
+	tests := []struct {
+		scheme string
+		a, b   string
+	}{
+		{SchemeDewey, "823/.914 row", "823.914 ROW"},
+		{SchemeDewey, "[823.914]", "823.914"},
+		{SchemeLC, "pr6066 .r34  c6", "PR6066.R34 C6"},
+	}
+
+	for _, tt := range tests {
+		if a, b := ShelfKey(tt.scheme, tt.a), ShelfKey(tt.scheme, tt.b); a != b {
+			t.Errorf("ShelfKey(%q) = %q, want it equal to ShelfKey(%q) = %q", tt.a, a, tt.b, b)
+		}
+	}
+}
+
+func TestCutter(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Rowling", "R69"},
+		{"Adams", "A33"},
+		{"Ibsen", "I27"},
+		{"Smith", "S65"},
+		{"Schmidt", "S36"},
+		{"Queen", "Q44"},
+		{"Qatar", "Q28"},
+		{"O'Brien", "O27"},
+		{"Li", "L5"},
+		{"X", "X3"},
+		{"", ""},
+		{"1984", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Cutter(tt.name); got != tt.want {
+			t.Errorf("Cutter(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMainEntry(t *testing.T) {
+	tests := []struct {
+		author, title string
+		want          string
+	}{
+		{"J. K. Rowling", "Harry Potter", "Rowling"},
+		{"Rowling, J. K.", "Harry Potter", "Rowling"},
+		{"", "The Hobbit", "Hobbit"},
+		{"", "An Introduction to Algorithms", "Introduction"},
+		{"", "A", "A"},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := MainEntry(tt.author, tt.title); got != tt.want {
+			t.Errorf("MainEntry(%q, %q) = %q, want %q", tt.author, tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name      string
+		preferred string
+		in        Input
+		want      CallNumber
+		ok        bool
+	}{
+		{
+			name:      "dewey with cutter and year",
+			preferred: SchemeDewey,
+			in:        Input{Dewey: "823/.914", LC: "PR6068", MainEntry: "Rowling", Year: "1997"},
+			want:      CallNumber{Scheme: SchemeDewey, Value: "823.914 ROW 1997"},
+			ok:        true,
+		},
+		{
+			name:      "lc adds a cutter",
+			preferred: SchemeLC,
+			in:        Input{Dewey: "823.914", LC: "pr6068", MainEntry: "Rowling", Year: "1997"},
+			want:      CallNumber{Scheme: SchemeLC, Value: "PR6068.R69 1997"},
+			ok:        true,
+		},
+		{
+			name:      "lc keeps its own cutter and year",
+			preferred: SchemeLC,
+			in:        Input{LC: "PR6066.R34 C6 1990", MainEntry: "Rowling", Year: "1997"},
+			want:      CallNumber{Scheme: SchemeLC, Value: "PR6066.R34 C6 1990"},
+			ok:        true,
+		},
+		{
+			name:      "falls back to the other scheme",
+			preferred: SchemeLC,
+			in:        Input{Dewey: "823.914", MainEntry: "Rowling"},
+			want:      CallNumber{Scheme: SchemeDewey, Value: "823.914 ROW"},
+			ok:        true,
+		},
+		{
+			name:      "no classification",
+			preferred: SchemeDewey,
+			in:        Input{MainEntry: "Rowling", Year: "1997"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Build(tt.preferred, tt.in)
+			if ok != tt.ok {
+				t.Fatalf("Build ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if got.Scheme != tt.want.Scheme || got.Value != tt.want.Value {
+				t.Errorf("Build = %s %q, want %s %q", got.Scheme, got.Value, tt.want.Scheme, tt.want.Value)
+			}
+			if key := ShelfKey(got.Scheme, got.Value); got.Key != key {
+				t.Errorf("Build key = %q, want %q", got.Key, key)
+			}
+		})
+	}
+}
+
+func TestCopies(t *testing.T) {
+	tests := []struct {
+		callNumber string
+		total      int
+		want       []string
+	}{
+		{"823.914 ROW", 1, []string{"823.914 ROW"}},
+		{"823.914 ROW", 3, []string{"823.914 ROW c.1", "823.914 ROW c.2", "823.914 ROW c.3"}},
+		{"823.914 ROW", 0, []string{}},
+		{"", 2, []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := Copies(tt.callNumber, tt.total); !slices.Equal(got, tt.want) {
+			t.Errorf("Copies(%q, %d) = %q, want %q", tt.callNumber, tt.total, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package citation
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Supported citation formats
+const (
+	FormatBibTeX  = "bibtex"
+	FormatRIS     = "ris"
+	FormatCSLJSON = "csljson"
+	FormatAPA     = "apa"
+	FormatMLA     = "mla"
+)
+
+// ErrUnsupportedFormat is returned for unknown citation formats
+var ErrUnsupportedFormat = errors.New("unsupported citation format")
+
+// Name is a personal name split into family and given parts
+type Name struct {
+	Family string `json:"family,omitempty"`
+	Given  string `json:"given,omitempty"`
+}
+
+// Work holds the bibliographic data needed to cite a book
+type Work struct {
+	ID        string
+	Title     string
+	Authors   []Name
+	Publisher string
+	Year      string
+	ISBN      string
+	Language  string
+	PageCount int32
+}
+
+// Render formats the works in the requested citation format
+func Render(format string, works []Work) ([]byte, error) {
+	switch format {
+	case FormatBibTeX:
+		return []byte(renderEach(works, BibTeX, "\n")), nil
+	case FormatRIS:
+		return []byte(renderEach(works, RIS, "")), nil
+	case FormatCSLJSON:
+		return CSLJSON(works)
+	case FormatAPA:
+		return []byte(renderEach(works, APA, "\n")), nil
+	case FormatMLA:
+		return []byte(renderEach(works, MLA, "\n")), nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ContentType returns the media type for a citation format
+func ContentType(format string) string {
+	switch format {
+	case FormatBibTeX:
+		return "application/x-bibtex; charset=utf-8"
+	case FormatRIS:
+		return "application/x-research-info-systems; charset=utf-8"
+	case FormatCSLJSON:
+		return "application/vnd.citationstyles.csl+json; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// IsSupported reports whether format is a known citation format
+func IsSupported(format string) bool {
+	switch format {
+	case FormatBibTeX, FormatRIS, FormatCSLJSON, FormatAPA, FormatMLA:
+		return true
+	}
+	return false
+}
+
+func renderEach(works []Work, render func(Work) string, separator string) string {
+	parts := make([]string, len(works))
+	for i, w := range works {
+		parts[i] = render(w)
+	}
+	return strings.Join(parts, separator)
+}
+
+// ParseName splits a stored author name into family and given parts.
+// Both "Tolkien, J. R. R." and "J. R. R. Tolkien" are understood.
+func ParseName(name string) Name {
+	name = strings.TrimSpace(name)
+	if family, given, ok := strings.Cut(name, ","); ok {
+		return Name{Family: strings.TrimSpace(family), Given: strings.TrimSpace(given)}
+	}
+
+	parts := strings.Fields(name)
+	if len(parts) <= 1 {
+		return Name{Family: name}
+	}
+	return Name{
+		Family: parts[len(parts)-1],
+		Given:  strings.Join(parts[:len(parts)-1], " "),
+	}
+}
+
+// Inverted returns the name as "Family, Given"
+func (n Name) Inverted() string {
+	if n.Given == "" {
+		return n.Family
+	}
+	return n.Family + ", " + n.Given
+}
+
+// Natural returns the name as "Given Family"
+func (n Name) Natural() string {
+	if n.Given == "" {
+		return n.Family
+	}
+	return n.Given + " " + n.Family
+}
+
+// Initials abbreviates the given names, e.g. "John Ronald Reuel" becomes "J. R. R."
+func (n Name) Initials() string {
+	var initials []string
+	for _, part := range strings.FieldsFunc(n.Given, func(r rune) bool { return r == ' ' || r == '.' }) {
+		for _, r := range part {
+			initials = append(initials, string(unicode.ToUpper(r))+".")
+			break
+		}
+	}
+	return strings.Join(initials, " ")
+}
+
+// ExtractYear finds the first four digit year in a free-text publication date
+func ExtractYear(date string) string {
+	digits := 0
+	for i, r := range date {
+		if r >= '0' && r <= '9' {
+			digits++
+			if digits == 4 {
+				return date[i-3 : i+1]
+			}
+			continue
+		}
+		digits = 0
+	}
+	return ""
+}
+
+// ensurePeriod terminates a sentence-like fragment with a period
+func ensurePeriod(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.HasSuffix(s, ".") || strings.HasSuffix(s, "?") || strings.HasSuffix(s, "!") {
+		return s
+	}
+	return s + "."
+}
@@ -0,0 +1,125 @@
+package citation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Name
+	}{
+		{"Tolkien, J. R. R.", Name{Family: "Tolkien", Given: "J. R. R."}},
+		{"J. R. R. Tolkien", Name{Family: "Tolkien", Given: "J. R. R."}},
+		{"  Le Guin,  Ursula K. ", Name{Family: "Le Guin", Given: "Ursula K."}},
+		{"Homer", Name{Family: "Homer"}},
+		{"", Name{}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseName(tt.input); got != tt.want {
+			t.Errorf("ParseName(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNameForms(t *testing.T) {
+	tests := []struct {
+		name                        Name
+		inverted, natural, initials string
+	}{
+		{Name{Family: "Tolkien", Given: "John Ronald Reuel"}, "Tolkien, John Ronald Reuel", "John Ronald Reuel Tolkien", "J. R. R."},
+		{Name{Family: "Tolkien", Given: "J.R.R."}, "Tolkien, J.R.R.", "J.R.R. Tolkien", "J. R. R."},
+		{Name{Family: "Brontë", Given: "émile"}, "Brontë, émile", "émile Brontë", "É."},
+		{Name{Family: "Homer"}, "Homer", "Homer", ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.name.Inverted(); got != tt.inverted {
+			t.Errorf("%+v Inverted() = %q, want %q", tt.name, got, tt.inverted)
+		}
+		if got := tt.name.Natural(); got != tt.natural {
+			t.Errorf("%+v Natural() = %q, want %q", tt.name, got, tt.natural)
+		}
+		if got := tt.name.Initials(); got != tt.initials {
+			t.Errorf("%+v Initials() = %q, want %q", tt.name, got, tt.initials)
+		}
+	}
+}
+
+func TestExtractYear(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"1997", "1997"},
+		{"c1997.", "1997"},
+		{"[1954?]", "1954"},
+		{"March 3, 2001", "2001"},
+		{"12 2020", "2020"},
+		{"n.d.", ""},
+		{"199", ""},
+		{"١٩٩٧", ""}, // Arabic-Indic digits are not years
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ExtractYear(tt.date); got != tt.want {
+			t.Errorf("ExtractYear(%q) = %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	hobbit := Work{
+		ID:        "hobbit",
+		Title:     "The Hobbit",
+		Authors:   []Name{{Family: "Tolkien", Given: "J. R. R."}},
+		Publisher: "Allen & Unwin",
+		Year:      "1937",
+	}
+	omens := Work{
+		Title:     "Good Omens",
+		Authors:   []Name{{Family: "Pratchett", Given: "Terry"}, {Family: "Gaiman", Given: "Neil"}},
+		Publisher: "Gollancz",
+		Year:      "1990",
+	}
+	sicp := Work{
+		Title:   "Structure and Interpretation of Computer Programs",
+		Authors: []Name{{Family: "Abelson", Given: "Harold"}, {Family: "Sussman", Given: "Gerald Jay"}, {Family: "Sussman", Given: "Julie"}},
+	}
+
+	tests := []struct {
+		name   string
+		render func(Work) string
+		work   Work
+		want   string
+	}{
+		{"apa one author", APA, hobbit, "Tolkien, J. R. R. (1937). The Hobbit. Allen & Unwin."},
+		{"apa two authors", APA, omens, "Pratchett, T., & Gaiman, N. (1990). Good Omens. Gollancz."},
+		{"apa three authors without year", APA, sicp, "Abelson, H., Sussman, G. J., & Sussman, J. (n.d.). Structure and Interpretation of Computer Programs."},
+		{"mla one author", MLA, hobbit, "Tolkien, J. R. R. The Hobbit. Allen & Unwin, 1937."},
+		{"mla two authors", MLA, omens, "Pratchett, Terry, and Neil Gaiman. Good Omens. Gollancz, 1990."},
+		{"mla three authors", MLA, sicp, "Abelson, Harold, et al. Structure and Interpretation of Computer Programs."},
+		{"bibtex", BibTeX, hobbit, "@book{tolkien1937hobbit,\n  title = {The Hobbit},\n  author = {Tolkien, J. R. R.},\n  publisher = {Allen \\& Unwin},\n  year = {1937},\n}\n"},
+		{"ris", RIS, hobbit, "TY  - BOOK\nID  - hobbit\nTI  - The Hobbit\nAU  - Tolkien, J. R. R.\nPB  - Allen & Unwin\nPY  - 1937\nER  - \n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.render(tt.work); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderUnsupported(t *testing.T) {
+	if IsSupported("chicago") {
+		t.Error("IsSupported(\"chicago\") = true, want false")
+	}
+	if _, err := Render("chicago", nil); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Render error = %v, want %v", err, ErrUnsupportedFormat)
+	}
+}
@@ -0,0 +1,225 @@
+package citation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// BibTeX renders a work as a BibTeX @book entry
+func BibTeX(w Work) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "@book{%s,\n", bibtexKey(w))
+	writeBibtexField(&b, "title", w.Title)
+	if len(w.Authors) > 0 {
+		names := make([]string, len(w.Authors))
+		for i, a := range w.Authors {
+			names[i] = a.Inverted()
+		}
+		writeBibtexField(&b, "author", strings.Join(names, " and "))
+	}
+	writeBibtexField(&b, "publisher", w.Publisher)
+	writeBibtexField(&b, "year", w.Year)
+	writeBibtexField(&b, "isbn", w.ISBN)
+	writeBibtexField(&b, "language", w.Language)
+	if w.PageCount > 0 {
+		writeBibtexField(&b, "pagetotal", strconv.Itoa(int(w.PageCount)))
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func writeBibtexField(b *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, "{", `\{`, "}", `\}`, "&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`)
+	fmt.Fprintf(b, "  %s = {%s},\n", name, replacer.Replace(value))
+}
+
+// bibtexKey builds a citation key such as tolkien1954fellowship
+func bibtexKey(w Work) string {
+	var key strings.Builder
+	if len(w.Authors) > 0 {
+		key.WriteString(keyPart(w.Authors[0].Family))
+	}
+	key.WriteString(w.Year)
+	for _, word := range strings.Fields(w.Title) {
+		lower := strings.ToLower(word)
+		if lower == "a" || lower == "an" || lower == "the" {
+			continue
+		}
+		key.WriteString(keyPart(word))
+		break
+	}
+	if key.Len() == 0 {
+		return w.ID
+	}
+	return key.String()
+}
+
+func keyPart(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// RIS renders a work as an RIS record
+func RIS(w Work) string {
+	var b strings.Builder
+
+	writeRISTag(&b, "TY", "BOOK")
+	writeRISTag(&b, "ID", w.ID)
+	writeRISTag(&b, "TI", w.Title)
+	for _, a := range w.Authors {
+		writeRISTag(&b, "AU", a.Inverted())
+	}
+	writeRISTag(&b, "PB", w.Publisher)
+	writeRISTag(&b, "PY", w.Year)
+	writeRISTag(&b, "SN", w.ISBN)
+	writeRISTag(&b, "LA", w.Language)
+	if w.PageCount > 0 {
+		writeRISTag(&b, "SP", strconv.Itoa(int(w.PageCount)))
+	}
+	b.WriteString("ER  - \n")
+
+	return b.String()
+}
+
+func writeRISTag(b *strings.Builder, tag, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "%s  - %s\n", tag, value)
+}
+
+// cslItem is a single item in the CSL-JSON data model
+type cslItem struct {
+	ID            string   `json:"id"`
+	Type          string   `json:"type"`
+	Title         string   `json:"title"`
+	Author        []Name   `json:"author,omitempty"`
+	Publisher     string   `json:"publisher,omitempty"`
+	Issued        *cslDate `json:"issued,omitempty"`
+	ISBN          string   `json:"ISBN,omitempty"`
+	Language      string   `json:"language,omitempty"`
+	NumberOfPages string   `json:"number-of-pages,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// CSLJSON renders the works as a CSL-JSON array
+func CSLJSON(works []Work) ([]byte, error) {
+	items := make([]cslItem, len(works))
+	for i, w := range works {
+		item := cslItem{
+			ID:        w.ID,
+			Type:      "book",
+			Title:     w.Title,
+			Author:    w.Authors,
+			Publisher: w.Publisher,
+			ISBN:      w.ISBN,
+			Language:  w.Language,
+		}
+		if year, err := strconv.Atoi(w.Year); err == nil {
+			item.Issued = &cslDate{DateParts: [][]int{{year}}}
+		}
+		if w.PageCount > 0 {
+			item.NumberOfPages = strconv.Itoa(int(w.PageCount))
+		}
+		items[i] = item
+	}
+	return json.MarshalIndent(items, "", "  ")
+}
+
+// APA renders a work in APA 7th edition reference style
+func APA(w Work) string {
+	var parts []string
+
+	if authors := apaAuthors(w.Authors); authors != "" {
+		parts = append(parts, authors)
+	}
+
+	year := w.Year
+	if year == "" {
+		year = "n.d."
+	}
+	parts = append(parts, "("+year+").")
+	parts = append(parts, ensurePeriod(w.Title))
+	if w.Publisher != "" {
+		parts = append(parts, ensurePeriod(w.Publisher))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func apaAuthors(authors []Name) string {
+	names := make([]string, len(authors))
+	for i, a := range authors {
+		names[i] = a.Family
+		if initials := a.Initials(); initials != "" {
+			names[i] += ", " + initials
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return ensurePeriod(names[0])
+	case 2:
+		return ensurePeriod(names[0] + ", & " + names[1])
+	default:
+		// APA lists up to 20 authors before eliding with an ellipsis
+		if len(names) > 20 {
+			names = append(names[:19], "... "+names[len(names)-1])
+			return ensurePeriod(strings.Join(names, ", "))
+		}
+		return ensurePeriod(strings.Join(names[:len(names)-1], ", ") + ", & " + names[len(names)-1])
+	}
+}
+
+// MLA renders a work in MLA 9th edition works-cited style
+func MLA(w Work) string {
+	var parts []string
+
+	if authors := mlaAuthors(w.Authors); authors != "" {
+		parts = append(parts, authors)
+	}
+	parts = append(parts, ensurePeriod(w.Title))
+
+	var publication []string
+	if w.Publisher != "" {
+		publication = append(publication, w.Publisher)
+	}
+	if w.Year != "" {
+		publication = append(publication, w.Year)
+	}
+	if len(publication) > 0 {
+		parts = append(parts, ensurePeriod(strings.Join(publication, ", ")))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func mlaAuthors(authors []Name) string {
+	switch len(authors) {
+	case 0:
+		return ""
+	case 1:
+		return ensurePeriod(authors[0].Inverted())
+	case 2:
+		return ensurePeriod(authors[0].Inverted() + ", and " + authors[1].Natural())
+	default:
+		return ensurePeriod(authors[0].Inverted() + ", et al")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -18,6 +20,73 @@ type Config struct {
 	DBName      string
 	DBSSLMode   string
 	Environment string
+	LogLevel    string // debug, info, warn or error; debug also logs every database query
+
+	// An admin with these credentials is created at startup if the username is not taken, so a new
+	// installation has someone who can sign in; unset to skip
+	AdminUsername string
+	AdminEmail    string
+	AdminPassword string
+
+	// Prometheus metrics are served on this address, apart from the API, so they are not public;
+	// empty disables them
+	MetricsAddr string
+
+	// Tracing exporter: "none", "stdout", or "otlp", configured by the standard OTEL_EXPORTER_OTLP_*
+	// variables
+	TracingExporter string
+
+	// Cover image storage
+	CoverStorage     string // "local" or "s3"
+	CoverStoragePath string
+	S3Endpoint       string
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+
+	// Call numbers are built in this scheme, "dewey" or "lc", when a book has both classifications
+	CallNumberScheme string
+
+	// Review moderation
+	ReviewBlockedWords    []string // Words and phrases that hold a review for moderation
+	ReviewRejectionLimit  int      // Rejected reviews within the window before a member may not post; 0 disables
+	ReviewRejectionWindow time.Duration
+
+	// Recommendations are recomputed in the background this often; 0 disables the job
+	RecommendationRefreshInterval time.Duration
+
+	// Email notifications; the defaults suit a local MailHog
+	SMTPHost                string
+	SMTPPort                string
+	SMTPUsername            string
+	SMTPPassword            string
+	MailFrom                string
+	DueReminderDays         int           // Days before the due date to remind members who have not chosen their own
+	NotificationInterval    time.Duration // How often loans and holds are checked for emails to send; 0 disables the job
+	NotificationMaxAttempts int           // Delivery attempts before a notification is marked failed
+
+	// Loans
+	OverdueCheckInterval time.Duration // How often loans past their due date are marked overdue; 0 disables the job
+
+	// Recorded domain events are relayed at least this often, and straight away when Postgres notifications
+	// arrive; 0 disables relaying
+	OutboxPollInterval time.Duration
+
+	// Live event streams read recorded events at least this often, and straight away when Postgres
+	// notifications arrive, whichever instance relays them; 0 disables event streams
+	EventStreamPollInterval time.Duration
+	EventStreamBuffer       int // Recent events kept for live event streams resuming with Last-Event-ID
+
+	// Webhooks
+	WebhookPollInterval time.Duration // How often deliveries due for a retry are sent; 0 disables delivery
+	WebhookMaxAttempts  int           // Delivery attempts before a webhook delivery is marked failed
+	WebhookTimeout      time.Duration
+
+	// Health checks
+	ReadinessTimeout          time.Duration // Time allowed for the readiness checks together
+	ReadinessCheckOpenLibrary bool          // Whether readiness reports degraded while Open Library is unreachable
+	ShutdownDrainDelay        time.Duration // How long the server reports not ready before it stops taking requests
 }
 
 // Load loads configuration from environment variables
@@ -34,6 +103,54 @@ func Load() (*Config, error) {
 		DBName:      getEnv("DB_NAME", "bookbridgeDB"),
 		DBSSLMode:   getEnv("DB_SSLMODE", "disable"),
 		Environment: getEnv("ENVIRONMENT", "development"),
+		LogLevel:    getEnv("LOG_LEVEL", "info"),
+
+		AdminUsername: getEnv("ADMIN_USERNAME", ""),
+		AdminEmail:    getEnv("ADMIN_EMAIL", ""),
+		AdminPassword: getEnv("ADMIN_PASSWORD", ""),
+
+		MetricsAddr:     getEnv("METRICS_ADDR", ":9091"),
+		TracingExporter: getEnv("TRACING_EXPORTER", "none"),
+
+		CoverStorage:     getEnv("COVER_STORAGE", "local"),
+		CoverStoragePath: getEnv("COVER_STORAGE_PATH", "./data/covers"),
+		S3Endpoint:       getEnv("S3_ENDPOINT", ""),
+		S3Region:         getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:         getEnv("S3_BUCKET", ""),
+		S3AccessKey:      getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:      getEnv("S3_SECRET_KEY", ""),
+
+		CallNumberScheme: getEnv("CALL_NUMBER_SCHEME", "dewey"),
+
+		ReviewBlockedWords:    getEnvAsList("REVIEW_BLOCKED_WORDS", nil),
+		ReviewRejectionLimit:  getEnvAsInt("REVIEW_REJECTION_LIMIT", 3),
+		ReviewRejectionWindow: time.Duration(getEnvAsInt("REVIEW_REJECTION_WINDOW_DAYS", 30)) * 24 * time.Hour,
+
+		RecommendationRefreshInterval: time.Duration(getEnvAsInt("RECOMMENDATION_REFRESH_MINUTES", 60)) * time.Minute,
+
+		SMTPHost:                getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:                getEnv("SMTP_PORT", "1025"),
+		SMTPUsername:            getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:            getEnv("SMTP_PASSWORD", ""),
+		MailFrom:                getEnv("MAIL_FROM", "BookBridge Library <library@bookbridge.local>"),
+		DueReminderDays:         getEnvAsInt("DUE_REMINDER_DAYS", 3),
+		NotificationInterval:    time.Duration(getEnvAsInt("NOTIFICATION_INTERVAL_MINUTES", 5)) * time.Minute,
+		NotificationMaxAttempts: getEnvAsInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+
+		OverdueCheckInterval: time.Duration(getEnvAsInt("OVERDUE_CHECK_MINUTES", 60)) * time.Minute,
+
+		OutboxPollInterval: time.Duration(getEnvAsInt("OUTBOX_POLL_SECONDS", 10)) * time.Second,
+
+		EventStreamPollInterval: time.Duration(getEnvAsInt("EVENT_STREAM_POLL_SECONDS", 5)) * time.Second,
+		EventStreamBuffer:       getEnvAsInt("EVENT_STREAM_BUFFER", 500),
+
+		WebhookPollInterval: time.Duration(getEnvAsInt("WEBHOOK_POLL_SECONDS", 30)) * time.Second,
+		WebhookMaxAttempts:  getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookTimeout:      time.Duration(getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second,
+
+		ReadinessTimeout:          time.Duration(getEnvAsInt("READINESS_TIMEOUT_SECONDS", 2)) * time.Second,
+		ReadinessCheckOpenLibrary: getEnvAsBool("READINESS_CHECK_OPENLIBRARY", false),
+		ShutdownDrainDelay:        time.Duration(getEnvAsInt("SHUTDOWN_DRAIN_SECONDS", 5)) * time.Second,
 	}
 
 	return config, nil
@@ -72,3 +189,18 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return val
 }
+
+// getEnvAsList retrieves a comma-separated environment variable as a list, dropping empty entries
+func getEnvAsList(key string, defaultValue []string) []string {
+	valStr, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(valStr, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
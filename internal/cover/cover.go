@@ -0,0 +1,147 @@
+package cover
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register GIF decoder
+	"image/jpeg"
+	_ "image/png" // register PNG decoder
+)
+
+// Size is a named cover rendition
+type Size string
+
+// Available cover sizes, named after Open Library's S/M/L covers
+const (
+	SizeSmall  Size = "small"
+	SizeMedium Size = "medium"
+	SizeLarge  Size = "large"
+)
+
+// Sizes lists every rendition generated for a cover
+var Sizes = []Size{SizeSmall, SizeMedium, SizeLarge}
+
+// maxWidths is the width each rendition is scaled down to
+var maxWidths = map[Size]int{
+	SizeSmall:  100,
+	SizeMedium: 300,
+	SizeLarge:  600,
+}
+
+// ContentType is the media type of every generated rendition
+const ContentType = "image/jpeg"
+
+const jpegQuality = 85
+
+// MaxPixels caps the width times height of an image Process will decode, since a small compressed
+// file can declare dimensions that take gigabytes to decode
+const MaxPixels = 25_000_000
+
+var (
+	// ErrUnsupportedImage is returned when the input cannot be decoded as JPEG, PNG or GIF
+	ErrUnsupportedImage = errors.New("unsupported image format")
+	// ErrImageTooLarge is returned when the image's dimensions exceed MaxPixels
+	ErrImageTooLarge = errors.New("image dimensions too large")
+)
+
+// ParseSize validates a size name
+func ParseSize(s string) (Size, bool) {
+	size := Size(s)
+	_, ok := maxWidths[size]
+	return size, ok
+}
+
+// Processed holds the renditions generated from an original image
+type Processed struct {
+	ETag       string
+	Width      int
+	Height     int
+	Renditions map[Size][]byte
+}
+
+// Process decodes an image and encodes a JPEG rendition for every size. The dimensions are checked
+// against MaxPixels before the image is decoded.
+func Process(data []byte) (*Processed, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width > MaxPixels/config.Height {
+		return nil, fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrImageTooLarge, config.Width, config.Height, MaxPixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	sum := sha256.Sum256(data)
+	bounds := src.Bounds()
+	p := &Processed{
+		ETag:       hex.EncodeToString(sum[:16]),
+		Width:      bounds.Dx(),
+		Height:     bounds.Dy(),
+		Renditions: make(map[Size][]byte, len(Sizes)),
+	}
+
+	for _, size := range Sizes {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resize(src, maxWidths[size]), &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, fmt.Errorf("failed to encode %s cover: %w", size, err)
+		}
+		p.Renditions[size] = buf.Bytes()
+	}
+
+	return p, nil
+}
+
+// resize scales src down to maxWidth keeping its aspect ratio, averaging the
+// source pixels that fall into each destination pixel. Smaller images are left as is.
+func resize(src image.Image, maxWidth int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW <= maxWidth || srcW == 0 {
+		return src
+	}
+
+	dstW := maxWidth
+	dstH := srcH * maxWidth / srcW
+	if dstH < 1 {
+		dstH = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < dstH; y++ {
+		y0 := bounds.Min.Y + y*srcH/dstH
+		y1 := bounds.Min.Y + (y+1)*srcH/dstH
+		for x := 0; x < dstW; x++ {
+			x0 := bounds.Min.X + x*srcW/dstW
+			x1 := bounds.Min.X + (x+1)*srcW/dstW
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return dst
+}
@@ -3,9 +3,12 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/tracelog"
 )
 
 // DB represents the database connection pool
@@ -13,11 +16,25 @@ type DB struct {
 	Pool *pgxpool.Pool
 }
 
-// New creates a new database connection
-func New(connString string) (*DB, error) {
+// New creates a new database connection. Queries are logged to logger at debug level, and failed
+// queries at error level, with the request ID of the context they ran with. Each query is traced
+// in a span named after it.
+func New(connString string, logger *slog.Logger) (*DB, error) {
 	ctx := context.Background()
 
-	pool, err := pgxpool.New(ctx, connString)
+	config, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse connection string: %v", err)
+	}
+	config.ConnConfig.Tracer = multitracer.New(
+		&tracelog.TraceLog{
+			Logger:   queryLogger(logger),
+			LogLevel: queryLogLevel(ctx, logger),
+		},
+		newSpanTracer(),
+	)
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create connection pool: %v", err)
 	}
@@ -27,13 +44,74 @@ func New(connString string) (*DB, error) {
 		return nil, fmt.Errorf("unable to ping database: %v", err)
 	}
 
-	log.Println("Connected to the database successfully")
+	logger.Info("Connected to the database successfully")
 	return &DB{Pool: pool}, nil
 }
 
+// queryLogger adapts logger for pgx. pgx logs every query at info level, which is logged as debug.
+func queryLogger(logger *slog.Logger) tracelog.Logger {
+	return tracelog.LoggerFunc(func(ctx context.Context, level tracelog.LogLevel, msg string, data map[string]any) {
+		attrs := make([]slog.Attr, 0, len(data))
+		for k, v := range data {
+			attrs = append(attrs, slog.Any(k, v))
+		}
+
+		slogLevel := slog.LevelDebug
+		switch level {
+		case tracelog.LogLevelWarn:
+			slogLevel = slog.LevelWarn
+		case tracelog.LogLevelError:
+			slogLevel = slog.LevelError
+		}
+		logger.LogAttrs(ctx, slogLevel, msg, attrs...)
+	})
+}
+
+// queryLogLevel traces every query only when logger would log them, and otherwise only failures
+func queryLogLevel(ctx context.Context, logger *slog.Logger) tracelog.LogLevel {
+	if logger.Enabled(ctx, slog.LevelDebug) {
+		return tracelog.LogLevelInfo
+	}
+	return tracelog.LogLevelError
+}
+
 // Close closes the database connection
 func (db *DB) Close() {
 	if db.Pool != nil {
 		db.Pool.Close()
 	}
 }
+
+// WithTx runs fn inside a transaction, committing on success and rolling back on error
+func (db *DB) WithTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// MigrationVersion returns the version of the newest migration goose has applied and not rolled back
+func (db *DB) MigrationVersion(ctx context.Context) (int64, error) {
+	var version int64
+	err := db.Pool.QueryRow(ctx, `
+		SELECT COALESCE(MAX(version_id), 0) FROM (
+		  SELECT DISTINCT ON (version_id) version_id, is_applied
+		  FROM goose_db_version
+		  ORDER BY version_id, id DESC
+		) latest
+		WHERE is_applied`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read migration version: %w", err)
+	}
+	return version, nil
+}
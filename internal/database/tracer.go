@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryName matches the name sqlc gives each query in a comment at the start of its SQL
+var queryName = regexp.MustCompile(`^-- name: (\w+)`)
+
+// spanTracer traces each query in a span named after its sqlc query
+type spanTracer struct {
+	tracer trace.Tracer
+}
+
+func newSpanTracer() *spanTracer {
+	return &spanTracer{
+		tracer: otel.Tracer("github.com/vasujain275/bookbridge-api/internal/database"),
+	}
+}
+
+func (t *spanTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name := "query"
+	if m := queryName.FindStringSubmatch(data.SQL); m != nil {
+		name = m[1]
+	}
+	ctx, _ = t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBQueryText(data.SQL),
+			attribute.String("db.operation.name", name),
+		),
+	)
+	return ctx
+}
+
+func (t *spanTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	// No rows is an expected outcome, such as a lookup of something that does not exist
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// AuditHandler handles HTTP requests for the audit log.
+type AuditHandler struct {
+	service service.AuditService
+}
+
+// NewAuditHandler creates a new AuditHandler.
+func NewAuditHandler(s service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		service: s,
+	}
+}
+
+// ListAuditEntries godoc
+// @Summary List audit log entries
+// @Description Get a paginated list of recorded changes, newest first, each with the actor, action, entity, the changed fields before and after, request ID and IP. Filters are optional; from and to take a date (YYYY-MM-DD), to including the whole day, or an RFC 3339 time. Requires an admin.
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param entity query string false "Entity type, e.g. users or books"
+// @Param entity_id query string false "Entity ID"
+// @Param actor query string false "Actor's user ID or username"
+// @Param from query string false "Earliest change"
+// @Param to query string false "Latest change"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Audit entries retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /audit [get]
+func (h *AuditHandler) ListAuditEntries(c *gin.Context) {
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	from, err := parseAuditTime(c.Query("from"), false)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid from parameter", err.Error())
+		return
+	}
+	to, err := parseAuditTime(c.Query("to"), true)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid to parameter", err.Error())
+		return
+	}
+
+	entries, err := h.service.List(c.Request.Context(), service.AuditFilter{
+		EntityType: c.Query("entity"),
+		EntityID:   c.Query("entity_id"),
+		Actor:      c.Query("actor"),
+		From:       from,
+		To:         to,
+	}, limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Audit entries retrieved successfully", entries)
+}
+
+// parseAuditTime parses an RFC 3339 time or a date. A date that ends a range is taken as the end of
+// that day, so the range includes it.
+func parseAuditTime(s string, end bool) (*time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		t = t.UTC()
+		return &t, nil
+	}
+	t, err := parseOptionalDate(s)
+	if err != nil || t == nil || !end {
+		return t, err
+	}
+	next := t.AddDate(0, 0, 1)
+	return &next, nil
+}
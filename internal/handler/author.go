@@ -0,0 +1,398 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// AuthorHandler handles HTTP requests for authors.
+type AuthorHandler struct {
+	service service.AuthorService
+}
+
+// NewAuthorHandler creates a new AuthorHandler.
+func NewAuthorHandler(s service.AuthorService) *AuthorHandler {
+	return &AuthorHandler{
+		service: s,
+	}
+}
+
+// AuthorRequest represents the expected request payload for creating or updating an author.
+// Dates use the YYYY-MM-DD format.
+type AuthorRequest struct {
+	Name           string               `json:"name" binding:"required"`
+	Bio            string               `json:"bio"`
+	OpenLibraryKey string               `json:"openlibrary_key" example:"/authors/OL23919A"`
+	Photos         []int                `json:"photos"`
+	AlternateNames []string             `json:"alternate_names"`
+	PersonalName   string               `json:"personal_name"`
+	Links          []service.AuthorLink `json:"links"`
+	BirthDate      string               `json:"birth_date" example:"1965-07-31"`
+	DeathDate      string               `json:"death_date"`
+}
+
+// BookAuthorRequest represents the expected request payload for attaching an author to a book.
+type BookAuthorRequest struct {
+	AuthorID uuid.UUID `json:"author_id" binding:"required"`
+}
+
+func (r AuthorRequest) params() (service.AuthorParams, error) {
+	birthDate, err := parseOptionalDate(r.BirthDate)
+	if err != nil {
+		return service.AuthorParams{}, fmt.Errorf("invalid birth_date: %w", err)
+	}
+	deathDate, err := parseOptionalDate(r.DeathDate)
+	if err != nil {
+		return service.AuthorParams{}, fmt.Errorf("invalid death_date: %w", err)
+	}
+
+	return service.AuthorParams{
+		Name:           r.Name,
+		Bio:            r.Bio,
+		OpenLibraryKey: r.OpenLibraryKey,
+		Photos:         r.Photos,
+		AlternateNames: r.AlternateNames,
+		PersonalName:   r.PersonalName,
+		Links:          r.Links,
+		BirthDate:      birthDate,
+		DeathDate:      deathDate,
+	}, nil
+}
+
+// GetAuthor godoc
+// @Summary Get author by ID
+// @Description Get an author by its ID.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Author ID"
+// @Success 200 {object} util.Response "Author found"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 301 {object} util.Response "Author was merged into another"
+// @Failure 404 {object} util.Response "Author not found"
+// @Router /authors/{id} [get]
+func (h *AuthorHandler) GetAuthor(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid author ID", err.Error())
+		return
+	}
+
+	author, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if redirectMerged(c, err) {
+			return
+		}
+		util.SendNotFound(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Author found", author)
+}
+
+// ListAuthors godoc
+// @Summary List authors
+// @Description Get a paginated list of authors, ordered by name.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Authors retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /authors [get]
+func (h *AuthorHandler) ListAuthors(c *gin.Context) {
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	authors, err := h.service.List(c.Request.Context(), limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Authors retrieved successfully", authors)
+}
+
+// CreateAuthor godoc
+// @Summary Create an author
+// @Description Create a new author. Requires an admin.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param author body AuthorRequest true "Author data"
+// @Success 201 {object} util.Response "Author created successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 409 {object} util.Response "Author already exists"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /authors [post]
+func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
+	var req AuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+	params, err := req.params()
+	if err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	author, err := h.service.Create(c.Request.Context(), params)
+	if err != nil {
+		if errors.Is(err, service.ErrAuthorExists) {
+			util.SendError(c, http.StatusConflict, "Author already exists", err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendCreated(c, "Author created successfully", author)
+}
+
+// UpdateAuthor godoc
+// @Summary Update an author
+// @Description Replace an author's details. Requires an admin.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Author ID"
+// @Param author body AuthorRequest true "Author data"
+// @Success 200 {object} util.Response "Author updated successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Author not found"
+// @Failure 409 {object} util.Response "Author already exists"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /authors/{id} [put]
+func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid author ID", err.Error())
+		return
+	}
+
+	var req AuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+	params, err := req.params()
+	if err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	author, err := h.service.Update(c.Request.Context(), id, params)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAuthorExists):
+			util.SendError(c, http.StatusConflict, "Author already exists", err.Error())
+		case errors.Is(err, pgx.ErrNoRows):
+			util.SendNotFound(c, err.Error())
+		default:
+			util.SendInternalServerError(c, err.Error())
+		}
+		return
+	}
+	util.SendOK(c, "Author updated successfully", author)
+}
+
+// DeleteAuthor godoc
+// @Summary Delete an author
+// @Description Delete an author and detach them from their books. Requires an admin.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Author ID"
+// @Success 204 {object} util.Response "Author deleted successfully"
+// @Failure 400 {object} util.Response "Invalid author ID"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /authors/{id} [delete]
+func (h *AuthorHandler) DeleteAuthor(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid author ID", err.Error())
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// GetAuthorBooks godoc
+// @Summary Get an author's bibliography
+// @Description Get an author's works held by the library in publication order, each with its editions.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Author ID"
+// @Success 200 {object} util.Response "Bibliography retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 301 {object} util.Response "Author was merged into another"
+// @Failure 404 {object} util.Response "Author not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /authors/{id}/books [get]
+func (h *AuthorHandler) GetAuthorBooks(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid author ID", err.Error())
+		return
+	}
+
+	bibliography, err := h.service.Bibliography(c.Request.Context(), id)
+	if err != nil {
+		if redirectMerged(c, err) {
+			return
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			util.SendNotFound(c, err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Bibliography retrieved successfully", bibliography)
+}
+
+// ListBookAuthors godoc
+// @Summary List a book's authors
+// @Description Get the authors credited on a book.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Success 200 {object} util.Response "Authors retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /books/{id}/authors [get]
+func (h *AuthorHandler) ListBookAuthors(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	authors, err := h.service.ListByBookID(c.Request.Context(), id)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Authors retrieved successfully", authors)
+}
+
+// AttachBookAuthor godoc
+// @Summary Attach an author to a book
+// @Description Credit an existing author on a book. Requires an admin.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param author body BookAuthorRequest true "Author to attach"
+// @Success 204 {object} util.Response "Author attached successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Book or author not found"
+// @Failure 409 {object} util.Response "Author already attached"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/authors [post]
+func (h *AuthorHandler) AttachBookAuthor(c *gin.Context) {
+	idParam := c.Param("id")
+	bookID, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	var req BookAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	if err := h.service.AddBookAuthor(c.Request.Context(), bookID, req.AuthorID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrBookAuthorExists):
+			util.SendError(c, http.StatusConflict, "Author already attached", err.Error())
+		case errors.Is(err, service.ErrBookAuthorTarget):
+			util.SendNotFound(c, err.Error())
+		default:
+			util.SendInternalServerError(c, err.Error())
+		}
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// DetachBookAuthor godoc
+// @Summary Detach an author from a book
+// @Description Remove an author's credit from a book. Requires an admin.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param authorId path string true "Author ID"
+// @Success 204 {object} util.Response "Author detached successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/authors/{authorId} [delete]
+func (h *AuthorHandler) DetachBookAuthor(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+	authorID, err := uuid.Parse(c.Param("authorId"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid author ID", err.Error())
+		return
+	}
+
+	if err := h.service.RemoveBookAuthor(c.Request.Context(), bookID, authorID); err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// parseOptionalDate parses a YYYY-MM-DD date, returning nil for an empty string
+func parseOptionalDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/isbn"
 	"github.com/vasujain275/bookbridge-api/internal/service"
 	"github.com/vasujain275/bookbridge-api/internal/util"
 )
@@ -22,6 +25,7 @@ func NewBookHandler(s service.BookService) *BookHandler {
 }
 
 // CreateBookRequest represents the expected request payload for creating a book.
+// The ISBN may be an ISBN-10 or ISBN-13, with or without hyphens.
 type CreateBookRequest struct {
 	ISBN string `json:"isbn" binding:"required"`
 }
@@ -35,6 +39,7 @@ type CreateBookRequest struct {
 // @Param id path string true "Book ID"
 // @Success 200 {object} util.Response "Book found"
 // @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 301 {object} util.Response "Book was merged into another"
 // @Failure 404 {object} util.Response "Book not found"
 // @Router /books/{id} [get]
 func (h *BookHandler) GetBook(c *gin.Context) {
@@ -46,15 +51,53 @@ func (h *BookHandler) GetBook(c *gin.Context) {
 	}
 	book, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
+		if redirectMerged(c, err) {
+			return
+		}
 		util.SendNotFound(c, err.Error())
 		return
 	}
 	util.SendOK(c, "Book found", book)
 }
 
+// GetBookDetails godoc
+// @Summary Get full book details
+// @Description Get a book with its authors, categories and, for each series it belongs to, the previous and next volumes with their availability.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Success 200 {object} util.Response "Book found"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 301 {object} util.Response "Book was merged into another"
+// @Failure 404 {object} util.Response "Book not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /books/{id}/details [get]
+func (h *BookHandler) GetBookDetails(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+	details, err := h.service.GetFullBookDetails(c.Request.Context(), id)
+	if err != nil {
+		if redirectMerged(c, err) {
+			return
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			util.SendNotFound(c, err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Book found", details)
+}
+
 // GetBookByISBN godoc
 // @Summary Get book by ISBN
-// @Description Get a book by its ISBN.
+// @Description Get a book by its ISBN-10 or ISBN-13. Hyphens and spaces are ignored.
 // @Tags books
 // @Accept json
 // @Produce json
@@ -64,13 +107,17 @@ func (h *BookHandler) GetBook(c *gin.Context) {
 // @Failure 404 {object} util.Response "Book not found"
 // @Router /books/isbn/{isbn} [get]
 func (h *BookHandler) GetBookByISBN(c *gin.Context) {
-	isbn := c.Param("isbn")
-	if isbn == "" {
+	isbnParam := c.Param("isbn")
+	if isbnParam == "" {
 		util.SendBadRequest(c, "Invalid ISBN", "ISBN cannot be empty")
 		return
 	}
-	book, err := h.service.GetByISBN(c.Request.Context(), isbn)
+	book, err := h.service.GetByISBN(c.Request.Context(), isbnParam)
 	if err != nil {
+		if errors.Is(err, isbn.ErrInvalid) {
+			util.SendBadRequest(c, "Invalid ISBN", err.Error())
+			return
+		}
 		util.SendNotFound(c, err.Error())
 		return
 	}
@@ -79,10 +126,11 @@ func (h *BookHandler) GetBookByISBN(c *gin.Context) {
 
 // ListBooks godoc
 // @Summary List books
-// @Description Get a list of books with pagination.
+// @Description Get a list of books with pagination, ordered by title or by average rating.
 // @Tags books
 // @Accept json
 // @Produce json
+// @Param sort query string false "Ordering (title or rating)" default(title)
 // @Param limit query int false "Limit" default(10)
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} util.Response "Books retrieved successfully"
@@ -105,8 +153,12 @@ func (h *BookHandler) ListBooks(c *gin.Context) {
 		return
 	}
 
-	books, err := h.service.List(c.Request.Context(), int32(limit), int32(offset))
+	books, err := h.service.List(c.Request.Context(), c.Query("sort"), int32(limit), int32(offset))
 	if err != nil {
+		if errors.Is(err, service.ErrBookSort) {
+			util.SendBadRequest(c, "Invalid sort parameter", err.Error())
+			return
+		}
 		util.SendInternalServerError(c, err.Error())
 		return
 	}
@@ -134,6 +186,10 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 
 	book, err := h.service.Create(c.Request.Context(), req.ISBN)
 	if err != nil {
+		if errors.Is(err, isbn.ErrInvalid) {
+			util.SendBadRequest(c, "Invalid ISBN", err.Error())
+			return
+		}
 		util.SendInternalServerError(c, "Failed to create book: "+err.Error())
 		return
 	}
@@ -0,0 +1,439 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// CategoryHandler handles HTTP requests for categories.
+type CategoryHandler struct {
+	service service.CategoryService
+}
+
+// NewCategoryHandler creates a new CategoryHandler.
+func NewCategoryHandler(s service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		service: s,
+	}
+}
+
+// CreateCategoryRequest represents the expected request payload for creating a category.
+// Omit parent_id to create a root category.
+type CreateCategoryRequest struct {
+	Name     string     `json:"name" binding:"required"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+// RenameCategoryRequest represents the expected request payload for renaming a category.
+type RenameCategoryRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// MoveCategoryRequest represents the expected request payload for moving a category.
+// A null parent_id moves the category to the root.
+type MoveCategoryRequest struct {
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// BookCategoryRequest represents the expected request payload for filing a book under a category.
+type BookCategoryRequest struct {
+	CategoryID uuid.UUID `json:"category_id" binding:"required"`
+}
+
+// GetCategory godoc
+// @Summary Get category by ID
+// @Description Get a category with its breadcrumbs from the root and its direct children.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} util.Response "Category found"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 404 {object} util.Response "Category not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid category ID", err.Error())
+		return
+	}
+
+	details, err := h.service.GetDetails(c.Request.Context(), id)
+	if err != nil {
+		sendCategoryError(c, err)
+		return
+	}
+	util.SendOK(c, "Category found", details)
+}
+
+// ListCategories godoc
+// @Summary List categories
+// @Description Get a flat, paginated list of categories ordered by name.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Categories retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /categories [get]
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	categories, err := h.service.List(c.Request.Context(), limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Categories retrieved successfully", categories)
+}
+
+// GetCategoryTree godoc
+// @Summary Get the category tree
+// @Description Get the whole category taxonomy with direct and total book counts on each node.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Success 200 {object} util.Response "Category tree retrieved successfully"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /categories/tree [get]
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.service.Tree(c.Request.Context())
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Category tree retrieved successfully", tree)
+}
+
+// GetCategorySubtree godoc
+// @Summary Get a category subtree
+// @Description Get the part of the taxonomy rooted at a category, with direct and total book counts on each node.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} util.Response "Category subtree retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 404 {object} util.Response "Category not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /categories/{id}/subtree [get]
+func (h *CategoryHandler) GetCategorySubtree(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid category ID", err.Error())
+		return
+	}
+
+	subtree, err := h.service.Subtree(c.Request.Context(), id)
+	if err != nil {
+		sendCategoryError(c, err)
+		return
+	}
+	util.SendOK(c, "Category subtree retrieved successfully", subtree)
+}
+
+// ListCategoryBooks godoc
+// @Summary List books in a category
+// @Description Get a paginated list of books filed under a category or any of its descendants.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Books retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /categories/{id}/books [get]
+func (h *CategoryHandler) ListCategoryBooks(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid category ID", err.Error())
+		return
+	}
+
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	books, err := h.service.ListBooks(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Books retrieved successfully", books)
+}
+
+// CreateCategory godoc
+// @Summary Create a category
+// @Description Create a category, under a parent or at the root. Requires an admin.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category body CreateCategoryRequest true "Category data"
+// @Success 201 {object} util.Response "Category created successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Parent category not found"
+// @Failure 409 {object} util.Response "Category already exists"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	category, err := h.service.Create(c.Request.Context(), req.Name, req.ParentID)
+	if err != nil {
+		sendCategoryError(c, err)
+		return
+	}
+	util.SendCreated(c, "Category created successfully", category)
+}
+
+// RenameCategory godoc
+// @Summary Rename a category
+// @Description Rename a category. Its subtree and books are unchanged. Requires an admin.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param category body RenameCategoryRequest true "New name"
+// @Success 200 {object} util.Response "Category renamed successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Category not found"
+// @Failure 409 {object} util.Response "Category already exists"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) RenameCategory(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid category ID", err.Error())
+		return
+	}
+
+	var req RenameCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	category, err := h.service.Update(c.Request.Context(), id, req.Name)
+	if err != nil {
+		sendCategoryError(c, err)
+		return
+	}
+	util.SendOK(c, "Category renamed successfully", category)
+}
+
+// MoveCategory godoc
+// @Summary Move a category
+// @Description Move a category and its whole subtree under a new parent, or to the root. Book links are kept. Requires an admin.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param category body MoveCategoryRequest true "New parent"
+// @Success 200 {object} util.Response "Category moved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Category not found"
+// @Failure 409 {object} util.Response "Move would create a cycle"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /categories/{id}/parent [put]
+func (h *CategoryHandler) MoveCategory(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid category ID", err.Error())
+		return
+	}
+
+	var req MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	category, err := h.service.Move(c.Request.Context(), id, req.ParentID)
+	if err != nil {
+		sendCategoryError(c, err)
+		return
+	}
+	util.SendOK(c, "Category moved successfully", category)
+}
+
+// DeleteCategory godoc
+// @Summary Delete a category
+// @Description Delete a category. Its children move up to its parent. Requires an admin.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 204 {object} util.Response "Category deleted successfully"
+// @Failure 400 {object} util.Response "Invalid category ID"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Category not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid category ID", err.Error())
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		sendCategoryError(c, err)
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// ListBookCategories godoc
+// @Summary List a book's categories
+// @Description Get the categories a book is filed under.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Success 200 {object} util.Response "Categories retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /books/{id}/categories [get]
+func (h *CategoryHandler) ListBookCategories(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	categories, err := h.service.ListByBookID(c.Request.Context(), id)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Categories retrieved successfully", categories)
+}
+
+// AttachBookCategory godoc
+// @Summary File a book under a category
+// @Description File a book under an existing category. Requires an admin.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param category body BookCategoryRequest true "Category to file under"
+// @Success 204 {object} util.Response "Category attached successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Book or category not found"
+// @Failure 409 {object} util.Response "Book already in category"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/categories [post]
+func (h *CategoryHandler) AttachBookCategory(c *gin.Context) {
+	idParam := c.Param("id")
+	bookID, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	var req BookCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	if err := h.service.AddBookCategory(c.Request.Context(), bookID, req.CategoryID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrBookCategoryExists):
+			util.SendError(c, http.StatusConflict, "Book already in category", err.Error())
+		case errors.Is(err, service.ErrBookCategoryTarget):
+			util.SendNotFound(c, err.Error())
+		default:
+			util.SendInternalServerError(c, err.Error())
+		}
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// DetachBookCategory godoc
+// @Summary Remove a book from a category
+// @Description Remove a book from a category. Requires an admin.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param categoryId path string true "Category ID"
+// @Success 204 {object} util.Response "Category detached successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/categories/{categoryId} [delete]
+func (h *CategoryHandler) DetachBookCategory(c *gin.Context) {
+	bookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+	categoryID, err := uuid.Parse(c.Param("categoryId"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid category ID", err.Error())
+		return
+	}
+
+	if err := h.service.RemoveBookCategory(c.Request.Context(), bookID, categoryID); err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendNoContent(c)
+}
+
+func sendCategoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCategoryExists):
+		util.SendError(c, http.StatusConflict, "Category already exists", err.Error())
+	case errors.Is(err, service.ErrCategoryCycle):
+		util.SendError(c, http.StatusConflict, "Invalid move", err.Error())
+	case errors.Is(err, pgx.ErrNoRows):
+		util.SendNotFound(c, err.Error())
+	default:
+		util.SendInternalServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasujain275/bookbridge-api/internal/citation"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// maxBulkCitations caps how many books can be cited in a single request
+const maxBulkCitations = 100
+
+// CitationHandler handles HTTP requests for book citations.
+type CitationHandler struct {
+	service service.CitationService
+}
+
+// NewCitationHandler creates a new CitationHandler.
+func NewCitationHandler(s service.CitationService) *CitationHandler {
+	return &CitationHandler{
+		service: s,
+	}
+}
+
+// CiteBooksRequest represents the expected request payload for citing several books.
+type CiteBooksRequest struct {
+	BookIDs []uuid.UUID `json:"book_ids" binding:"required,min=1"`
+	Format  string      `json:"format" binding:"required"`
+}
+
+// GetBookCitation godoc
+// @Summary Cite a book
+// @Description Render a citation for a book as BibTeX, RIS, CSL-JSON, APA or MLA.
+// @Tags citations
+// @Produce plain
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param format query string false "bibtex, ris, csljson, apa or mla" default(bibtex)
+// @Success 200 {string} string "Citation"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 404 {object} util.Response "Book not found"
+// @Router /books/{id}/cite [get]
+func (h *CitationHandler) GetBookCitation(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	h.cite(c, []uuid.UUID{id}, c.DefaultQuery("format", citation.FormatBibTeX))
+}
+
+// CiteBooks godoc
+// @Summary Cite several books
+// @Description Render citations for a list of books, in the order given, as BibTeX, RIS, CSL-JSON, APA or MLA.
+// @Tags citations
+// @Accept json
+// @Produce plain
+// @Produce json
+// @Param request body CiteBooksRequest true "Book IDs and format"
+// @Success 200 {string} string "Citations"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 404 {object} util.Response "Book not found"
+// @Router /books/cite [post]
+func (h *CitationHandler) CiteBooks(c *gin.Context) {
+	var req CiteBooksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+	if len(req.BookIDs) > maxBulkCitations {
+		util.SendBadRequest(c, "Too many books", "at most 100 books can be cited at once")
+		return
+	}
+
+	h.cite(c, req.BookIDs, req.Format)
+}
+
+func (h *CitationHandler) cite(c *gin.Context, ids []uuid.UUID, format string) {
+	format = strings.ToLower(format)
+
+	data, err := h.service.Cite(c.Request.Context(), ids, format)
+	if err != nil {
+		if errors.Is(err, citation.ErrUnsupportedFormat) {
+			util.SendBadRequest(c, "Invalid format parameter", "format must be one of bibtex, ris, csljson, apa, mla")
+			return
+		}
+		util.SendNotFound(c, err.Error())
+		return
+	}
+
+	c.Data(http.StatusOK, citation.ContentType(format), data)
+}
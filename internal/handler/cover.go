@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/cover"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// maxCoverUpload caps the body of a cover upload: the 10 MB image plus room for multipart framing
+const maxCoverUpload = 11 << 20
+
+// CoverHandler handles HTTP requests for book cover images.
+type CoverHandler struct {
+	service service.CoverService
+}
+
+// NewCoverHandler creates a new CoverHandler.
+func NewCoverHandler(s service.CoverService) *CoverHandler {
+	return &CoverHandler{
+		service: s,
+	}
+}
+
+// GetCover godoc
+// @Summary Get book cover
+// @Description Serve a locally stored cover image. Supports conditional requests with If-None-Match.
+// @Tags covers
+// @Produce image/jpeg
+// @Param id path string true "Book ID"
+// @Param size query string false "small, medium or large" default(medium)
+// @Success 200 {file} file "Cover image"
+// @Success 304 "Not modified"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 404 {object} util.Response "Cover not found"
+// @Router /books/{id}/cover [get]
+func (h *CoverHandler) GetCover(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	size, ok := cover.ParseSize(c.DefaultQuery("size", string(cover.SizeMedium)))
+	if !ok {
+		util.SendBadRequest(c, "Invalid size parameter", "size must be small, medium or large")
+		return
+	}
+
+	rc, meta, err := h.service.Get(c.Request.Context(), id, size)
+	if err != nil {
+		util.SendNotFound(c, err.Error())
+		return
+	}
+	defer rc.Close()
+
+	etag := `"` + meta.Etag + "-" + string(size) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=86400")
+	if match := c.GetHeader("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, -1, cover.ContentType, rc, nil)
+}
+
+// UploadCover godoc
+// @Summary Upload book cover
+// @Description Upload a JPEG, PNG or GIF cover of up to 10 MB and 25 megapixels as a multipart "cover" file or as the raw request body. Small, medium and large renditions are generated. Admins only.
+// @Tags covers
+// @Accept multipart/form-data
+// @Accept image/jpeg
+// @Accept image/png
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param cover formData file false "Cover image"
+// @Success 201 {object} util.Response "Cover uploaded successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Book not found"
+// @Failure 413 {object} util.Response "Cover too large"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/cover [post]
+func (h *CoverHandler) UploadCover(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCoverUpload)
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("cover")
+		if err != nil {
+			if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+				util.SendError(c, http.StatusRequestEntityTooLarge, "Cover too large", err.Error())
+				return
+			}
+			util.SendBadRequest(c, "Missing cover file", err.Error())
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			util.SendBadRequest(c, "Invalid cover file", err.Error())
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	meta, err := h.service.Upload(c.Request.Context(), id, body)
+	if err != nil {
+		if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) ||
+			errors.Is(err, service.ErrCoverTooLarge) || errors.Is(err, cover.ErrImageTooLarge) {
+			util.SendError(c, http.StatusRequestEntityTooLarge, "Cover too large", err.Error())
+			return
+		}
+		if errors.Is(err, cover.ErrUnsupportedImage) {
+			util.SendBadRequest(c, "Invalid cover image", err.Error())
+			return
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			util.SendNotFound(c, "Book not found")
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	util.SendCreated(c, "Cover uploaded successfully", meta)
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// eventStreamKeepAlive is how often an idle stream sends a comment so proxies keep it open
+const eventStreamKeepAlive = 15 * time.Second
+
+// EventStreamHandler handles HTTP requests for live event streams.
+type EventStreamHandler struct {
+	service service.EventStreamService
+}
+
+// NewEventStreamHandler creates a new EventStreamHandler.
+func NewEventStreamHandler(s service.EventStreamService) *EventStreamHandler {
+	return &EventStreamHandler{
+		service: s,
+	}
+}
+
+// StreamEvents godoc
+// @Summary Stream circulation events
+// @Description Stream circulation events live as Server-Sent Events: loan.overdue, hold.ready and book.created. Each message has the event ID as its id, the event type as its event and the event as JSON data. A client reconnecting with a Last-Event-ID header, or last_event_id query parameter, first receives the recent events it missed. Every instance streams every event, whichever instance recorded it. Events can be filtered by type only; there is no per-branch filter, as the library has no branches. Requires an admin.
+// @Tags events
+// @Produce text/event-stream
+// @Param type query string false "Comma-separated event types to receive; all when empty"
+// @Param last_event_id query string false "ID of the last event received, when the Last-Event-ID header cannot be set"
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 503 {object} util.Response "Server shutting down"
+// @Security BasicAuth
+// @Router /events/stream [get]
+func (h *EventStreamHandler) StreamEvents(c *gin.Context) {
+	var types []string
+	for _, t := range strings.Split(c.Query("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	stream, err := h.service.Subscribe(lastEventID, types)
+	if err != nil {
+		if errors.Is(err, service.ErrEventStreamTypes) {
+			util.SendBadRequest(c, "Invalid type parameter", err.Error())
+			return
+		}
+		util.SendError(c, http.StatusServiceUnavailable, "Server shutting down", err.Error())
+		return
+	}
+	defer stream.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	for _, event := range stream.Backlog {
+		if err := writeEvent(c, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventStreamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-stream.Events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(c, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent writes one event as a Server-Sent Events message
+func writeEvent(c *gin.Context, event service.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// HealthHandler handles liveness and readiness probes.
+type HealthHandler struct {
+	service service.HealthService
+}
+
+// NewHealthHandler creates a new HealthHandler.
+func NewHealthHandler(s service.HealthService) *HealthHandler {
+	return &HealthHandler{
+		service: s,
+	}
+}
+
+// Liveness godoc
+// @Summary Liveness probe
+// @Description Report that the process is up and serving requests. Checks no dependencies.
+// @Tags health
+// @Produce json
+// @Success 200 {object} util.Response "Alive"
+// @Router /healthz [get]
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	util.SendOK(c, "Alive", gin.H{"status": "ok"})
+}
+
+// Readiness godoc
+// @Summary Readiness probe
+// @Description Report whether the server can take traffic: the database answers a ping and has every migration applied. Open Library, when checked, only degrades readiness. Not ready as soon as the server starts shutting down. Only each check's status is returned; failures are logged.
+// @Tags health
+// @Produce json
+// @Success 200 {object} util.Response "Ready or degraded"
+// @Failure 503 {object} util.Response "Not ready or shutting down"
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	readiness := h.service.Ready(c.Request.Context())
+	switch readiness.Status {
+	case service.ReadinessReady, service.ReadinessDegraded:
+		util.SendOK(c, "Ready", readiness)
+	default:
+		util.SendError(c, http.StatusServiceUnavailable, "Not ready", readiness)
+	}
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/middleware"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// HoldHandler handles HTTP requests for holds.
+type HoldHandler struct {
+	service service.HoldService
+}
+
+// NewHoldHandler creates a new HoldHandler.
+func NewHoldHandler(s service.HoldService) *HoldHandler {
+	return &HoldHandler{
+		service: s,
+	}
+}
+
+// PlaceHoldRequest represents the expected request payload for placing a hold.
+// Set work_id to accept any edition, or book_id to wait for a specific edition.
+// Holds are placed for the caller; only admins may set user_id to place one for another member.
+type PlaceHoldRequest struct {
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	WorkID *uuid.UUID `json:"work_id,omitempty"`
+	BookID *uuid.UUID `json:"book_id,omitempty"`
+}
+
+// PlaceHold godoc
+// @Summary Place a hold
+// @Description Place a hold on a work (any edition) or a specific edition for the caller, or for user_id when the caller is an admin. If a copy is available it is set aside immediately.
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Security BasicAuth
+// @Param hold body PlaceHoldRequest true "Hold data"
+// @Success 201 {object} util.Response "Hold placed successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Authentication required"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Work or book not found"
+// @Failure 409 {object} util.Response "Hold already exists"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /holds [post]
+func (h *HoldHandler) PlaceHold(c *gin.Context) {
+	var req PlaceHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	actor, ok := middleware.Actor(c)
+	if !ok {
+		util.SendUnauthorized(c)
+		return
+	}
+	userID := actor.ID
+	if req.UserID != nil && *req.UserID != actor.ID {
+		if actor.Role != service.RoleAdmin {
+			util.SendForbidden(c)
+			return
+		}
+		userID = *req.UserID
+	}
+
+	hold, err := h.service.Place(c.Request.Context(), service.PlaceHoldParams{
+		UserID: userID,
+		WorkID: req.WorkID,
+		BookID: req.BookID,
+	})
+	if err != nil {
+		sendHoldError(c, err)
+		return
+	}
+
+	util.SendCreated(c, "Hold placed successfully", hold)
+}
+
+// GetHold godoc
+// @Summary Get hold by ID
+// @Description Get a hold by its ID.
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Security BasicAuth
+// @Param id path string true "Hold ID"
+// @Success 200 {object} util.Response "Hold found"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 401 {object} util.Response "Authentication required"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Hold not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /holds/{id} [get]
+func (h *HoldHandler) GetHold(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid hold ID", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	hold, err := h.service.GetByID(c.Request.Context(), actor, id)
+	if err != nil {
+		sendHoldError(c, err)
+		return
+	}
+	util.SendOK(c, "Hold found", hold)
+}
+
+// CancelHold godoc
+// @Summary Cancel a hold
+// @Description Cancel an open hold. A copy set aside for it passes to the next member in the queue. Only the hold's member or an admin may cancel it.
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Security BasicAuth
+// @Param id path string true "Hold ID"
+// @Success 200 {object} util.Response "Hold cancelled"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Authentication required"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Hold not found"
+// @Failure 409 {object} util.Response "Hold is not open"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /holds/{id} [delete]
+func (h *HoldHandler) CancelHold(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid hold ID", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	hold, err := h.service.Cancel(c.Request.Context(), actor, id)
+	if err != nil {
+		sendHoldError(c, err)
+		return
+	}
+	util.SendOK(c, "Hold cancelled", hold)
+}
+
+// ListUserHolds godoc
+// @Summary List a user's holds
+// @Description Get a paginated list of a user's holds, newest first. Only the user and admins may list them.
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Security BasicAuth
+// @Param id path string true "User ID"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Holds retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Authentication required"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /users/{id}/holds [get]
+func (h *HoldHandler) ListUserHolds(c *gin.Context) {
+	id, ok := selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	holds, err := h.service.ListByUserID(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Holds retrieved successfully", holds)
+}
+
+// ListWorkHolds godoc
+// @Summary List a work's hold queue
+// @Description Get the open holds on a work, in queue order.
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Security BasicAuth
+// @Param id path string true "Work ID"
+// @Success 200 {object} util.Response "Holds retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Authentication required"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /works/{id}/holds [get]
+func (h *HoldHandler) ListWorkHolds(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid work ID", err.Error())
+		return
+	}
+
+	holds, err := h.service.ListByWorkID(c.Request.Context(), id)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Holds retrieved successfully", holds)
+}
+
+func sendHoldError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrHoldTarget):
+		util.SendBadRequest(c, "Invalid hold target", err.Error())
+	case errors.Is(err, service.ErrHoldUser):
+		util.SendBadRequest(c, "Invalid user", err.Error())
+	case errors.Is(err, service.ErrHoldExists):
+		util.SendError(c, http.StatusConflict, "Hold already exists", err.Error())
+	case errors.Is(err, service.ErrHoldNotOpen):
+		util.SendError(c, http.StatusConflict, "Hold is not open", err.Error())
+	case errors.Is(err, service.ErrHoldForbidden):
+		util.SendForbidden(c)
+	case errors.Is(err, pgx.ErrNoRows):
+		util.SendNotFound(c, err.Error())
+	default:
+		util.SendInternalServerError(c, err.Error())
+	}
+}
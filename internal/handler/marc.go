@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// maxMarcImportSize caps the size of an uploaded MARC file (32 MB)
+const maxMarcImportSize = 32 << 20
+
+// MarcHandler handles HTTP requests for MARC import and export.
+type MarcHandler struct {
+	service service.MarcService
+}
+
+// NewMarcHandler creates a new MarcHandler.
+func NewMarcHandler(s service.MarcService) *MarcHandler {
+	return &MarcHandler{
+		service: s,
+	}
+}
+
+// marcFormat reads and validates the format query parameter, falling back to defaultFormat.
+func marcFormat(c *gin.Context, defaultFormat string) (string, bool) {
+	format := strings.ToLower(c.DefaultQuery("format", defaultFormat))
+	if format != service.MarcFormatBinary && format != service.MarcFormatXML {
+		util.SendBadRequest(c, "Invalid format parameter", "format must be marc21 or marcxml")
+		return "", false
+	}
+	return format, true
+}
+
+// marcContentType returns the media type and file extension for a MARC format.
+func marcContentType(format string) (string, string) {
+	if format == service.MarcFormatXML {
+		return "application/marcxml+xml", "xml"
+	}
+	return "application/marc", "mrc"
+}
+
+// GetBookMarc godoc
+// @Summary Export book as MARC
+// @Description Export a single book as a binary MARC21 or MARCXML record.
+// @Tags marc
+// @Produce application/marc
+// @Produce application/marcxml+xml
+// @Param id path string true "Book ID"
+// @Param format query string false "marc21 or marcxml" default(marc21)
+// @Success 200 {file} file "MARC record"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 404 {object} util.Response "Book not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /books/{id}/marc [get]
+func (h *MarcHandler) GetBookMarc(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	format, ok := marcFormat(c, service.MarcFormatBinary)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.ExportBook(c.Request.Context(), id, format)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			util.SendNotFound(c, err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	contentType, ext := marcContentType(format)
+	c.Header("Content-Disposition", "attachment; filename=\""+id.String()+"."+ext+"\"")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ExportMarc godoc
+// @Summary Export catalog as MARC
+// @Description Stream every book in the catalog as binary MARC21 or a MARCXML collection.
+// @Tags marc
+// @Produce application/marc
+// @Produce application/marcxml+xml
+// @Param format query string false "marc21 or marcxml" default(marc21)
+// @Success 200 {file} file "MARC records"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Router /export/marc [get]
+func (h *MarcHandler) ExportMarc(c *gin.Context) {
+	format, ok := marcFormat(c, service.MarcFormatBinary)
+	if !ok {
+		return
+	}
+
+	contentType, ext := marcContentType(format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", "attachment; filename=\"catalog."+ext+"\"")
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, so a failure part way through can only be logged
+	if err := h.service.ExportAll(c.Request.Context(), c.Writer, format); err != nil {
+		slog.ErrorContext(c.Request.Context(), "MARC export failed", "format", format, "error", err)
+	}
+}
+
+// ImportMarc godoc
+// @Summary Import MARC records
+// @Description Import books, authors and categories from binary MARC21 or MARCXML. Books whose ISBN-13 already exists are skipped. Admins only.
+// @Tags marc
+// @Accept application/marc
+// @Accept application/marcxml+xml
+// @Produce json
+// @Param format query string false "marc21 or marcxml; inferred from Content-Type when omitted"
+// @Success 201 {object} util.Response "MARC records imported"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /import/marc [post]
+func (h *MarcHandler) ImportMarc(c *gin.Context) {
+	defaultFormat := service.MarcFormatBinary
+	if strings.Contains(c.ContentType(), "xml") {
+		defaultFormat = service.MarcFormatXML
+	}
+
+	format, ok := marcFormat(c, defaultFormat)
+	if !ok {
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxMarcImportSize)
+	result, err := h.service.Import(c.Request.Context(), body, format)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMarcData) {
+			util.SendBadRequest(c, "Failed to parse MARC records", err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+
+	util.SendCreated(c, "MARC records imported", result)
+}
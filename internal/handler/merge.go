@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// MergeHandler handles HTTP requests for duplicate detection and merging.
+type MergeHandler struct {
+	service service.MergeService
+}
+
+// NewMergeHandler creates a new MergeHandler.
+func NewMergeHandler(s service.MergeService) *MergeHandler {
+	return &MergeHandler{
+		service: s,
+	}
+}
+
+// MergeRequest represents the expected request payload for merging records.
+// The duplicates are folded into the survivor and then deleted.
+type MergeRequest struct {
+	SurvivorID   uuid.UUID   `json:"survivor_id" binding:"required"`
+	DuplicateIDs []uuid.UUID `json:"duplicate_ids" binding:"required,min=1"`
+}
+
+// ListAuthorDuplicates godoc
+// @Summary List possible duplicate authors
+// @Description Report pairs of authors sharing an Open Library key, the same normalized name, or similar names. Requires an admin.
+// @Tags merge
+// @Accept json
+// @Produce json
+// @Param min_similarity query number false "Minimum trigram name similarity, between 0 and 1" default(0.6)
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Duplicate candidates retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /authors/duplicates [get]
+func (h *MergeHandler) ListAuthorDuplicates(c *gin.Context) {
+	minSimilarity, limit, offset, ok := parseDuplicateQuery(c)
+	if !ok {
+		return
+	}
+
+	candidates, err := h.service.AuthorDuplicates(c.Request.Context(), minSimilarity, limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Duplicate candidates retrieved successfully", candidates)
+}
+
+// ListBookDuplicates godoc
+// @Summary List possible duplicate books
+// @Description Report pairs of books whose ISBN-10 and ISBN-13 match, or with similar titles. Requires an admin.
+// @Tags merge
+// @Accept json
+// @Produce json
+// @Param min_similarity query number false "Minimum trigram title similarity, between 0 and 1" default(0.6)
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Duplicate candidates retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/duplicates [get]
+func (h *MergeHandler) ListBookDuplicates(c *gin.Context) {
+	minSimilarity, limit, offset, ok := parseDuplicateQuery(c)
+	if !ok {
+		return
+	}
+
+	candidates, err := h.service.BookDuplicates(c.Request.Context(), minSimilarity, limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Duplicate candidates retrieved successfully", candidates)
+}
+
+// MergeAuthors godoc
+// @Summary Merge authors
+// @Description Fold duplicate authors into a survivor. Their books are credited to the survivor and their old IDs redirect to it. Requires an admin.
+// @Tags merge
+// @Accept json
+// @Produce json
+// @Param merge body MergeRequest true "Survivor and duplicates"
+// @Success 200 {object} util.Response "Authors merged successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Author not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /authors/merge [post]
+func (h *MergeHandler) MergeAuthors(c *gin.Context) {
+	var req MergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	author, err := h.service.MergeAuthors(c.Request.Context(), req.SurvivorID, req.DuplicateIDs)
+	if err != nil {
+		sendMergeError(c, err)
+		return
+	}
+	util.SendOK(c, "Authors merged successfully", author)
+}
+
+// MergeBooks godoc
+// @Summary Merge books
+// @Description Fold duplicate books into a survivor. Authors, categories, loans, reviews and holds move to the survivor, copies are added to its stock, and the old IDs redirect to it. A member who reviewed more than one of the books keeps only their most recently updated review. Requires an admin.
+// @Tags merge
+// @Accept json
+// @Produce json
+// @Param merge body MergeRequest true "Survivor and duplicates"
+// @Success 200 {object} util.Response "Books merged successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Book not found"
+// @Failure 409 {object} util.Response "Books conflict"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/merge [post]
+func (h *MergeHandler) MergeBooks(c *gin.Context) {
+	var req MergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	book, err := h.service.MergeBooks(c.Request.Context(), req.SurvivorID, req.DuplicateIDs)
+	if err != nil {
+		sendMergeError(c, err)
+		return
+	}
+	util.SendOK(c, "Books merged successfully", book)
+}
+
+func sendMergeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrMergeSelf):
+		util.SendBadRequest(c, "Invalid merge", err.Error())
+	case errors.Is(err, service.ErrMergeConflict):
+		util.SendError(c, http.StatusConflict, "Records conflict", err.Error())
+	case errors.Is(err, pgx.ErrNoRows):
+		util.SendNotFound(c, err.Error())
+	default:
+		util.SendInternalServerError(c, err.Error())
+	}
+}
+
+// parseDuplicateQuery reads the min_similarity, limit and offset query parameters
+func parseDuplicateQuery(c *gin.Context) (float64, int32, int32, bool) {
+	minSimilarity := service.DefaultDuplicateSimilarity
+	if raw := c.Query("min_similarity"); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 || value > 1 {
+			util.SendBadRequest(c, "Invalid min_similarity parameter", "min_similarity must be between 0 and 1")
+			return 0, 0, 0, false
+		}
+		minSimilarity = value
+	}
+
+	limit, offset, ok := parsePagination(c)
+	return minSimilarity, limit, offset, ok
+}
+
+// redirectMerged sends a permanent redirect to the surviving record if err reports that the
+// record identified by the id path parameter was merged away
+func redirectMerged(c *gin.Context, err error) bool {
+	var merged *service.MergedError
+	if !errors.As(err, &merged) {
+		return false
+	}
+
+	location := strings.Replace(c.Request.URL.Path, c.Param("id"), merged.NewID.String(), 1)
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, location)
+	return true
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// NotificationHandler handles HTTP requests for email notifications and their preferences.
+type NotificationHandler struct {
+	service service.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler.
+func NewNotificationHandler(s service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		service: s,
+	}
+}
+
+// NotificationPreferencesRequest represents the expected request payload for choosing which emails a user gets.
+// Leaving out due_reminder_days uses the library's default reminder period.
+type NotificationPreferencesRequest struct {
+	DueReminders    *bool  `json:"due_reminders" binding:"required"`
+	DueReminderDays *int32 `json:"due_reminder_days" binding:"omitempty,min=0,max=30"`
+	OverdueNotices  *bool  `json:"overdue_notices" binding:"required"`
+	HoldReady       *bool  `json:"hold_ready" binding:"required"`
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get a user's notification preferences
+// @Description Get which emails a user gets: due-date reminders and how many days ahead, overdue notices, and hold-ready notices. Users who never chose get every email. Only the user and admins may see them.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} util.Response "Notification preferences retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "User not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /users/{id}/notification-preferences [get]
+func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
+	id, ok := selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.service.Preferences(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			util.SendNotFound(c, err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Notification preferences retrieved successfully", preferences)
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update a user's notification preferences
+// @Description Choose which emails a user gets. Only the user and admins may change them.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param preferences body NotificationPreferencesRequest true "Notification preferences"
+// @Success 200 {object} util.Response "Notification preferences updated"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "User not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /users/{id}/notification-preferences [put]
+func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
+	id, ok := selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	var req NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	params := repository.UpsertNotificationPreferencesParams{
+		UserID:         id,
+		DueReminders:   *req.DueReminders,
+		OverdueNotices: *req.OverdueNotices,
+		HoldReady:      *req.HoldReady,
+	}
+	if req.DueReminderDays != nil {
+		params.DueReminderDays = util.Int32ToPgInt(*req.DueReminderDays)
+	}
+
+	preferences, err := h.service.SetPreferences(c.Request.Context(), params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			util.SendNotFound(c, err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Notification preferences updated", preferences)
+}
+
+// ListUserNotifications godoc
+// @Summary List a user's notifications
+// @Description Get a paginated list of the emails queued for a user, newest first, with their delivery status. Only the user and admins may see them.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Notifications retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /users/{id}/notifications [get]
+func (h *NotificationHandler) ListUserNotifications(c *gin.Context) {
+	id, ok := selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	notifications, err := h.service.ListByUserID(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Notifications retrieved successfully", notifications)
+}
+
+// ListNotifications godoc
+// @Summary List notifications
+// @Description Get a paginated list of every queued email, newest first, optionally only those with a delivery status. Requires an admin.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param status query string false "Delivery status" Enums(pending, sent, failed)
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Notifications retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	notifications, err := h.service.List(c.Request.Context(), c.Query("status"), limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrNotificationStatus) {
+			util.SendBadRequest(c, "Invalid status parameter", err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Notifications retrieved successfully", notifications)
+}
+
+// RetryNotification godoc
+// @Summary Retry a failed notification
+// @Description Queue a notification that failed every delivery attempt for one more attempt straight away. Requires an admin.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} util.Response "Notification queued for retry"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Notification not found"
+// @Failure 409 {object} util.Response "Notification has not failed"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /notifications/{id}/retry [post]
+func (h *NotificationHandler) RetryNotification(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid notification ID", err.Error())
+		return
+	}
+
+	notification, err := h.service.Retry(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			util.SendNotFound(c, "Notification not found")
+		case errors.Is(err, service.ErrNotificationNotFailed):
+			util.SendError(c, http.StatusConflict, "Notification has not failed", err.Error())
+		default:
+			util.SendInternalServerError(c, err.Error())
+		}
+		return
+	}
+	util.SendOK(c, "Notification queued for retry", notification)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// maxPageLimit caps the limit query parameter, so one request cannot ask for a whole table
+const maxPageLimit = 100
+
+// parsePagination reads the limit and offset query parameters, sending a
+// bad request response and returning false if either is malformed or negative.
+// Limits above maxPageLimit are lowered to it.
+func parsePagination(c *gin.Context) (int32, int32, bool) {
+	limit, ok := parseLimit(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	offset, err := strconv.ParseInt(c.DefaultQuery("offset", "0"), 10, 32)
+	if err == nil && offset < 0 {
+		err = errors.New("offset must not be negative")
+	}
+	if err != nil {
+		util.SendBadRequest(c, "Invalid offset parameter", err.Error())
+		return 0, 0, false
+	}
+
+	return limit, int32(offset), true
+}
+
+// parseLimit reads the limit query parameter, sending a bad request response and
+// returning false if it is malformed or negative. Limits above maxPageLimit are
+// lowered to it.
+func parseLimit(c *gin.Context) (int32, bool) {
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 32)
+	if err == nil && limit < 0 {
+		err = errors.New("limit must not be negative")
+	}
+	if err != nil {
+		util.SendBadRequest(c, "Invalid limit parameter", err.Error())
+		return 0, false
+	}
+
+	return int32(min(limit, maxPageLimit)), true
+}
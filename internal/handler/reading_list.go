@@ -0,0 +1,456 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/middleware"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// ReadingListHandler handles HTTP requests for reading lists.
+type ReadingListHandler struct {
+	service service.ReadingListService
+}
+
+// NewReadingListHandler creates a new ReadingListHandler.
+func NewReadingListHandler(s service.ReadingListService) *ReadingListHandler {
+	return &ReadingListHandler{
+		service: s,
+	}
+}
+
+// CreateReadingListRequest represents the expected request payload for creating a reading list.
+// A member may have one to_read and one favorites list and any number of custom lists.
+type CreateReadingListRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Kind        string `json:"kind" binding:"omitempty,oneof=to_read favorites custom"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+// UpdateReadingListRequest represents the expected request payload for updating a reading list.
+type UpdateReadingListRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	IsPublic    bool   `json:"is_public"`
+}
+
+// AddReadingListItemRequest represents the expected request payload for adding a book to a reading list.
+type AddReadingListItemRequest struct {
+	BookID uuid.UUID `json:"book_id" binding:"required"`
+	Note   string    `json:"note"`
+}
+
+// UpdateReadingListItemRequest represents the expected request payload for changing an item's note.
+type UpdateReadingListItemRequest struct {
+	Note string `json:"note"`
+}
+
+// ReorderReadingListRequest represents the expected request payload for reordering a reading list.
+// It must list every item ID on the list exactly once, in the new order.
+type ReorderReadingListRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids" binding:"required"`
+}
+
+// GetReadingList godoc
+// @Summary Get a reading list
+// @Description Get a reading list with its items in order, each with its book's availability. Private lists are only visible to their owner and admins.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Success 200 {object} util.Response "Reading list found"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 404 {object} util.Response "Reading list not found"
+// @Router /reading-lists/{id} [get]
+func (h *ReadingListHandler) GetReadingList(c *gin.Context) {
+	id, ok := readingListID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	details, err := h.service.GetByID(c.Request.Context(), actor, id)
+	if err != nil {
+		sendReadingListError(c, err)
+		return
+	}
+	util.SendOK(c, "Reading list found", details)
+}
+
+// GetSharedReadingList godoc
+// @Summary Get a shared reading list
+// @Description Get a public reading list from its share link, with its items in order and their availability.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} util.Response "Reading list found"
+// @Failure 404 {object} util.Response "Reading list not found"
+// @Router /reading-lists/shared/{token} [get]
+func (h *ReadingListHandler) GetSharedReadingList(c *gin.Context) {
+	details, err := h.service.GetByShareToken(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		sendReadingListError(c, err)
+		return
+	}
+	util.SendOK(c, "Reading list found", details)
+}
+
+// ListUserReadingLists godoc
+// @Summary List a user's reading lists
+// @Description Get a paginated list of a user's reading lists, "to read" and "favorites" first. The user and admins also see private lists.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Reading lists retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /users/{id}/reading-lists [get]
+func (h *ReadingListHandler) ListUserReadingLists(c *gin.Context) {
+	idParam := c.Param("id")
+	userID, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	lists, err := h.service.ListByUserID(c.Request.Context(), actor, userID, limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Reading lists retrieved successfully", lists)
+}
+
+// CreateReadingList godoc
+// @Summary Create a reading list
+// @Description Create a reading list for the authenticated user. Public lists get a share_token for their share link.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param list body CreateReadingListRequest true "Reading list data"
+// @Success 201 {object} util.Response "Reading list created successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 409 {object} util.Response "Reading list already exists"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /reading-lists [post]
+func (h *ReadingListHandler) CreateReadingList(c *gin.Context) {
+	var req CreateReadingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	list, err := h.service.Create(c.Request.Context(), actor, req.Kind, service.ReadingListParams{
+		Name:        req.Name,
+		Description: req.Description,
+		Public:      req.IsPublic,
+	})
+	if err != nil {
+		sendReadingListError(c, err)
+		return
+	}
+	util.SendCreated(c, "Reading list created successfully", list)
+}
+
+// UpdateReadingList godoc
+// @Summary Update a reading list
+// @Description Rename a reading list and change its visibility. Making a list private revokes its share link; making it public again issues a new one. Only the owner or an admin may update it.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Param list body UpdateReadingListRequest true "Reading list data"
+// @Success 200 {object} util.Response "Reading list updated successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Reading list not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /reading-lists/{id} [put]
+func (h *ReadingListHandler) UpdateReadingList(c *gin.Context) {
+	id, ok := readingListID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateReadingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	list, err := h.service.Update(c.Request.Context(), actor, id, service.ReadingListParams{
+		Name:        req.Name,
+		Description: req.Description,
+		Public:      req.IsPublic,
+	})
+	if err != nil {
+		sendReadingListError(c, err)
+		return
+	}
+	util.SendOK(c, "Reading list updated successfully", list)
+}
+
+// DeleteReadingList godoc
+// @Summary Delete a reading list
+// @Description Delete a reading list and its items. Only the owner or an admin may delete it.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Success 204 {object} util.Response "Reading list deleted successfully"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Reading list not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /reading-lists/{id} [delete]
+func (h *ReadingListHandler) DeleteReadingList(c *gin.Context) {
+	id, ok := readingListID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	if err := h.service.Delete(c.Request.Context(), actor, id); err != nil {
+		sendReadingListError(c, err)
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// AddReadingListItem godoc
+// @Summary Add a book to a reading list
+// @Description Add a book to the end of a reading list with an optional note. Only the owner or an admin may change the list.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Param item body AddReadingListItemRequest true "Item data"
+// @Success 201 {object} util.Response "Item added successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Reading list or book not found"
+// @Failure 409 {object} util.Response "Book already on list"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /reading-lists/{id}/items [post]
+func (h *ReadingListHandler) AddReadingListItem(c *gin.Context) {
+	id, ok := readingListID(c)
+	if !ok {
+		return
+	}
+
+	var req AddReadingListItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	item, err := h.service.AddItem(c.Request.Context(), actor, id, req.BookID, req.Note)
+	if err != nil {
+		sendReadingListError(c, err)
+		return
+	}
+	util.SendCreated(c, "Item added successfully", item)
+}
+
+// UpdateReadingListItem godoc
+// @Summary Update a reading list item
+// @Description Replace the note on a reading list item. Only the owner or an admin may change the list.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Param itemId path string true "Item ID"
+// @Param item body UpdateReadingListItemRequest true "Item data"
+// @Success 200 {object} util.Response "Item updated successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Item not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /reading-lists/{id}/items/{itemId} [put]
+func (h *ReadingListHandler) UpdateReadingListItem(c *gin.Context) {
+	id, itemID, ok := readingListItemID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateReadingListItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	item, err := h.service.UpdateItem(c.Request.Context(), actor, id, itemID, req.Note)
+	if err != nil {
+		sendReadingListError(c, err)
+		return
+	}
+	util.SendOK(c, "Item updated successfully", item)
+}
+
+// RemoveReadingListItem godoc
+// @Summary Remove a book from a reading list
+// @Description Remove an item from a reading list; the items after it move up one place. Only the owner or an admin may change the list.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Param itemId path string true "Item ID"
+// @Success 204 {object} util.Response "Item removed successfully"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Item not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /reading-lists/{id}/items/{itemId} [delete]
+func (h *ReadingListHandler) RemoveReadingListItem(c *gin.Context) {
+	id, itemID, ok := readingListItemID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	if err := h.service.RemoveItem(c.Request.Context(), actor, id, itemID); err != nil {
+		sendReadingListError(c, err)
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// ReorderReadingList godoc
+// @Summary Reorder a reading list
+// @Description Put a reading list's items in a new order. The request must list every item ID exactly once. Only the owner or an admin may change the list.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Param order body ReorderReadingListRequest true "New item order"
+// @Success 200 {object} util.Response "Reading list reordered successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Reading list not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /reading-lists/{id}/order [put]
+func (h *ReadingListHandler) ReorderReadingList(c *gin.Context) {
+	id, ok := readingListID(c)
+	if !ok {
+		return
+	}
+
+	var req ReorderReadingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	details, err := h.service.Reorder(c.Request.Context(), actor, id, req.ItemIDs)
+	if err != nil {
+		sendReadingListError(c, err)
+		return
+	}
+	util.SendOK(c, "Reading list reordered successfully", details)
+}
+
+// HoldReadingList godoc
+// @Summary Place holds on a reading list's unavailable books
+// @Description Place a hold for the list's owner on every item with no copy on the shelf. Items that already have an open hold, or whose hold fails, are reported as skipped. Only the owner or an admin may place them.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param id path string true "Reading list ID"
+// @Success 200 {object} util.Response "Holds placed"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Reading list not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /reading-lists/{id}/holds [post]
+func (h *ReadingListHandler) HoldReadingList(c *gin.Context) {
+	id, ok := readingListID(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	holds, err := h.service.HoldUnavailable(c.Request.Context(), actor, id)
+	if err != nil {
+		sendReadingListError(c, err)
+		return
+	}
+	util.SendOK(c, "Holds placed", holds)
+}
+
+// readingListID parses the reading list ID in the path, sending a bad request response if it is malformed
+func readingListID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid reading list ID", err.Error())
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// readingListItemID parses the reading list and item IDs in the path
+func readingListItemID(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, ok := readingListID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	itemID, err := uuid.Parse(c.Param("itemId"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid item ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, itemID, true
+}
+
+func sendReadingListError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrReadingListExists):
+		util.SendError(c, http.StatusConflict, "Reading list already exists", err.Error())
+	case errors.Is(err, service.ErrReadingListItemExists):
+		util.SendError(c, http.StatusConflict, "Book already on list", err.Error())
+	case errors.Is(err, service.ErrReadingListForbidden):
+		util.SendForbidden(c)
+	case errors.Is(err, service.ErrReadingListOrder):
+		util.SendBadRequest(c, "Invalid order", err.Error())
+	case errors.Is(err, pgx.ErrNoRows):
+		util.SendNotFound(c, err.Error())
+	default:
+		util.SendInternalServerError(c, err.Error())
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// RecommendationHandler handles HTTP requests for book recommendations.
+type RecommendationHandler struct {
+	service service.RecommendationService
+}
+
+// NewRecommendationHandler creates a new RecommendationHandler.
+func NewRecommendationHandler(s service.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{
+		service: s,
+	}
+}
+
+// GetUserRecommendations godoc
+// @Summary Get a user's recommendations
+// @Description Get a paginated list of books recommended to a user, best first, from what other borrowers of their books borrowed, shared authors and categories, and ratings. Books the user has borrowed or reviewed are left out. Recommendations are recomputed periodically, so new members may have none yet. Only the user and admins may see them.
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Recommendations retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /users/{id}/recommendations [get]
+func (h *RecommendationHandler) GetUserRecommendations(c *gin.Context) {
+	id, ok := selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	recommendations, err := h.service.ForUser(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Recommendations retrieved successfully", recommendations)
+}
+
+// ListAlsoBorrowed godoc
+// @Summary List books also borrowed
+// @Description Get the books most often borrowed by members who borrowed this book, most similar first.
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param limit query int false "Limit" default(10)
+// @Success 200 {object} util.Response "Books retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /books/{id}/also-borrowed [get]
+func (h *RecommendationHandler) ListAlsoBorrowed(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	limit, ok := parseLimit(c)
+	if !ok {
+		return
+	}
+
+	books, err := h.service.AlsoBorrowed(c.Request.Context(), id, limit)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Books retrieved successfully", books)
+}
+
+// RefreshRecommendations godoc
+// @Summary Refresh recommendations
+// @Description Recompute book similarities and every member's recommendations now instead of waiting for the background job. Requires an admin.
+// @Tags recommendations
+// @Accept json
+// @Produce json
+// @Success 200 {object} util.Response "Recommendations refreshed"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /recommendations/refresh [post]
+func (h *RecommendationHandler) RefreshRecommendations(c *gin.Context) {
+	if err := h.service.Refresh(c.Request.Context()); err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Recommendations refreshed", nil)
+}
@@ -0,0 +1,491 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/middleware"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// ReviewHandler handles HTTP requests for book reviews.
+type ReviewHandler struct {
+	service service.ReviewService
+}
+
+// NewReviewHandler creates a new ReviewHandler.
+func NewReviewHandler(s service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		service: s,
+	}
+}
+
+// CreateReviewRequest represents the expected request payload for reviewing a book.
+type CreateReviewRequest struct {
+	Rating     int32  `json:"rating" binding:"required,min=1,max=5"`
+	ReviewText string `json:"review_text"`
+}
+
+// UpdateReviewRequest represents the expected request payload for updating a review.
+// Omit review_text to keep the current text.
+type UpdateReviewRequest struct {
+	Rating     int32   `json:"rating" binding:"required,min=1,max=5"`
+	ReviewText *string `json:"review_text,omitempty"`
+}
+
+// ReportReviewRequest represents the expected request payload for reporting a review.
+type ReportReviewRequest struct {
+	Reason  string `json:"reason" binding:"required,oneof=spam offensive spoiler off_topic other"`
+	Details string `json:"details"`
+}
+
+// VoteReviewRequest represents the expected request payload for voting on a review.
+type VoteReviewRequest struct {
+	Helpful *bool `json:"helpful" binding:"required"`
+}
+
+// ModerateReviewRequest represents the expected request payload for a moderation decision.
+type ModerateReviewRequest struct {
+	Action string `json:"action" binding:"required,oneof=approve reject hide"`
+	Note   string `json:"note"`
+}
+
+// ListBookReviews godoc
+// @Summary List a book's reviews
+// @Description Get a paginated list of a book's published reviews. Sort by helpful (most net helpful votes first), recent (newest first, the default) or rating (highest first). Reviews by members who have borrowed and returned the book are marked verified_borrower.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param sort query string false "Sort order (helpful, recent or rating)" default(recent)
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Reviews retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /books/{id}/reviews [get]
+func (h *ReviewHandler) ListBookReviews(c *gin.Context) {
+	idParam := c.Param("id")
+	bookID, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	reviews, err := h.service.ListByBookID(c.Request.Context(), bookID, c.Query("sort"), limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrReviewSort) {
+			util.SendBadRequest(c, "Invalid sort", err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Reviews retrieved successfully", reviews)
+}
+
+// GetBookReview godoc
+// @Summary Get a review
+// @Description Get one of a book's reviews. Reviews that are not published are only visible to their author and admins.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param reviewId path string true "Review ID"
+// @Success 200 {object} util.Response "Review found"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 404 {object} util.Response "Review not found"
+// @Router /books/{id}/reviews/{reviewId} [get]
+func (h *ReviewHandler) GetBookReview(c *gin.Context) {
+	review, ok := h.bookReview(c)
+	if !ok {
+		return
+	}
+	util.SendOK(c, "Review found", review)
+}
+
+// CreateBookReview godoc
+// @Summary Review a book
+// @Description Review a book as the authenticated user. Each user may review a book once. Reviews containing blocked words are held for moderation, and users with too many recently rejected reviews may not post.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param review body CreateReviewRequest true "Review data"
+// @Success 201 {object} util.Response "Review created successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 404 {object} util.Response "Book not found"
+// @Failure 409 {object} util.Response "Book already reviewed"
+// @Failure 429 {object} util.Response "Too many rejected reviews"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/reviews [post]
+func (h *ReviewHandler) CreateBookReview(c *gin.Context) {
+	idParam := c.Param("id")
+	bookID, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	var req CreateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	review, err := h.service.Create(c.Request.Context(), repository.CreateReviewParams{
+		BookID:     bookID,
+		UserID:     actor.ID,
+		Rating:     req.Rating,
+		ReviewText: util.StringToPgText(req.ReviewText),
+	})
+	if err != nil {
+		sendReviewError(c, err)
+		return
+	}
+	if review.Status == service.ReviewStatusPending {
+		util.SendCreated(c, "Review submitted for moderation", review)
+		return
+	}
+	util.SendCreated(c, "Review created successfully", review)
+}
+
+// UpdateBookReview godoc
+// @Summary Update a review
+// @Description Update a review's rating and text. Only the reviewer or an admin may update it. Text containing blocked words, or an edit to a rejected review, sends it back to moderation.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param reviewId path string true "Review ID"
+// @Param review body UpdateReviewRequest true "Review data"
+// @Success 200 {object} util.Response "Review updated successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Review not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/reviews/{reviewId} [put]
+func (h *ReviewHandler) UpdateBookReview(c *gin.Context) {
+	review, ok := h.bookReview(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	updated, err := h.service.Update(c.Request.Context(), actor, review.ID, req.Rating, req.ReviewText)
+	if err != nil {
+		sendReviewError(c, err)
+		return
+	}
+	util.SendOK(c, "Review updated successfully", updated)
+}
+
+// DeleteBookReview godoc
+// @Summary Delete a review
+// @Description Delete a review. Only the reviewer or an admin may delete it.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param reviewId path string true "Review ID"
+// @Success 204 {object} util.Response "Review deleted successfully"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Review not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/reviews/{reviewId} [delete]
+func (h *ReviewHandler) DeleteBookReview(c *gin.Context) {
+	review, ok := h.bookReview(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	if err := h.service.Delete(c.Request.Context(), actor, review.ID); err != nil {
+		sendReviewError(c, err)
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// ListUserReviews godoc
+// @Summary List a user's reviews
+// @Description Get a paginated list of a user's reviews, newest first. The user and admins also see reviews that are not published.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Reviews retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /users/{id}/reviews [get]
+func (h *ReviewHandler) ListUserReviews(c *gin.Context) {
+	idParam := c.Param("id")
+	userID, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	actor, ok := middleware.Actor(c)
+	includeUnpublished := ok && (actor.ID == userID || actor.Role == service.RoleAdmin)
+
+	reviews, err := h.service.ListByUserID(c.Request.Context(), userID, includeUnpublished, limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Reviews retrieved successfully", reviews)
+}
+
+// VoteBookReview godoc
+// @Summary Vote on a review
+// @Description Mark a published review as helpful or unhelpful as the authenticated user. Each user has one vote per review; voting again replaces it. Users cannot vote on their own reviews.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param reviewId path string true "Review ID"
+// @Param vote body VoteReviewRequest true "Vote data"
+// @Success 200 {object} util.Response "Vote recorded successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Own review"
+// @Failure 404 {object} util.Response "Review not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/reviews/{reviewId}/vote [put]
+func (h *ReviewHandler) VoteBookReview(c *gin.Context) {
+	review, ok := h.bookReview(c)
+	if !ok {
+		return
+	}
+
+	var req VoteReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	voted, err := h.service.Vote(c.Request.Context(), actor, review.ID, *req.Helpful)
+	if err != nil {
+		sendReviewError(c, err)
+		return
+	}
+	util.SendOK(c, "Vote recorded successfully", voted)
+}
+
+// UnvoteBookReview godoc
+// @Summary Withdraw a vote on a review
+// @Description Withdraw the authenticated user's helpful or unhelpful vote on a review.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param reviewId path string true "Review ID"
+// @Success 200 {object} util.Response "Vote withdrawn successfully"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Own review"
+// @Failure 404 {object} util.Response "Review not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/reviews/{reviewId}/vote [delete]
+func (h *ReviewHandler) UnvoteBookReview(c *gin.Context) {
+	review, ok := h.bookReview(c)
+	if !ok {
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	unvoted, err := h.service.Unvote(c.Request.Context(), actor, review.ID)
+	if err != nil {
+		sendReviewError(c, err)
+		return
+	}
+	util.SendOK(c, "Vote withdrawn successfully", unvoted)
+}
+
+// ReportBookReview godoc
+// @Summary Report a review
+// @Description Report a published review to the moderators. Each user may report a review once.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param reviewId path string true "Review ID"
+// @Param report body ReportReviewRequest true "Report data"
+// @Success 201 {object} util.Response "Review reported successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 404 {object} util.Response "Review not found"
+// @Failure 409 {object} util.Response "Review already reported"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/reviews/{reviewId}/reports [post]
+func (h *ReviewHandler) ReportBookReview(c *gin.Context) {
+	review, ok := h.bookReview(c)
+	if !ok {
+		return
+	}
+
+	var req ReportReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	report, err := h.service.Report(c.Request.Context(), actor, review.ID, req.Reason, req.Details)
+	if err != nil {
+		sendReviewError(c, err)
+		return
+	}
+	util.SendCreated(c, "Review reported successfully", report)
+}
+
+// GetModerationQueue godoc
+// @Summary Get the review moderation queue
+// @Description Get reviews awaiting a moderator, oldest first: those held for moderation and published ones with open reports, each with its reports. Requires an admin.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Moderation queue retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /moderation/reviews [get]
+func (h *ReviewHandler) GetModerationQueue(c *gin.Context) {
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.service.ModerationQueue(c.Request.Context(), limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Moderation queue retrieved successfully", items)
+}
+
+// ModerateReview godoc
+// @Summary Moderate a review
+// @Description Approve, reject or hide a review with an optional note. Its open reports are resolved. Requires an admin.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param id path string true "Review ID"
+// @Param decision body ModerateReviewRequest true "Moderation decision"
+// @Success 200 {object} util.Response "Review moderated successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Review not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /moderation/reviews/{id} [post]
+func (h *ReviewHandler) ModerateReview(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid review ID", err.Error())
+		return
+	}
+
+	var req ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	actor, _ := middleware.Actor(c)
+	review, err := h.service.Moderate(c.Request.Context(), actor, id, req.Action, req.Note)
+	if err != nil {
+		sendReviewError(c, err)
+		return
+	}
+	util.SendOK(c, "Review moderated successfully", review)
+}
+
+// bookReview loads the review named in the path, sending an error response unless it belongs to the book in the path
+func (h *ReviewHandler) bookReview(c *gin.Context) (*service.Review, bool) {
+	bookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return nil, false
+	}
+	reviewID, err := uuid.Parse(c.Param("reviewId"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid review ID", err.Error())
+		return nil, false
+	}
+
+	review, err := h.service.GetByID(c.Request.Context(), reviewID)
+	if err != nil {
+		sendReviewError(c, err)
+		return nil, false
+	}
+	actor, _ := middleware.Actor(c)
+	if review.BookID != bookID || !service.ReviewVisibleTo(&review.BookReview, actor) {
+		util.SendNotFound(c, "Review not found")
+		return nil, false
+	}
+	return review, true
+}
+
+func sendReviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrReviewExists):
+		util.SendError(c, http.StatusConflict, "Book already reviewed", err.Error())
+	case errors.Is(err, service.ErrReviewReported):
+		util.SendError(c, http.StatusConflict, "Review already reported", err.Error())
+	case errors.Is(err, service.ErrReviewForbidden):
+		util.SendForbidden(c)
+	case errors.Is(err, service.ErrReviewOwnVote):
+		util.SendError(c, http.StatusForbidden, "Own review", err.Error())
+	case errors.Is(err, service.ErrReviewRateLimited):
+		util.SendError(c, http.StatusTooManyRequests, "Too many rejected reviews", err.Error())
+	case errors.Is(err, service.ErrModerationAction):
+		util.SendBadRequest(c, "Invalid moderation action", err.Error())
+	case errors.Is(err, pgx.ErrNoRows):
+		util.SendNotFound(c, err.Error())
+	default:
+		util.SendInternalServerError(c, err.Error())
+	}
+}
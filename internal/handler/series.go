@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// SeriesHandler handles HTTP requests for series.
+type SeriesHandler struct {
+	service service.SeriesService
+}
+
+// NewSeriesHandler creates a new SeriesHandler.
+func NewSeriesHandler(s service.SeriesService) *SeriesHandler {
+	return &SeriesHandler{
+		service: s,
+	}
+}
+
+// SeriesRequest represents the expected request payload for creating or updating a series.
+type SeriesRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// SeriesVolumeRequest represents the expected request payload for placing a work in a series.
+type SeriesVolumeRequest struct {
+	Volume int32 `json:"volume" binding:"required,min=1"`
+}
+
+// GetSeries godoc
+// @Summary Get series by ID
+// @Description Get a series with its volumes in reading order and the available copies of each.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "Series ID"
+// @Success 200 {object} util.Response "Series found"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 404 {object} util.Response "Series not found"
+// @Router /series/{id} [get]
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid series ID", err.Error())
+		return
+	}
+
+	series, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		util.SendNotFound(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Series found", series)
+}
+
+// ListSeries godoc
+// @Summary List series
+// @Description Get a paginated list of series, ordered by name.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Series retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /series [get]
+func (h *SeriesHandler) ListSeries(c *gin.Context) {
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.service.List(c.Request.Context(), limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Series retrieved successfully", series)
+}
+
+// CreateSeries godoc
+// @Summary Create a series
+// @Description Create a new series. Requires an admin.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param series body SeriesRequest true "Series data"
+// @Success 201 {object} util.Response "Series created successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 409 {object} util.Response "Series already exists"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /series [post]
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	var req SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	series, err := h.service.Create(c.Request.Context(), req.Name, req.Description)
+	if err != nil {
+		if errors.Is(err, service.ErrSeriesExists) {
+			util.SendError(c, http.StatusConflict, "Series already exists", err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendCreated(c, "Series created successfully", series)
+}
+
+// UpdateSeries godoc
+// @Summary Update a series
+// @Description Rename a series or change its description. Requires an admin.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "Series ID"
+// @Param series body SeriesRequest true "Series data"
+// @Success 200 {object} util.Response "Series updated successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 409 {object} util.Response "Series already exists"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /series/{id} [put]
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid series ID", err.Error())
+		return
+	}
+
+	var req SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	series, err := h.service.Update(c.Request.Context(), id, req.Name, req.Description)
+	if err != nil {
+		if errors.Is(err, service.ErrSeriesExists) {
+			util.SendError(c, http.StatusConflict, "Series already exists", err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Series updated successfully", series)
+}
+
+// DeleteSeries godoc
+// @Summary Delete a series
+// @Description Delete a series. Its works are kept. Requires an admin.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "Series ID"
+// @Success 204 {object} util.Response "Series deleted successfully"
+// @Failure 400 {object} util.Response "Invalid series ID"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /series/{id} [delete]
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid series ID", err.Error())
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// SetSeriesVolume godoc
+// @Summary Place a work in a series
+// @Description Add a work to a series at the given volume number, or move it if it is already a member. Requires an admin.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "Series ID"
+// @Param workId path string true "Work ID"
+// @Param volume body SeriesVolumeRequest true "Volume number"
+// @Success 204 {object} util.Response "Volume set successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /series/{id}/works/{workId} [put]
+func (h *SeriesHandler) SetSeriesVolume(c *gin.Context) {
+	seriesID, workID, ok := parseSeriesWorkIDs(c)
+	if !ok {
+		return
+	}
+
+	var req SeriesVolumeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	if err := h.service.SetVolume(c.Request.Context(), seriesID, workID, req.Volume); err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// RemoveSeriesWork godoc
+// @Summary Remove a work from a series
+// @Description Remove a work from a series. Requires an admin.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "Series ID"
+// @Param workId path string true "Work ID"
+// @Success 204 {object} util.Response "Work removed successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /series/{id}/works/{workId} [delete]
+func (h *SeriesHandler) RemoveSeriesWork(c *gin.Context) {
+	seriesID, workID, ok := parseSeriesWorkIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveWork(c.Request.Context(), seriesID, workID); err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// parseSeriesWorkIDs reads the series and work IDs from the path
+func parseSeriesWorkIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	seriesID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid series ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	workID, err := uuid.Parse(c.Param("workId"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid work ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	return seriesID, workID, true
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// ShelfHandler handles HTTP requests for classification and shelf browsing.
+type ShelfHandler struct {
+	service service.ShelfService
+}
+
+// NewShelfHandler creates a new ShelfHandler.
+func NewShelfHandler(s service.ShelfService) *ShelfHandler {
+	return &ShelfHandler{
+		service: s,
+	}
+}
+
+// ClassificationRequest represents the expected request payload for classifying a book.
+// Both fields replace the stored values; leave one empty to clear it.
+type ClassificationRequest struct {
+	DeweyDecimalClass string `json:"dewey_decimal_class"`
+	LCClassification  string `json:"lc_classification"`
+}
+
+// BrowseShelf godoc
+// @Summary Browse the shelf
+// @Description Get books in call-number order between two call numbers, like walking the stacks. Dewey numbers come before LC classes. Either end may be partial, e.g. "800" or "PR"; the end of the range includes everything filed under it.
+// @Tags shelf
+// @Accept json
+// @Produce json
+// @Param from query string false "First call number"
+// @Param to query string false "Last call number"
+// @Param scheme query string false "Scheme of from and to (dewey or lc); inferred when omitted"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Shelf retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /shelf [get]
+func (h *ShelfHandler) BrowseShelf(c *gin.Context) {
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.service.Browse(c.Request.Context(), c.Query("from"), c.Query("to"), c.Query("scheme"), limit, offset)
+	if err != nil {
+		if errors.Is(err, service.ErrShelfScheme) {
+			util.SendBadRequest(c, "Invalid scheme", err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Shelf retrieved successfully", entries)
+}
+
+// ClassifyBook godoc
+// @Summary Classify a book
+// @Description Set a book's Dewey and LC classifications and regenerate its call number. Requires an admin.
+// @Tags shelf
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param classification body ClassificationRequest true "Classification data"
+// @Success 200 {object} util.Response "Book classified successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Book not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /books/{id}/classification [put]
+func (h *ShelfHandler) ClassifyBook(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid book ID", err.Error())
+		return
+	}
+
+	var req ClassificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	entry, err := h.service.Classify(c.Request.Context(), id, req.DeweyDecimalClass, req.LCClassification)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			util.SendNotFound(c, err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Book classified successfully", entry)
+}
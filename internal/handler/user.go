@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/middleware"
 	"github.com/vasujain275/bookbridge-api/internal/repository"
 	"github.com/vasujain275/bookbridge-api/internal/service"
 	"github.com/vasujain275/bookbridge-api/internal/util"
@@ -26,8 +29,8 @@ func NewUserHandler(s service.UserService) *UserHandler {
 type CreateUserRequest struct {
 	Username  string `json:"username" binding:"required"`
 	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required"`
-	Role      string `json:"role" binding:"required"`
+	Password  string `json:"password" binding:"required,max=72"`
+	Role      string `json:"role" binding:"required,oneof=admin member"`
 	FirstName string `json:"first_name" binding:"required"`
 	LastName  string `json:"last_name" binding:"required"`
 }
@@ -36,25 +39,31 @@ type CreateUserRequest struct {
 type UpdateUserRequest struct {
 	Username string `json:"username,omitempty"`
 	Email    string `json:"email,omitempty"`
-	Password string `json:"password,omitempty"`
+	Password string `json:"password,omitempty" binding:"omitempty,max=72"`
+}
+
+// HistoryRetentionRequest represents the expected request payload for opting in to or out of reading history retention.
+type HistoryRetentionRequest struct {
+	RetainHistory *bool `json:"retain_history" binding:"required"`
 }
 
 // GetUser godoc
 // @Summary Get user by ID
-// @Description Get a user by its ID.
+// @Description Get a user by its ID. Only the user and admins may see it.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
 // @Success 200 {object} util.Response "User found"
 // @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
 // @Failure 404 {object} util.Response "User not found"
+// @Security BasicAuth
 // @Router /users/{id} [get]
 func (h *UserHandler) GetUser(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
-	if err != nil {
-		util.SendBadRequest(c, "Invalid user ID", err.Error())
+	id, ok := selfOrAdmin(c)
+	if !ok {
 		return
 	}
 
@@ -68,14 +77,17 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 
 // ListUsers godoc
 // @Summary List users
-// @Description Get a paginated list of users.
+// @Description Get a paginated list of users. Admins only.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param limit query int false "Limit" default(10)
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} util.Response "List of users"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
 // @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
 // @Router /users [get]
 func (h *UserHandler) ListUsers(c *gin.Context) {
 	// Default values
@@ -103,14 +115,17 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 
 // CreateUser godoc
 // @Summary Create user
-// @Description Create a new user.
+// @Description Create a new user with the role admin or member. The password is stored hashed. Admins only.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param user body CreateUserRequest true "User data"
 // @Success 201 {object} util.Response "User created successfully"
 // @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
 // @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
 // @Router /users [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req CreateUserRequest
@@ -139,7 +154,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 
 // UpdateUser godoc
 // @Summary Update user
-// @Description Update an existing user.
+// @Description Update an existing user's username, email or password; fields left out are unchanged. Only the user and admins may update it.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -147,14 +162,15 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 // @Param user body UpdateUserRequest true "Updated user data"
 // @Success 200 {object} util.Response "User updated successfully"
 // @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
 // @Failure 404 {object} util.Response "User not found"
 // @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
 // @Router /users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
-	if err != nil {
-		util.SendBadRequest(c, "Invalid user ID", err.Error())
+	id, ok := selfOrAdmin(c)
+	if !ok {
 		return
 	}
 
@@ -181,14 +197,17 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 
 // DeleteUser godoc
 // @Summary Delete user
-// @Description Delete a user by its ID.
+// @Description Delete a user by its ID. Admins only.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
 // @Success 204 {object} util.Response "User deleted successfully"
 // @Failure 400 {object} util.Response "Invalid user ID"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
 // @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
 // @Router /users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idParam := c.Param("id")
@@ -204,3 +223,103 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	}
 	util.SendNoContent(c)
 }
+
+// GetUserStats godoc
+// @Summary Get a user's reading statistics
+// @Description Get a user's reading statistics: books and pages read overall and per month of a year, favourite categories and authors, average loan duration and reading streaks in months. Only the user and admins may see them.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param year query int false "Year to break down by month; defaults to the current year"
+// @Success 200 {object} util.Response "Statistics retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "User not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /users/{id}/stats [get]
+func (h *UserHandler) GetUserStats(c *gin.Context) {
+	id, ok := selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	year, err := strconv.Atoi(c.DefaultQuery("year", "0"))
+	if err != nil || year < 0 {
+		util.SendBadRequest(c, "Invalid year parameter", "year must be a positive integer")
+		return
+	}
+
+	stats, err := h.service.Stats(c.Request.Context(), id, year)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			util.SendNotFound(c, err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Statistics retrieved successfully", stats)
+}
+
+// SetHistoryRetention godoc
+// @Summary Opt in to or out of reading history retention
+// @Description Choose whether the library keeps a user's reading history. Opting out erases the dates of the user's returned loans. The library keeps only which books the user borrowed, so their reviews stay marked as verified and recommendations skip books already read; reading statistics then count those books only through reviews. Only the user and admins may change it.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param retention body HistoryRetentionRequest true "Retention choice"
+// @Success 200 {object} util.Response "History retention updated"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "User not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /users/{id}/history-retention [put]
+func (h *UserHandler) SetHistoryRetention(c *gin.Context) {
+	id, ok := selfOrAdmin(c)
+	if !ok {
+		return
+	}
+
+	var req HistoryRetentionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	user, err := h.service.SetHistoryRetention(c.Request.Context(), id, *req.RetainHistory)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			util.SendNotFound(c, err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "History retention updated", user)
+}
+
+// selfOrAdmin parses the user ID in the path, sending an error response unless the authenticated
+// user is that user or an admin
+func selfOrAdmin(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid user ID", err.Error())
+		return uuid.Nil, false
+	}
+	actor, ok := middleware.Actor(c)
+	if !ok {
+		util.SendUnauthorized(c)
+		return uuid.Nil, false
+	}
+	if actor.ID != id && actor.Role != service.RoleAdmin {
+		util.SendForbidden(c)
+		return uuid.Nil, false
+	}
+	return id, true
+}
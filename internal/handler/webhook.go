@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// WebhookHandler handles HTTP requests for webhook registration and deliveries.
+type WebhookHandler struct {
+	service service.WebhookService
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(s service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: s,
+	}
+}
+
+// CreateWebhookRequest represents the expected request payload for registering a webhook.
+// A random secret is generated when none is given. Webhooks are active unless is_active is false.
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Secret      string   `json:"secret"`
+	EventTypes  []string `json:"event_types" binding:"required"`
+	Description string   `json:"description"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// CreatedWebhookResponse is a newly registered webhook with its secret, which is not shown again
+type CreatedWebhookResponse struct {
+	*repository.Webhook
+	Secret string `json:"secret"`
+}
+
+// UpdateWebhookRequest represents the expected request payload for updating a webhook.
+type UpdateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	EventTypes  []string `json:"event_types" binding:"required"`
+	Description string   `json:"description"`
+	IsActive    *bool    `json:"is_active" binding:"required"`
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description Get every registered webhook with its subscribed event types. Secrets are only shown when a webhook is registered. Requires an admin.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {object} util.Response "Webhooks retrieved successfully"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.service.List(c.Request.Context())
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Webhooks retrieved successfully", webhooks)
+}
+
+// GetWebhook godoc
+// @Summary Get webhook by ID
+// @Description Get a registered webhook by its ID. Requires an admin.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} util.Response "Webhook found"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Webhook not found"
+// @Security BasicAuth
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid webhook ID", err.Error())
+		return
+	}
+
+	webhook, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		util.SendNotFound(c, "Webhook not found")
+		return
+	}
+	util.SendOK(c, "Webhook found", webhook)
+}
+
+// CreateWebhook godoc
+// @Summary Register a webhook
+// @Description Register an endpoint to receive domain events: book.created, loan.overdue, hold.ready, user.created and review.created, which is sent when a review is published rather than while it awaits moderation. Each event is POSTed as JSON with X-BookBridge-Event, X-BookBridge-Delivery and X-BookBridge-Timestamp headers, and an X-BookBridge-Signature header of "sha256=" and the hex HMAC-SHA256 of the timestamp, a period and the body, keyed with the webhook's secret. The secret is only returned in this response. Any response other than 2xx is retried with exponential backoff. Requires an admin.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body CreateWebhookRequest true "Webhook data"
+// @Success 201 {object} util.Response "Webhook registered successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	webhook, err := h.service.Create(c.Request.Context(), service.WebhookParams{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookURL) || errors.Is(err, service.ErrWebhookEventTypes) {
+			util.SendBadRequest(c, "Invalid webhook", err.Error())
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendCreated(c, "Webhook registered successfully", CreatedWebhookResponse{Webhook: webhook, Secret: webhook.Secret})
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Change a webhook's URL, subscribed event types, description and whether it is active. The secret is kept. Requires an admin.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param webhook body UpdateWebhookRequest true "Webhook data"
+// @Success 200 {object} util.Response "Webhook updated successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Webhook not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid webhook ID", err.Error())
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.SendBadRequest(c, "Invalid request payload", err.Error())
+		return
+	}
+
+	webhook, err := h.service.Update(c.Request.Context(), id, service.WebhookParams{
+		URL:         req.URL,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		IsActive:    *req.IsActive,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWebhookURL), errors.Is(err, service.ErrWebhookEventTypes):
+			util.SendBadRequest(c, "Invalid webhook", err.Error())
+		case errors.Is(err, pgx.ErrNoRows):
+			util.SendNotFound(c, "Webhook not found")
+		default:
+			util.SendInternalServerError(c, err.Error())
+		}
+		return
+	}
+	util.SendOK(c, "Webhook updated successfully", webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Remove a webhook and its delivery log. Requires an admin.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Webhook not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid webhook ID", err.Error())
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			util.SendNotFound(c, "Webhook not found")
+			return
+		}
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendNoContent(c)
+}
+
+// ListWebhookDeliveries godoc
+// @Summary List a webhook's deliveries
+// @Description Get a paginated log of the events sent or waiting to be sent to a webhook, newest first, with attempts, the last response status and error. Requires an admin.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Delivery status" Enums(pending, delivered, failed)
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Deliveries retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Webhook not found"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid webhook ID", err.Error())
+		return
+	}
+
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.service.ListDeliveries(c.Request.Context(), id, c.Query("status"), limit, offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWebhookDeliveryStatus):
+			util.SendBadRequest(c, "Invalid status parameter", err.Error())
+		case errors.Is(err, pgx.ErrNoRows):
+			util.SendNotFound(c, "Webhook not found")
+		default:
+			util.SendInternalServerError(c, err.Error())
+		}
+		return
+	}
+	util.SendOK(c, "Deliveries retrieved successfully", deliveries)
+}
+
+// RedeliverWebhookDelivery godoc
+// @Summary Redeliver a webhook delivery
+// @Description Send a delivered or failed event to its webhook once more, straight away, with a fresh signature. Requires an admin.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 200 {object} util.Response "Delivery queued"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 401 {object} util.Response "Unauthorized"
+// @Failure 403 {object} util.Response "Forbidden"
+// @Failure 404 {object} util.Response "Delivery not found"
+// @Failure 409 {object} util.Response "Delivery already pending"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Security BasicAuth
+// @Router /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhookDelivery(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid webhook ID", err.Error())
+		return
+	}
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		util.SendBadRequest(c, "Invalid delivery ID", err.Error())
+		return
+	}
+
+	delivery, err := h.service.Redeliver(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			util.SendNotFound(c, "Delivery not found")
+		case errors.Is(err, service.ErrWebhookDeliveryPending):
+			util.SendError(c, http.StatusConflict, "Delivery already pending", err.Error())
+		default:
+			util.SendInternalServerError(c, err.Error())
+		}
+		return
+	}
+	util.SendOK(c, "Delivery queued", delivery)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// WorkHandler handles HTTP requests for works.
+type WorkHandler struct {
+	service service.WorkService
+}
+
+// NewWorkHandler creates a new WorkHandler.
+func NewWorkHandler(s service.WorkService) *WorkHandler {
+	return &WorkHandler{
+		service: s,
+	}
+}
+
+// GetWork godoc
+// @Summary Get work by ID
+// @Description Get a work with all of its editions.
+// @Tags works
+// @Accept json
+// @Produce json
+// @Param id path string true "Work ID"
+// @Success 200 {object} util.Response "Work found"
+// @Failure 400 {object} util.Response "Invalid ID supplied"
+// @Failure 404 {object} util.Response "Work not found"
+// @Router /works/{id} [get]
+func (h *WorkHandler) GetWork(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		util.SendBadRequest(c, "Invalid work ID", err.Error())
+		return
+	}
+
+	work, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		util.SendNotFound(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Work found", work)
+}
+
+// SearchWorks godoc
+// @Summary Search works
+// @Description Search the catalog, returning one result per work with its editions listed.
+// @Tags works
+// @Accept json
+// @Produce json
+// @Param q query string false "Search query"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} util.Response "Works retrieved successfully"
+// @Failure 400 {object} util.Response "Invalid request"
+// @Failure 500 {object} util.Response "Internal server error"
+// @Router /works [get]
+func (h *WorkHandler) SearchWorks(c *gin.Context) {
+	limit, offset, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	works, err := h.service.Search(c.Request.Context(), c.Query("q"), limit, offset)
+	if err != nil {
+		util.SendInternalServerError(c, err.Error())
+		return
+	}
+	util.SendOK(c, "Works retrieved successfully", works)
+}
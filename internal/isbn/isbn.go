@@ -0,0 +1,160 @@
+package isbn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalid is wrapped by every validation error so callers can match on it
+	ErrInvalid = errors.New("invalid ISBN")
+	// ErrInvalidLength is returned when an ISBN is neither 10 nor 13 characters after normalization
+	ErrInvalidLength = fmt.Errorf("%w: must have 10 or 13 digits", ErrInvalid)
+	// ErrInvalidCharacters is returned when an ISBN contains anything but digits (and a final X for ISBN-10)
+	ErrInvalidCharacters = fmt.Errorf("%w: contains invalid characters", ErrInvalid)
+	// ErrInvalidChecksum is returned when the check digit does not match
+	ErrInvalidChecksum = fmt.Errorf("%w: check digit does not match", ErrInvalid)
+	// ErrNoISBN10 is returned when converting a 979-prefixed ISBN-13, which has no ISBN-10 form
+	ErrNoISBN10 = errors.New("ISBN-13 has no ISBN-10 equivalent")
+)
+
+// ISBN holds both forms of a validated ISBN. ISBN10 is empty for 979-prefixed ISBNs.
+type ISBN struct {
+	ISBN10 string `json:"isbn_10,omitempty"`
+	ISBN13 string `json:"isbn_13"`
+}
+
+// Normalize strips hyphens and whitespace and upper-cases a trailing x
+func Normalize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '-' || r == ' ' || r == '\t' || r == '‐' || r == '‑':
+			continue
+		case r == 'x':
+			b.WriteRune('X')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Parse normalizes and validates an ISBN in either form and returns both forms
+func Parse(s string) (ISBN, error) {
+	n := Normalize(s)
+	switch len(n) {
+	case 10:
+		if err := validate10(n); err != nil {
+			return ISBN{}, err
+		}
+		return ISBN{ISBN10: n, ISBN13: convert10To13(n)}, nil
+	case 13:
+		if err := validate13(n); err != nil {
+			return ISBN{}, err
+		}
+		isbn := ISBN{ISBN13: n}
+		if strings.HasPrefix(n, "978") {
+			isbn.ISBN10 = convert13To10(n)
+		}
+		return isbn, nil
+	default:
+		return ISBN{}, fmt.Errorf("%w: got %d", ErrInvalidLength, len(n))
+	}
+}
+
+// IsValid reports whether s is a valid ISBN-10 or ISBN-13
+func IsValid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// To13 converts an ISBN in either form to ISBN-13
+func To13(s string) (string, error) {
+	isbn, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return isbn.ISBN13, nil
+}
+
+// To10 converts an ISBN in either form to ISBN-10
+func To10(s string) (string, error) {
+	isbn, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if isbn.ISBN10 == "" {
+		return "", ErrNoISBN10
+	}
+	return isbn.ISBN10, nil
+}
+
+func validate10(n string) error {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var v int
+		switch c := n[i]; {
+		case c >= '0' && c <= '9':
+			v = int(c - '0')
+		case c == 'X' && i == 9:
+			v = 10
+		default:
+			return ErrInvalidCharacters
+		}
+		sum += v * (10 - i)
+	}
+	if sum%11 != 0 {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+func validate13(n string) error {
+	for i := 0; i < 13; i++ {
+		if n[i] < '0' || n[i] > '9' {
+			return ErrInvalidCharacters
+		}
+	}
+	if checkDigit13(n[:12]) != n[12] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// checkDigit13 computes the ISBN-13 check digit for the first 12 digits
+func checkDigit13(first12 string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(first12[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// checkDigit10 computes the ISBN-10 check digit for the first 9 digits
+func checkDigit10(first9 string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(first9[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+func convert10To13(n string) string {
+	first12 := "978" + n[:9]
+	return first12 + string(checkDigit13(first12))
+}
+
+func convert13To10(n string) string {
+	first9 := n[3:12]
+	return first9 + string(checkDigit10(first9))
+}
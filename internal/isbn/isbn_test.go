@@ -0,0 +1,104 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  ISBN
+		err   error
+	}{
+		{name: "ISBN-10", input: "0306406152", want: ISBN{ISBN10: "0306406152", ISBN13: "9780306406157"}},
+		{name: "ISBN-10 with hyphens", input: "0-306-40615-2", want: ISBN{ISBN10: "0306406152", ISBN13: "9780306406157"}},
+		{name: "ISBN-10 with X check digit", input: "0-8044-2957-X", want: ISBN{ISBN10: "080442957X", ISBN13: "9780804429573"}},
+		{name: "ISBN-10 with lower-case x", input: "080442957x", want: ISBN{ISBN10: "080442957X", ISBN13: "9780804429573"}},
+		{name: "ISBN-13", input: "9780306406157", want: ISBN{ISBN10: "0306406152", ISBN13: "9780306406157"}},
+		{name: "ISBN-13 with spaces", input: "978 0 306 40615 7", want: ISBN{ISBN10: "0306406152", ISBN13: "9780306406157"}},
+		{name: "979 ISBN-13 has no ISBN-10", input: "979-10-90636-07-1", want: ISBN{ISBN13: "9791090636071"}},
+		{name: "ISBN-10 bad checksum", input: "0306406153", err: ErrInvalidChecksum},
+		{name: "ISBN-13 bad checksum", input: "9780306406158", err: ErrInvalidChecksum},
+		{name: "X before the check digit", input: "03064061X2", err: ErrInvalidCharacters},
+		{name: "X in ISBN-13", input: "978030640615X", err: ErrInvalidCharacters},
+		{name: "letters", input: "978030640A157", err: ErrInvalidCharacters},
+		{name: "too short", input: "030640615", err: ErrInvalidLength},
+		{name: "too long", input: "97803064061570", err: ErrInvalidLength},
+		{name: "empty", input: "", err: ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Parse(%q) error = %v, want %v", tt.input, err, tt.err)
+				}
+				if !errors.Is(err, ErrInvalid) {
+					t.Errorf("Parse(%q) error = %v, want it to wrap ErrInvalid", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		input  string
+		want13 string
+		want10 string
+		err10  error
+	}{
+		{input: "0306406152", want13: "9780306406157", want10: "0306406152"},
+		{input: "9780306406157", want13: "9780306406157", want10: "0306406152"},
+		{input: "9780804429573", want13: "9780804429573", want10: "080442957X"},
+		{input: "9791090636071", want13: "9791090636071", err10: ErrNoISBN10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got13, err := To13(tt.input)
+			if err != nil || got13 != tt.want13 {
+				t.Errorf("To13(%q) = %q, %v, want %q", tt.input, got13, err, tt.want13)
+			}
+
+			got10, err := To10(tt.input)
+			if tt.err10 != nil {
+				if !errors.Is(err, tt.err10) {
+					t.Errorf("To10(%q) error = %v, want %v", tt.input, err, tt.err10)
+				}
+				return
+			}
+			if err != nil || got10 != tt.want10 {
+				t.Errorf("To10(%q) = %q, %v, want %q", tt.input, got10, err, tt.want10)
+			}
+		})
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"0306406152", true},
+		{"9780306406157", true},
+		{"0306406151", false},
+		{"not an isbn", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValid(tt.input); got != tt.want {
+			t.Errorf("IsValid(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
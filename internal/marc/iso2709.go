@@ -0,0 +1,213 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+
+	leaderLength         = 24
+	directoryEntryLength = 12
+
+	// defaultLeader describes a new, Unicode-encoded language material monograph.
+	// Record length and base address (positions 0-4 and 12-16) are filled in on write.
+	defaultLeader = "00000nam a2200000 i 4500"
+)
+
+// ErrInvalidRecord is returned when binary MARC data does not follow ISO 2709
+var ErrInvalidRecord = errors.New("invalid MARC21 record")
+
+// MarshalBinary encodes the record as binary MARC21 (ISO 2709)
+func (r *Record) MarshalBinary() ([]byte, error) {
+	var directory, data bytes.Buffer
+
+	for _, f := range r.Fields {
+		if len(f.Tag) != 3 {
+			return nil, fmt.Errorf("invalid tag %q", f.Tag)
+		}
+
+		start := data.Len()
+		if f.IsControl() {
+			data.WriteString(f.Value)
+		} else {
+			data.WriteByte(indicator(f.Ind1))
+			data.WriteByte(indicator(f.Ind2))
+			for _, sf := range f.Subfields {
+				data.WriteByte(subfieldDelimiter)
+				data.WriteByte(sf.Code)
+				data.WriteString(sf.Value)
+			}
+		}
+		data.WriteByte(fieldTerminator)
+
+		length := data.Len() - start
+		if length > 9999 || start > 99999 {
+			return nil, fmt.Errorf("field %s exceeds MARC21 size limits", f.Tag)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", f.Tag, length, start)
+	}
+	directory.WriteByte(fieldTerminator)
+	data.WriteByte(recordTerminator)
+
+	baseAddress := leaderLength + directory.Len()
+	recordLength := baseAddress + data.Len()
+	if recordLength > 99999 {
+		return nil, errors.New("record exceeds MARC21 size limit")
+	}
+
+	leader := []byte(r.Leader)
+	if len(leader) != leaderLength {
+		leader = []byte(defaultLeader)
+	}
+	copy(leader[0:5], fmt.Sprintf("%05d", recordLength))
+	copy(leader[12:17], fmt.Sprintf("%05d", baseAddress))
+
+	out := make([]byte, 0, recordLength)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, data.Bytes()...)
+	return out, nil
+}
+
+// UnmarshalBinary decodes a single binary MARC21 record
+func (r *Record) UnmarshalBinary(raw []byte) error {
+	if len(raw) < leaderLength+1 {
+		return ErrInvalidRecord
+	}
+
+	leader := string(raw[:leaderLength])
+	baseAddress, ok := parseDigits(leader[12:17])
+	if !ok || baseAddress <= leaderLength || baseAddress > len(raw) {
+		return fmt.Errorf("%w: bad base address", ErrInvalidRecord)
+	}
+
+	// The directory runs from the end of the leader up to the field terminator before the base address
+	directory := raw[leaderLength : baseAddress-1]
+	if len(directory)%directoryEntryLength != 0 {
+		return fmt.Errorf("%w: malformed directory", ErrInvalidRecord)
+	}
+
+	fields := make([]Field, 0, len(directory)/directoryEntryLength)
+	for i := 0; i < len(directory); i += directoryEntryLength {
+		entry := directory[i : i+directoryEntryLength]
+		tag := string(entry[0:3])
+		length, ok := parseDigits(string(entry[3:7]))
+		if !ok {
+			return fmt.Errorf("%w: bad length for field %s", ErrInvalidRecord, tag)
+		}
+		start, ok := parseDigits(string(entry[7:12]))
+		if !ok {
+			return fmt.Errorf("%w: bad offset for field %s", ErrInvalidRecord, tag)
+		}
+
+		// Both numbers are unsigned, so the field starts at or after the base address
+		from := baseAddress + start
+		to := from + length
+		if length < 1 || to > len(raw) {
+			return fmt.Errorf("%w: field %s out of bounds", ErrInvalidRecord, tag)
+		}
+		// Drop the trailing field terminator
+		fieldData := raw[from : to-1]
+
+		fields = append(fields, parseField(tag, fieldData))
+	}
+
+	r.Leader = leader
+	r.Fields = fields
+	return nil
+}
+
+// parseDigits parses a fixed-width number from the leader or directory, which may only hold ASCII
+// digits; strconv.Atoi would also accept a sign
+func parseDigits(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		n = n*10 + int(s[i]-'0')
+	}
+	return n, true
+}
+
+func parseField(tag string, data []byte) Field {
+	if isControlTag(tag) {
+		return Field{Tag: tag, Value: string(data)}
+	}
+
+	f := Field{Tag: tag, Ind1: ' ', Ind2: ' '}
+	if len(data) >= 2 {
+		f.Ind1, f.Ind2 = data[0], data[1]
+		data = data[2:]
+	}
+	for _, chunk := range bytes.Split(data, []byte{subfieldDelimiter}) {
+		if len(chunk) == 0 {
+			continue
+		}
+		f.Subfields = append(f.Subfields, Subfield{Code: chunk[0], Value: string(chunk[1:])})
+	}
+	return f
+}
+
+// Writer writes binary MARC21 records to an underlying stream
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter creates a new binary MARC21 writer
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write encodes and writes a single record
+func (w *Writer) Write(r *Record) error {
+	raw, err := r.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(raw)
+	return err
+}
+
+// Reader reads binary MARC21 records from an underlying stream
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader creates a new binary MARC21 reader
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next record, or io.EOF when the stream is exhausted
+func (r *Reader) Read() (*Record, error) {
+	raw, err := r.r.ReadBytes(recordTerminator)
+	if err == io.EOF {
+		if len(bytes.TrimSpace(raw)) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%w: missing record terminator", ErrInvalidRecord)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Tolerate line breaks some tools insert between records
+	raw = bytes.TrimLeft(raw, "\r\n")
+
+	record := &Record{}
+	if err := record.UnmarshalBinary(raw); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,187 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sampleRecord(title string) *Record {
+	r := NewRecord()
+	r.AddControlField("001", "bb-0001")
+	r.AddControlField("008", "240101s2020    xx            000 0 eng d")
+	r.AddDataField("020", ' ', ' ', Subfield{Code: 'a', Value: "9780306406157"})
+	r.AddDataField("100", '1', ' ', Subfield{Code: 'a', Value: "Brontë, Charlotte,"})
+	r.AddDataField("245", '1', '0', Subfield{Code: 'a', Value: title + " :"}, Subfield{Code: 'b', Value: "an autobiography /"})
+	r.AddDataField("650", ' ', '0', Subfield{Code: 'a', Value: "Governesses"})
+	r.AddDataField("650", ' ', '0', Subfield{Code: 'a', Value: "Café society"})
+	return r
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	want := sampleRecord("Jane Eyre")
+
+	raw, err := want.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	if got, _ := parseDigits(string(raw[0:5])); got != len(raw) {
+		t.Errorf("leader record length = %d, want %d", got, len(raw))
+	}
+	if raw[len(raw)-1] != recordTerminator {
+		t.Errorf("record does not end with the record terminator")
+	}
+
+	var got Record
+	if err := got.UnmarshalBinary(raw); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if !reflect.DeepEqual(got.Fields, want.Fields) {
+		t.Errorf("fields after round trip = %+v, want %+v", got.Fields, want.Fields)
+	}
+	if got.Leader[5:12] != defaultLeader[5:12] {
+		t.Errorf("leader = %q, want status and type from %q", got.Leader, defaultLeader)
+	}
+}
+
+func TestReaderWriterRoundTrip(t *testing.T) {
+	want := []*Record{sampleRecord("Jane Eyre"), sampleRecord("Villette")}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, r := range want {
+		if err := w.Write(r); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		// Some tools put line breaks between records
+		buf.WriteString("\r\n")
+	}
+
+	r := NewReader(&buf)
+	for i, wantRecord := range want {
+		got, err := r.Read()
+		if err != nil {
+			t.Fatalf("Read record %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(got.Fields, wantRecord.Fields) {
+			t.Errorf("record %d fields = %+v, want %+v", i, got.Fields, wantRecord.Fields)
+		}
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("Read after last record = %v, want io.EOF", err)
+	}
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	valid, err := sampleRecord("Jane Eyre").MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	baseAddress, _ := parseDigits(string(valid[12:17]))
+
+	// patch returns a copy of the valid record with s written at offset
+	patch := func(offset int, s string) []byte {
+		raw := bytes.Clone(valid)
+		copy(raw[offset:], s)
+		return raw
+	}
+	// The first directory entry is the tag, a four digit length and a five digit start
+	const firstLength, firstStart = leaderLength + 3, leaderLength + 7
+
+	tests := []struct {
+		name string
+		raw  []byte
+	}{
+		{name: "shorter than the leader", raw: valid[:leaderLength]},
+		{name: "base address not a number", raw: patch(12, "00a12")},
+		{name: "signed base address", raw: patch(12, "+0100")},
+		{name: "base address inside the leader", raw: patch(12, "00010")},
+		{name: "base address past the end", raw: patch(12, "99999")},
+		{name: "directory length not a multiple of 12", raw: patch(12, fmt.Sprintf("%05d", baseAddress+1))},
+		{name: "field length not a number", raw: patch(firstLength, "00x7")},
+		{name: "signed field length", raw: patch(firstLength, "-008")},
+		{name: "zero field length", raw: patch(firstLength, "0000")},
+		{name: "field length past the end", raw: patch(firstLength, "9999")},
+		{name: "signed field start", raw: patch(firstStart, "-0001")},
+		{name: "field start past the end", raw: patch(firstStart, "99999")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Record
+			if err := r.UnmarshalBinary(tt.raw); !errors.Is(err, ErrInvalidRecord) {
+				t.Errorf("UnmarshalBinary error = %v, want %v", err, ErrInvalidRecord)
+			}
+		})
+	}
+}
+
+func TestReaderTruncated(t *testing.T) {
+	raw, err := sampleRecord("Jane Eyre").MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	_, err = NewReader(bytes.NewReader(raw[:len(raw)-1])).Read()
+	if !errors.Is(err, ErrInvalidRecord) {
+		t.Errorf("Read error = %v, want %v", err, ErrInvalidRecord)
+	}
+}
+
+func TestXMLRoundTrip(t *testing.T) {
+	want := []*Record{sampleRecord("Jane Eyre"), sampleRecord("Villette & Shirley <1853>")}
+
+	var buf bytes.Buffer
+	w := NewXMLWriter(&buf)
+	for _, r := range want {
+		if err := w.Write(r); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := ReadXML(&buf)
+	if err != nil {
+		t.Fatalf("ReadXML: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ReadXML returned %d records, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i].Fields, want[i].Fields) {
+			t.Errorf("record %d fields = %+v, want %+v", i, got[i].Fields, want[i].Fields)
+		}
+	}
+}
+
+func TestMarshalXMLSingleRecord(t *testing.T) {
+	want := sampleRecord("Jane Eyre")
+
+	out, err := MarshalXML(want)
+	if err != nil {
+		t.Fatalf("MarshalXML: %v", err)
+	}
+	if !strings.Contains(string(out), Namespace) {
+		t.Errorf("MarshalXML output does not declare the MARCXML namespace:\n%s", out)
+	}
+
+	got, err := ReadXML(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("ReadXML: %v", err)
+	}
+	if len(got) != 1 || !reflect.DeepEqual(got[0].Fields, want.Fields) {
+		t.Errorf("ReadXML = %+v, want one record with fields %+v", got, want.Fields)
+	}
+}
+
+func TestReadXMLInvalid(t *testing.T) {
+	if _, err := ReadXML(strings.NewReader(`<collection xmlns="` + Namespace + `"><record><leader>`)); err == nil {
+		t.Error("ReadXML of truncated document returned no error")
+	}
+}
@@ -0,0 +1,169 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Namespace is the MARCXML slim schema namespace
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlCollection struct {
+	XMLName xml.Name    `xml:"collection"`
+	Xmlns   string      `xml:"xmlns,attr,omitempty"`
+	Records []xmlRecord `xml:"record"`
+}
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Xmlns         string            `xml:"xmlns,attr,omitempty"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+func toXMLRecord(r *Record) xmlRecord {
+	xr := xmlRecord{Leader: r.Leader}
+	for _, f := range r.Fields {
+		if f.IsControl() {
+			xr.ControlFields = append(xr.ControlFields, xmlControlField{Tag: f.Tag, Value: f.Value})
+			continue
+		}
+		df := xmlDataField{
+			Tag:  f.Tag,
+			Ind1: string(indicator(f.Ind1)),
+			Ind2: string(indicator(f.Ind2)),
+		}
+		for _, sf := range f.Subfields {
+			df.Subfields = append(df.Subfields, xmlSubfield{Code: string(sf.Code), Value: sf.Value})
+		}
+		xr.DataFields = append(xr.DataFields, df)
+	}
+	return xr
+}
+
+func fromXMLRecord(xr xmlRecord) *Record {
+	r := &Record{Leader: xr.Leader}
+	for _, cf := range xr.ControlFields {
+		r.Fields = append(r.Fields, Field{Tag: cf.Tag, Value: cf.Value})
+	}
+	for _, df := range xr.DataFields {
+		f := Field{Tag: df.Tag, Ind1: firstByte(df.Ind1), Ind2: firstByte(df.Ind2)}
+		for _, sf := range df.Subfields {
+			f.Subfields = append(f.Subfields, Subfield{Code: firstByte(sf.Code), Value: sf.Value})
+		}
+		r.Fields = append(r.Fields, f)
+	}
+	return r
+}
+
+func firstByte(s string) byte {
+	if s == "" {
+		return ' '
+	}
+	return s[0]
+}
+
+// XMLWriter streams records as a MARCXML collection
+type XMLWriter struct {
+	enc     *xml.Encoder
+	w       io.Writer
+	started bool
+}
+
+// NewXMLWriter creates a new MARCXML writer. Close must be called to end the collection.
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return &XMLWriter{enc: enc, w: w}
+}
+
+func (w *XMLWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	if _, err := io.WriteString(w.w, xml.Header); err != nil {
+		return err
+	}
+	return w.enc.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "collection"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: Namespace}},
+	})
+}
+
+// Write encodes a single record into the collection
+func (w *XMLWriter) Write(r *Record) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	return w.enc.Encode(toXMLRecord(r))
+}
+
+// Close ends the collection element and flushes the encoder
+func (w *XMLWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	if err := w.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "collection"}}); err != nil {
+		return err
+	}
+	return w.enc.Flush()
+}
+
+// MarshalXML encodes a single record as a standalone MARCXML document
+func MarshalXML(r *Record) ([]byte, error) {
+	xr := toXMLRecord(r)
+	xr.Xmlns = Namespace
+	out, err := xml.MarshalIndent(xr, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode MARCXML: %w", err)
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// ReadXML decodes every record in a MARCXML document, which may be a
+// <collection> or a single <record> root element
+func ReadXML(r io.Reader) ([]*Record, error) {
+	dec := xml.NewDecoder(r)
+	var records []*Record
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode MARCXML: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var xr xmlRecord
+		if err := dec.DecodeElement(&xr, &start); err != nil {
+			return nil, fmt.Errorf("failed to decode MARCXML record: %w", err)
+		}
+		records = append(records, fromXMLRecord(xr))
+	}
+	return records, nil
+}
@@ -0,0 +1,110 @@
+package marc
+
+import "strings"
+
+// Subfield is a single coded value inside a data field (e.g. $a Title)
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// Field is either a control field (tags 001-009) or a data field with indicators and subfields
+type Field struct {
+	Tag       string
+	Value     string // only set for control fields
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+// IsControl reports whether the field is a control field (tags 001-009)
+func (f Field) IsControl() bool {
+	return isControlTag(f.Tag)
+}
+
+// Subfield returns the first value for the given subfield code
+func (f Field) Subfield(code byte) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
+
+// Record is a single bibliographic MARC record
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// NewRecord creates an empty record with a default leader for a monograph
+func NewRecord() *Record {
+	return &Record{Leader: defaultLeader}
+}
+
+// AddControlField appends a control field to the record
+func (r *Record) AddControlField(tag, value string) {
+	r.Fields = append(r.Fields, Field{Tag: tag, Value: value})
+}
+
+// AddDataField appends a data field to the record, skipping subfields with empty values
+func (r *Record) AddDataField(tag string, ind1, ind2 byte, subfields ...Subfield) {
+	var nonEmpty []Subfield
+	for _, sf := range subfields {
+		if sf.Value != "" {
+			nonEmpty = append(nonEmpty, sf)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return
+	}
+	r.Fields = append(r.Fields, Field{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: nonEmpty})
+}
+
+// FieldsByTag returns all fields with the given tag
+func (r *Record) FieldsByTag(tag string) []Field {
+	var fields []Field
+	for _, f := range r.Fields {
+		if f.Tag == tag {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// ControlField returns the value of the first control field with the given tag
+func (r *Record) ControlField(tag string) string {
+	for _, f := range r.Fields {
+		if f.Tag == tag {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// SubfieldValue returns the first value of a subfield across all fields with the given tag
+func (r *Record) SubfieldValue(tag string, code byte) string {
+	for _, f := range r.FieldsByTag(tag) {
+		if v := f.Subfield(code); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// TrimPunctuation strips the trailing ISBD punctuation catalogers leave on subfield values
+func TrimPunctuation(s string) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), " /:;,=."))
+}
+
+func isControlTag(tag string) bool {
+	return len(tag) == 3 && strings.HasPrefix(tag, "00")
+}
+
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+	return b
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+)
+
+// circulationTimeout bounds the query counting loans and holds on each scrape
+const circulationTimeout = 5 * time.Second
+
+// circulationCollector reports open loans and holds by status, counted when scraped
+type circulationCollector struct {
+	repo *repository.Queries
+
+	loans *prometheus.Desc
+	holds *prometheus.Desc
+}
+
+func newCirculationCollector(repo *repository.Queries) *circulationCollector {
+	return &circulationCollector{
+		repo:  repo,
+		loans: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "loans"), "Open loans, by status.", []string{"status"}, nil),
+		holds: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "holds"), "Open holds, by status.", []string{"status"}, nil),
+	}
+}
+
+func (c *circulationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.loans
+	ch <- c.holds
+}
+
+func (c *circulationCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), circulationTimeout)
+	defer cancel()
+
+	counts, err := c.repo.CountCirculation(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.loans, err)
+		ch <- prometheus.NewInvalidMetric(c.holds, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.loans, prometheus.GaugeValue, float64(counts.ActiveLoans), "active")
+	ch <- prometheus.MustNewConstMetric(c.loans, prometheus.GaugeValue, float64(counts.OverdueLoans), "overdue")
+	ch <- prometheus.MustNewConstMetric(c.holds, prometheus.GaugeValue, float64(counts.WaitingHolds), "waiting")
+	ch <- prometheus.MustNewConstMetric(c.holds, prometheus.GaugeValue, float64(counts.ReadyHolds), "ready")
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+)
+
+// namespace prefixes every metric name
+const namespace = "bookbridge"
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests handled, by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to handle HTTP requests, by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	openLibraryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "openlibrary_request_duration_seconds",
+		Help:      "Time taken by Open Library API calls, by operation.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"operation"})
+
+	openLibraryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "openlibrary_errors_total",
+		Help:      "Open Library API calls that failed or returned an error status, by operation.",
+	}, []string{"operation"})
+)
+
+// ObserveHTTPRequest records a handled HTTP request. Requests matching no route have an empty route.
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObserveOpenLibraryCall records an Open Library API call, counting it as an error when err is set
+func ObserveOpenLibraryCall(operation string, duration time.Duration, err error) {
+	openLibraryDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	if err != nil {
+		openLibraryErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format: HTTP and Open Library metrics, the
+// database pool's statistics and circulation gauges counted at scrape time, and Go runtime and
+// process metrics. Metrics that cannot be collected are left out rather than failing the scrape.
+func Handler(db *database.DB, repo *repository.Queries) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		openLibraryDuration,
+		openLibraryErrors,
+		newPoolCollector(db.Pool),
+		newCirculationCollector(repo),
+	)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vasujain275/bookbridge-api/internal/logger"
+	"github.com/vasujain275/bookbridge-api/internal/service"
+)
+
+// Audit attributes the changes a request makes to its caller, request ID and IP. Services record
+// their own changes in detail; any other successful POST, PUT, PATCH or DELETE is recorded here
+// with the route as the action, its first path segment as the entity type and its :id parameter,
+// if any, as the entity ID. Audit must come after RequestID and Authenticate.
+func Audit(audit service.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		src := &service.AuditSource{
+			RequestID: logger.RequestID(c.Request.Context()),
+			IP:        c.ClientIP(),
+		}
+		if actor, ok := Actor(c); ok {
+			src.ActorID = actor.ID
+			src.ActorUsername = actor.Username
+		}
+		c.Request = c.Request.WithContext(service.WithAuditSource(c.Request.Context(), src))
+
+		c.Next()
+
+		if !isMutation(c.Request.Method) || c.FullPath() == "" || c.Writer.Status() >= http.StatusBadRequest || src.Recorded() {
+			return
+		}
+		entityType, _, _ := strings.Cut(strings.TrimPrefix(c.FullPath(), "/"), "/")
+		err := audit.Record(context.WithoutCancel(c.Request.Context()), service.AuditRecord{
+			Action:     c.Request.Method + " " + c.FullPath(),
+			EntityType: entityType,
+			EntityID:   c.Param("id"),
+		})
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to record audit entry",
+				"method", c.Request.Method,
+				"route", c.FullPath(),
+				"error", err,
+			)
+		}
+	}
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -1,27 +1,98 @@
 package middleware
 
 import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/vasujain275/bookbridge-api/internal/metrics"
+	"github.com/vasujain275/bookbridge-api/internal/tracing"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // SetupGlobalMiddleware adds the middleware to the router
-func SetupGlobalMiddleware(r *gin.Engine) {
-	// Recovery middleware recovers from any panics
-	r.Use(gin.Recovery())
+func SetupGlobalMiddleware(r *gin.Engine, log *slog.Logger) {
+	// Tracing middleware starts a span for each request, continuing the caller's trace
+	r.Use(otelgin.Middleware(tracing.ServiceName))
+
+	// Request ID middleware identifies each request in logs and responses
+	r.Use(RequestID())
 
 	// Logger middleware logs the incoming requests
-	r.Use(gin.Logger())
+	r.Use(Logger(log))
+
+	// Metrics middleware counts and times the requests by route
+	r.Use(Metrics())
+
+	// Recovery middleware recovers from any panics
+	r.Use(Recovery(log))
 
 	// CORS middleware
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 }
+
+// Logger logs each request once it has been handled
+func Logger(log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		log.LogAttrs(c.Request.Context(), level, "Request handled",
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+		)
+	}
+}
+
+// Metrics records each request's status and duration under its route template
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		metrics.ObserveHTTPRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+	}
+}
+
+// Recovery recovers from panics in handlers, logging them with their stack trace
+func Recovery(log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				log.ErrorContext(c.Request.Context(), "Recovered from panic",
+					"error", err,
+					"stack", string(debug.Stack()),
+				)
+				util.SendInternalServerError(c, nil)
+				c.Abort()
+			}
+		}()
+		c.Next()
+	}
+}
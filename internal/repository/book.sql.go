@@ -16,11 +16,11 @@ const createBook = `-- name: CreateBook :one
 INSERT INTO books (
   isbn_10, isbn_13, title, publisher,
   published_date, description, page_count, language,
-  thumbnail_url, total_copies, available_copies
+  thumbnail_url, total_copies, available_copies, work_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
-RETURNING id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at
+RETURNING id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count
 `
 
 type CreateBookParams struct {
@@ -35,6 +35,7 @@ type CreateBookParams struct {
 	ThumbnailUrl    pgtype.Text `json:"thumbnail_url"`
 	TotalCopies     int32       `json:"total_copies"`
 	AvailableCopies int32       `json:"available_copies"`
+	WorkID          uuid.UUID   `json:"work_id"`
 }
 
 func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (Book, error) {
@@ -50,6 +51,7 @@ func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (Book, e
 		arg.ThumbnailUrl,
 		arg.TotalCopies,
 		arg.AvailableCopies,
+		arg.WorkID,
 	)
 	var i Book
 	err := row.Scan(
@@ -67,6 +69,13 @@ func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) (Book, e
 		&i.AvailableCopies,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorkID,
+		&i.DeweyDecimalClass,
+		&i.LcClassification,
+		&i.CallNumber,
+		&i.ShelfKey,
+		&i.RatingAvg,
+		&i.RatingCount,
 	)
 	return i, err
 }
@@ -81,8 +90,45 @@ func (q *Queries) DeleteBook(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const getAvailableBookForWork = `-- name: GetAvailableBookForWork :one
+SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count FROM books
+WHERE work_id = $1 AND available_copies > 0
+ORDER BY available_copies DESC
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetAvailableBookForWork(ctx context.Context, workID uuid.UUID) (Book, error) {
+	row := q.db.QueryRow(ctx, getAvailableBookForWork, workID)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn10,
+		&i.Isbn13,
+		&i.Title,
+		&i.Publisher,
+		&i.PublishedDate,
+		&i.Description,
+		&i.PageCount,
+		&i.Language,
+		&i.ThumbnailUrl,
+		&i.TotalCopies,
+		&i.AvailableCopies,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkID,
+		&i.DeweyDecimalClass,
+		&i.LcClassification,
+		&i.CallNumber,
+		&i.ShelfKey,
+		&i.RatingAvg,
+		&i.RatingCount,
+	)
+	return i, err
+}
+
 const getBook = `-- name: GetBook :one
-SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at FROM books
+SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count FROM books
 WHERE id = $1
 `
 
@@ -104,12 +150,19 @@ func (q *Queries) GetBook(ctx context.Context, id uuid.UUID) (Book, error) {
 		&i.AvailableCopies,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorkID,
+		&i.DeweyDecimalClass,
+		&i.LcClassification,
+		&i.CallNumber,
+		&i.ShelfKey,
+		&i.RatingAvg,
+		&i.RatingCount,
 	)
 	return i, err
 }
 
 const getBookByISBN = `-- name: GetBookByISBN :one
-SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at FROM books
+SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count FROM books
 WHERE isbn_13 = $1
 `
 
@@ -131,12 +184,53 @@ func (q *Queries) GetBookByISBN(ctx context.Context, isbn13 string) (Book, error
 		&i.AvailableCopies,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorkID,
+		&i.DeweyDecimalClass,
+		&i.LcClassification,
+		&i.CallNumber,
+		&i.ShelfKey,
+		&i.RatingAvg,
+		&i.RatingCount,
+	)
+	return i, err
+}
+
+const getBookByISBN10 = `-- name: GetBookByISBN10 :one
+SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count FROM books
+WHERE isbn_10 = $1
+`
+
+func (q *Queries) GetBookByISBN10(ctx context.Context, isbn10 pgtype.Text) (Book, error) {
+	row := q.db.QueryRow(ctx, getBookByISBN10, isbn10)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn10,
+		&i.Isbn13,
+		&i.Title,
+		&i.Publisher,
+		&i.PublishedDate,
+		&i.Description,
+		&i.PageCount,
+		&i.Language,
+		&i.ThumbnailUrl,
+		&i.TotalCopies,
+		&i.AvailableCopies,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkID,
+		&i.DeweyDecimalClass,
+		&i.LcClassification,
+		&i.CallNumber,
+		&i.ShelfKey,
+		&i.RatingAvg,
+		&i.RatingCount,
 	)
 	return i, err
 }
 
 const listBooks = `-- name: ListBooks :many
-SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at FROM books
+SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count FROM books
 ORDER BY title
 LIMIT $1 OFFSET $2
 `
@@ -170,6 +264,229 @@ func (q *Queries) ListBooks(ctx context.Context, arg ListBooksParams) ([]Book, e
 			&i.AvailableCopies,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WorkID,
+			&i.DeweyDecimalClass,
+			&i.LcClassification,
+			&i.CallNumber,
+			&i.ShelfKey,
+			&i.RatingAvg,
+			&i.RatingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksByAuthorID = `-- name: ListBooksByAuthorID :many
+SELECT b.id, b.isbn_10, b.isbn_13, b.title, b.publisher, b.published_date, b.description, b.page_count, b.language, b.thumbnail_url, b.total_copies, b.available_copies, b.created_at, b.updated_at, b.work_id, b.dewey_decimal_class, b.lc_classification, b.call_number, b.shelf_key, b.rating_avg, b.rating_count FROM books b
+JOIN book_authors ba ON b.id = ba.book_id
+WHERE ba.author_id = $1
+ORDER BY b.title
+`
+
+func (q *Queries) ListBooksByAuthorID(ctx context.Context, authorID uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByAuthorID, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Title,
+			&i.Publisher,
+			&i.PublishedDate,
+			&i.Description,
+			&i.PageCount,
+			&i.Language,
+			&i.ThumbnailUrl,
+			&i.TotalCopies,
+			&i.AvailableCopies,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkID,
+			&i.DeweyDecimalClass,
+			&i.LcClassification,
+			&i.CallNumber,
+			&i.ShelfKey,
+			&i.RatingAvg,
+			&i.RatingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksByCategoryTree = `-- name: ListBooksByCategoryTree :many
+WITH RECURSIVE subtree AS (
+  SELECT id FROM categories
+  WHERE id = $1
+  UNION ALL
+  SELECT c.id FROM categories c
+  JOIN subtree s ON c.parent_id = s.id
+)
+SELECT b.id, b.isbn_10, b.isbn_13, b.title, b.publisher, b.published_date, b.description, b.page_count, b.language, b.thumbnail_url, b.total_copies, b.available_copies, b.created_at, b.updated_at, b.work_id, b.dewey_decimal_class, b.lc_classification, b.call_number, b.shelf_key, b.rating_avg, b.rating_count FROM books b
+WHERE EXISTS (
+  SELECT 1 FROM book_categories bc
+  JOIN subtree s ON bc.category_id = s.id
+  WHERE bc.book_id = b.id
+)
+ORDER BY b.title
+LIMIT $2 OFFSET $3
+`
+
+type ListBooksByCategoryTreeParams struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	Limit      int32     `json:"limit"`
+	Offset     int32     `json:"offset"`
+}
+
+func (q *Queries) ListBooksByCategoryTree(ctx context.Context, arg ListBooksByCategoryTreeParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByCategoryTree, arg.CategoryID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Title,
+			&i.Publisher,
+			&i.PublishedDate,
+			&i.Description,
+			&i.PageCount,
+			&i.Language,
+			&i.ThumbnailUrl,
+			&i.TotalCopies,
+			&i.AvailableCopies,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkID,
+			&i.DeweyDecimalClass,
+			&i.LcClassification,
+			&i.CallNumber,
+			&i.ShelfKey,
+			&i.RatingAvg,
+			&i.RatingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksByRating = `-- name: ListBooksByRating :many
+SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count FROM books
+ORDER BY rating_avg DESC, rating_count DESC, title
+LIMIT $1 OFFSET $2
+`
+
+type ListBooksByRatingParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListBooksByRating(ctx context.Context, arg ListBooksByRatingParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByRating, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Title,
+			&i.Publisher,
+			&i.PublishedDate,
+			&i.Description,
+			&i.PageCount,
+			&i.Language,
+			&i.ThumbnailUrl,
+			&i.TotalCopies,
+			&i.AvailableCopies,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkID,
+			&i.DeweyDecimalClass,
+			&i.LcClassification,
+			&i.CallNumber,
+			&i.ShelfKey,
+			&i.RatingAvg,
+			&i.RatingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksByWorkID = `-- name: ListBooksByWorkID :many
+SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count FROM books
+WHERE work_id = $1
+ORDER BY published_date, title
+`
+
+func (q *Queries) ListBooksByWorkID(ctx context.Context, workID uuid.UUID) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listBooksByWorkID, workID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Title,
+			&i.Publisher,
+			&i.PublishedDate,
+			&i.Description,
+			&i.PageCount,
+			&i.Language,
+			&i.ThumbnailUrl,
+			&i.TotalCopies,
+			&i.AvailableCopies,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkID,
+			&i.DeweyDecimalClass,
+			&i.LcClassification,
+			&i.CallNumber,
+			&i.ShelfKey,
+			&i.RatingAvg,
+			&i.RatingCount,
 		); err != nil {
 			return nil, err
 		}
@@ -181,8 +498,170 @@ func (q *Queries) ListBooks(ctx context.Context, arg ListBooksParams) ([]Book, e
 	return items, nil
 }
 
+const listShelf = `-- name: ListShelf :many
+SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count FROM books
+WHERE shelf_key >= $1
+  AND ($2::text = '' OR shelf_key <= $2)
+ORDER BY shelf_key, title
+LIMIT $3 OFFSET $4
+`
+
+type ListShelfParams struct {
+	FromKey string `json:"from_key"`
+	ToKey   string `json:"to_key"`
+	Limit   int32  `json:"limit"`
+	Offset  int32  `json:"offset"`
+}
+
+func (q *Queries) ListShelf(ctx context.Context, arg ListShelfParams) ([]Book, error) {
+	rows, err := q.db.Query(ctx, listShelf,
+		arg.FromKey,
+		arg.ToKey,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.ID,
+			&i.Isbn10,
+			&i.Isbn13,
+			&i.Title,
+			&i.Publisher,
+			&i.PublishedDate,
+			&i.Description,
+			&i.PageCount,
+			&i.Language,
+			&i.ThumbnailUrl,
+			&i.TotalCopies,
+			&i.AvailableCopies,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkID,
+			&i.DeweyDecimalClass,
+			&i.LcClassification,
+			&i.CallNumber,
+			&i.ShelfKey,
+			&i.RatingAvg,
+			&i.RatingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockBook = `-- name: LockBook :exec
+SELECT id FROM books
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockBook(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, lockBook, id)
+	return err
+}
+
+const refreshBookRating = `-- name: RefreshBookRating :exec
+UPDATE books
+SET
+  rating_avg = COALESCE((SELECT AVG(rating) FROM book_reviews WHERE book_id = $1 AND status = 'published'), 0),
+  rating_count = (SELECT COUNT(*) FROM book_reviews WHERE book_id = $1 AND status = 'published')
+WHERE id = $1
+`
+
+func (q *Queries) RefreshBookRating(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, refreshBookRating, id)
+	return err
+}
+
+const releaseBookCopy = `-- name: ReleaseBookCopy :one
+UPDATE books
+SET
+  available_copies = available_copies + 1,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND available_copies < total_copies
+RETURNING id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count
+`
+
+func (q *Queries) ReleaseBookCopy(ctx context.Context, id uuid.UUID) (Book, error) {
+	row := q.db.QueryRow(ctx, releaseBookCopy, id)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn10,
+		&i.Isbn13,
+		&i.Title,
+		&i.Publisher,
+		&i.PublishedDate,
+		&i.Description,
+		&i.PageCount,
+		&i.Language,
+		&i.ThumbnailUrl,
+		&i.TotalCopies,
+		&i.AvailableCopies,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkID,
+		&i.DeweyDecimalClass,
+		&i.LcClassification,
+		&i.CallNumber,
+		&i.ShelfKey,
+		&i.RatingAvg,
+		&i.RatingCount,
+	)
+	return i, err
+}
+
+const reserveBookCopy = `-- name: ReserveBookCopy :one
+UPDATE books
+SET
+  available_copies = available_copies - 1,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND available_copies > 0
+RETURNING id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count
+`
+
+func (q *Queries) ReserveBookCopy(ctx context.Context, id uuid.UUID) (Book, error) {
+	row := q.db.QueryRow(ctx, reserveBookCopy, id)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn10,
+		&i.Isbn13,
+		&i.Title,
+		&i.Publisher,
+		&i.PublishedDate,
+		&i.Description,
+		&i.PageCount,
+		&i.Language,
+		&i.ThumbnailUrl,
+		&i.TotalCopies,
+		&i.AvailableCopies,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkID,
+		&i.DeweyDecimalClass,
+		&i.LcClassification,
+		&i.CallNumber,
+		&i.ShelfKey,
+		&i.RatingAvg,
+		&i.RatingCount,
+	)
+	return i, err
+}
+
 const searchBooks = `-- name: SearchBooks :many
-SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at FROM books
+SELECT id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count FROM books
 WHERE 
   title ILIKE '%' || $1 || '%'
   OR publisher ILIKE '%' || $1 || '%'
@@ -221,6 +700,13 @@ func (q *Queries) SearchBooks(ctx context.Context, arg SearchBooksParams) ([]Boo
 			&i.AvailableCopies,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WorkID,
+			&i.DeweyDecimalClass,
+			&i.LcClassification,
+			&i.CallNumber,
+			&i.ShelfKey,
+			&i.RatingAvg,
+			&i.RatingCount,
 		); err != nil {
 			return nil, err
 		}
@@ -248,7 +734,7 @@ SET
   available_copies = $12,
   updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at
+RETURNING id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count
 `
 
 type UpdateBookParams struct {
@@ -297,6 +783,68 @@ func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) (Book, e
 		&i.AvailableCopies,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorkID,
+		&i.DeweyDecimalClass,
+		&i.LcClassification,
+		&i.CallNumber,
+		&i.ShelfKey,
+		&i.RatingAvg,
+		&i.RatingCount,
+	)
+	return i, err
+}
+
+const updateBookClassification = `-- name: UpdateBookClassification :one
+UPDATE books
+SET
+  dewey_decimal_class = $2,
+  lc_classification = $3,
+  call_number = $4,
+  shelf_key = $5,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count
+`
+
+type UpdateBookClassificationParams struct {
+	ID                uuid.UUID   `json:"id"`
+	DeweyDecimalClass pgtype.Text `json:"dewey_decimal_class"`
+	LcClassification  pgtype.Text `json:"lc_classification"`
+	CallNumber        pgtype.Text `json:"call_number"`
+	ShelfKey          pgtype.Text `json:"shelf_key"`
+}
+
+func (q *Queries) UpdateBookClassification(ctx context.Context, arg UpdateBookClassificationParams) (Book, error) {
+	row := q.db.QueryRow(ctx, updateBookClassification,
+		arg.ID,
+		arg.DeweyDecimalClass,
+		arg.LcClassification,
+		arg.CallNumber,
+		arg.ShelfKey,
+	)
+	var i Book
+	err := row.Scan(
+		&i.ID,
+		&i.Isbn10,
+		&i.Isbn13,
+		&i.Title,
+		&i.Publisher,
+		&i.PublishedDate,
+		&i.Description,
+		&i.PageCount,
+		&i.Language,
+		&i.ThumbnailUrl,
+		&i.TotalCopies,
+		&i.AvailableCopies,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkID,
+		&i.DeweyDecimalClass,
+		&i.LcClassification,
+		&i.CallNumber,
+		&i.ShelfKey,
+		&i.RatingAvg,
+		&i.RatingCount,
 	)
 	return i, err
 }
@@ -308,7 +856,7 @@ SET
   available_copies = $3,
   updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at
+RETURNING id, isbn_10, isbn_13, title, publisher, published_date, description, page_count, language, thumbnail_url, total_copies, available_copies, created_at, updated_at, work_id, dewey_decimal_class, lc_classification, call_number, shelf_key, rating_avg, rating_count
 `
 
 type UpdateBookCopiesParams struct {
@@ -335,6 +883,31 @@ func (q *Queries) UpdateBookCopies(ctx context.Context, arg UpdateBookCopiesPara
 		&i.AvailableCopies,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WorkID,
+		&i.DeweyDecimalClass,
+		&i.LcClassification,
+		&i.CallNumber,
+		&i.ShelfKey,
+		&i.RatingAvg,
+		&i.RatingCount,
 	)
 	return i, err
 }
+
+const updateBookThumbnail = `-- name: UpdateBookThumbnail :exec
+UPDATE books
+SET
+  thumbnail_url = $2,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+type UpdateBookThumbnailParams struct {
+	ID           uuid.UUID   `json:"id"`
+	ThumbnailUrl pgtype.Text `json:"thumbnail_url"`
+}
+
+func (q *Queries) UpdateBookThumbnail(ctx context.Context, arg UpdateBookThumbnailParams) error {
+	_, err := q.db.Exec(ctx, updateBookThumbnail, arg.ID, arg.ThumbnailUrl)
+	return err
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addBookCategory = `-- name: AddBookCategory :exec
@@ -30,19 +31,28 @@ func (q *Queries) AddBookCategory(ctx context.Context, arg AddBookCategoryParams
 }
 
 const createCategory = `-- name: CreateCategory :one
-INSERT INTO categories (name)
-VALUES ($1)
-RETURNING id, name, created_at, updated_at
+INSERT INTO categories (
+  name, parent_id
+) VALUES (
+  $1, $2
+)
+RETURNING id, name, created_at, updated_at, parent_id
 `
 
-func (q *Queries) CreateCategory(ctx context.Context, name string) (Category, error) {
-	row := q.db.QueryRow(ctx, createCategory, name)
+type CreateCategoryParams struct {
+	Name     string      `json:"name"`
+	ParentID pgtype.UUID `json:"parent_id"`
+}
+
+func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error) {
+	row := q.db.QueryRow(ctx, createCategory, arg.Name, arg.ParentID)
 	var i Category
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentID,
 	)
 	return i, err
 }
@@ -58,7 +68,7 @@ func (q *Queries) DeleteCategory(ctx context.Context, id uuid.UUID) error {
 }
 
 const getCategory = `-- name: GetCategory :one
-SELECT id, name, created_at, updated_at FROM categories
+SELECT id, name, created_at, updated_at, parent_id FROM categories
 WHERE id = $1
 `
 
@@ -70,12 +80,13 @@ func (q *Queries) GetCategory(ctx context.Context, id uuid.UUID) (Category, erro
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentID,
 	)
 	return i, err
 }
 
 const getCategoryByName = `-- name: GetCategoryByName :one
-SELECT id, name, created_at, updated_at FROM categories
+SELECT id, name, created_at, updated_at, parent_id FROM categories
 WHERE name = $1
 `
 
@@ -87,12 +98,44 @@ func (q *Queries) GetCategoryByName(ctx context.Context, name string) (Category,
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentID,
 	)
 	return i, err
 }
 
+const listAllCategories = `-- name: ListAllCategories :many
+SELECT id, name, created_at, updated_at, parent_id FROM categories
+ORDER BY name
+`
+
+func (q *Queries) ListAllCategories(ctx context.Context) ([]Category, error) {
+	rows, err := q.db.Query(ctx, listAllCategories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Category
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ParentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listCategories = `-- name: ListCategories :many
-SELECT id, name, created_at, updated_at FROM categories
+SELECT id, name, created_at, updated_at, parent_id FROM categories
 ORDER BY name
 LIMIT $1 OFFSET $2
 `
@@ -116,6 +159,7 @@ func (q *Queries) ListCategories(ctx context.Context, arg ListCategoriesParams)
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ParentID,
 		); err != nil {
 			return nil, err
 		}
@@ -128,7 +172,7 @@ func (q *Queries) ListCategories(ctx context.Context, arg ListCategoriesParams)
 }
 
 const listCategoriesByBookID = `-- name: ListCategoriesByBookID :many
-SELECT c.id, c.name, c.created_at, c.updated_at FROM categories c
+SELECT c.id, c.name, c.created_at, c.updated_at, c.parent_id FROM categories c
 JOIN book_categories bc ON c.id = bc.category_id
 WHERE bc.book_id = $1
 ORDER BY c.name
@@ -148,6 +192,7 @@ func (q *Queries) ListCategoriesByBookID(ctx context.Context, bookID uuid.UUID)
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ParentID,
 		); err != nil {
 			return nil, err
 		}
@@ -159,6 +204,190 @@ func (q *Queries) ListCategoriesByBookID(ctx context.Context, bookID uuid.UUID)
 	return items, nil
 }
 
+const listCategoryAncestors = `-- name: ListCategoryAncestors :many
+WITH RECURSIVE ancestors AS (
+  SELECT id, parent_id, 0 AS depth, ARRAY[id] AS path FROM categories
+  WHERE id = $1
+  UNION ALL
+  SELECT p.id, p.parent_id, a.depth + 1, a.path || p.id FROM categories p
+  JOIN ancestors a ON p.id = a.parent_id
+  WHERE p.id <> ALL(a.path)
+)
+SELECT c.id, c.name, c.created_at, c.updated_at, c.parent_id FROM categories c
+JOIN ancestors a ON c.id = a.id
+ORDER BY a.depth DESC
+`
+
+func (q *Queries) ListCategoryAncestors(ctx context.Context, categoryID uuid.UUID) ([]Category, error) {
+	rows, err := q.db.Query(ctx, listCategoryAncestors, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Category
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ParentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCategoryBookCounts = `-- name: ListCategoryBookCounts :many
+SELECT category_id, COUNT(*)::int AS book_count FROM book_categories
+GROUP BY category_id
+`
+
+type ListCategoryBookCountsRow struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	BookCount  int32     `json:"book_count"`
+}
+
+func (q *Queries) ListCategoryBookCounts(ctx context.Context) ([]ListCategoryBookCountsRow, error) {
+	rows, err := q.db.Query(ctx, listCategoryBookCounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCategoryBookCountsRow
+	for rows.Next() {
+		var i ListCategoryBookCountsRow
+		if err := rows.Scan(
+			&i.CategoryID,
+			&i.BookCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCategoryChildren = `-- name: ListCategoryChildren :many
+SELECT id, name, created_at, updated_at, parent_id FROM categories
+WHERE parent_id = $1
+ORDER BY name
+`
+
+func (q *Queries) ListCategoryChildren(ctx context.Context, parentID pgtype.UUID) ([]Category, error) {
+	rows, err := q.db.Query(ctx, listCategoryChildren, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Category
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ParentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCategoryTreeBookCounts = `-- name: ListCategoryTreeBookCounts :many
+WITH RECURSIVE closure AS (
+  SELECT id AS ancestor_id, id AS descendant_id, ARRAY[id] AS path FROM categories
+  UNION ALL
+  SELECT cl.ancestor_id, c.id, cl.path || c.id FROM closure cl
+  JOIN categories c ON c.parent_id = cl.descendant_id
+  WHERE c.id <> ALL(cl.path)
+)
+SELECT cl.ancestor_id AS category_id, COUNT(DISTINCT bc.book_id)::int AS book_count
+FROM closure cl
+JOIN book_categories bc ON bc.category_id = cl.descendant_id
+GROUP BY cl.ancestor_id
+`
+
+type ListCategoryTreeBookCountsRow struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	BookCount  int32     `json:"book_count"`
+}
+
+func (q *Queries) ListCategoryTreeBookCounts(ctx context.Context) ([]ListCategoryTreeBookCountsRow, error) {
+	rows, err := q.db.Query(ctx, listCategoryTreeBookCounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCategoryTreeBookCountsRow
+	for rows.Next() {
+		var i ListCategoryTreeBookCountsRow
+		if err := rows.Scan(
+			&i.CategoryID,
+			&i.BookCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockCategoryTree = `-- name: LockCategoryTree :exec
+SELECT pg_advisory_xact_lock(hashtext('categories'))
+`
+
+// Serializes moves within the transaction, so two concurrent moves cannot both pass the cycle check
+func (q *Queries) LockCategoryTree(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockCategoryTree)
+	return err
+}
+
+const moveCategory = `-- name: MoveCategory :one
+UPDATE categories
+SET 
+  parent_id = $2,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, name, created_at, updated_at, parent_id
+`
+
+type MoveCategoryParams struct {
+	ID       uuid.UUID   `json:"id"`
+	ParentID pgtype.UUID `json:"parent_id"`
+}
+
+func (q *Queries) MoveCategory(ctx context.Context, arg MoveCategoryParams) (Category, error) {
+	row := q.db.QueryRow(ctx, moveCategory, arg.ID, arg.ParentID)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentID,
+	)
+	return i, err
+}
+
 const removeAllBookCategories = `-- name: RemoveAllBookCategories :exec
 DELETE FROM book_categories
 WHERE book_id = $1
@@ -184,13 +413,31 @@ func (q *Queries) RemoveBookCategory(ctx context.Context, arg RemoveBookCategory
 	return err
 }
 
+const reparentCategoryChildren = `-- name: ReparentCategoryChildren :exec
+UPDATE categories
+SET 
+  parent_id = $1,
+  updated_at = CURRENT_TIMESTAMP
+WHERE parent_id = $2
+`
+
+type ReparentCategoryChildrenParams struct {
+	NewParentID pgtype.UUID `json:"new_parent_id"`
+	CategoryID  pgtype.UUID `json:"category_id"`
+}
+
+func (q *Queries) ReparentCategoryChildren(ctx context.Context, arg ReparentCategoryChildrenParams) error {
+	_, err := q.db.Exec(ctx, reparentCategoryChildren, arg.NewParentID, arg.CategoryID)
+	return err
+}
+
 const updateCategory = `-- name: UpdateCategory :one
 UPDATE categories
 SET 
   name = $2,
   updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, name, created_at, updated_at
+RETURNING id, name, created_at, updated_at, parent_id
 `
 
 type UpdateCategoryParams struct {
@@ -206,6 +453,7 @@ func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams)
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentID,
 	)
 	return i, err
 }
@@ -12,6 +12,23 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const anonymizeReturnedLoansByUserID = `-- name: AnonymizeReturnedLoansByUserID :exec
+UPDATE loans
+SET
+  borrowed_date = NULL,
+  due_date = NULL,
+  returned_date = NULL,
+  created_at = NULL,
+  updated_at = NULL
+WHERE user_id = $1 AND status = 'returned'
+`
+
+// Keeps only who borrowed which book of each returned loan
+func (q *Queries) AnonymizeReturnedLoansByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, anonymizeReturnedLoansByUserID, userID)
+	return err
+}
+
 const createLoan = `-- name: CreateLoan :one
 INSERT INTO loans (
   user_id, book_id, borrowed_date, due_date, status
@@ -84,6 +101,32 @@ func (q *Queries) GetLoan(ctx context.Context, id uuid.UUID) (Loan, error) {
 	return i, err
 }
 
+const getUserLoanSummary = `-- name: GetUserLoanSummary :one
+SELECT
+  COUNT(*)::int AS total_loans,
+  COUNT(*) FILTER (WHERE status <> 'returned')::int AS open_loans,
+  COALESCE(AVG(returned_date - borrowed_date) FILTER (WHERE returned_date IS NOT NULL), 0)::float8 AS average_loan_days
+FROM loans
+WHERE user_id = $1
+`
+
+type GetUserLoanSummaryRow struct {
+	TotalLoans      int32   `json:"total_loans"`
+	OpenLoans       int32   `json:"open_loans"`
+	AverageLoanDays float64 `json:"average_loan_days"`
+}
+
+func (q *Queries) GetUserLoanSummary(ctx context.Context, userID uuid.UUID) (GetUserLoanSummaryRow, error) {
+	row := q.db.QueryRow(ctx, getUserLoanSummary, userID)
+	var i GetUserLoanSummaryRow
+	err := row.Scan(
+		&i.TotalLoans,
+		&i.OpenLoans,
+		&i.AverageLoanDays,
+	)
+	return i, err
+}
+
 const listActiveLoans = `-- name: ListActiveLoans :many
 SELECT id, user_id, book_id, borrowed_date, due_date, returned_date, status, created_at, updated_at FROM loans
 WHERE status = 'active'
@@ -126,6 +169,96 @@ func (q *Queries) ListActiveLoans(ctx context.Context, arg ListActiveLoansParams
 	return items, nil
 }
 
+const listFavoriteAuthors = `-- name: ListFavoriteAuthors :many
+SELECT a.id, a.name, COUNT(*)::int AS books
+FROM unnest($1::uuid[]) AS r(book_id)
+JOIN book_authors ba ON ba.book_id = r.book_id
+JOIN authors a ON a.id = ba.author_id
+GROUP BY a.id, a.name
+ORDER BY books DESC, a.name
+LIMIT $2
+`
+
+type ListFavoriteAuthorsParams struct {
+	BookIds []uuid.UUID `json:"book_ids"`
+	Limit   int32       `json:"limit"`
+}
+
+type ListFavoriteAuthorsRow struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Books int32     `json:"books"`
+}
+
+func (q *Queries) ListFavoriteAuthors(ctx context.Context, arg ListFavoriteAuthorsParams) ([]ListFavoriteAuthorsRow, error) {
+	rows, err := q.db.Query(ctx, listFavoriteAuthors, arg.BookIds, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFavoriteAuthorsRow
+	for rows.Next() {
+		var i ListFavoriteAuthorsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Books,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFavoriteCategories = `-- name: ListFavoriteCategories :many
+SELECT c.id, c.name, COUNT(*)::int AS books
+FROM unnest($1::uuid[]) AS r(book_id)
+JOIN book_categories bc ON bc.book_id = r.book_id
+JOIN categories c ON c.id = bc.category_id
+GROUP BY c.id, c.name
+ORDER BY books DESC, c.name
+LIMIT $2
+`
+
+type ListFavoriteCategoriesParams struct {
+	BookIds []uuid.UUID `json:"book_ids"`
+	Limit   int32       `json:"limit"`
+}
+
+type ListFavoriteCategoriesRow struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Books int32     `json:"books"`
+}
+
+func (q *Queries) ListFavoriteCategories(ctx context.Context, arg ListFavoriteCategoriesParams) ([]ListFavoriteCategoriesRow, error) {
+	rows, err := q.db.Query(ctx, listFavoriteCategories, arg.BookIds, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFavoriteCategoriesRow
+	for rows.Next() {
+		var i ListFavoriteCategoriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Books,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listLoans = `-- name: ListLoans :many
 SELECT id, user_id, book_id, borrowed_date, due_date, returned_date, status, created_at, updated_at FROM loans
 ORDER BY borrowed_date DESC
@@ -295,6 +428,94 @@ func (q *Queries) ListOverdueLoans(ctx context.Context, arg ListOverdueLoansPara
 	return items, nil
 }
 
+const listUserReads = `-- name: ListUserReads :many
+SELECT r.book_id, r.finished_on, b.page_count
+FROM (
+  SELECT l.book_id, l.returned_date AS finished_on
+  FROM loans l
+  WHERE l.user_id = $1 AND l.returned_date IS NOT NULL
+  UNION ALL
+  SELECT br.book_id, br.created_at::date AS finished_on
+  FROM book_reviews br
+  WHERE br.user_id = $1
+    AND NOT EXISTS (
+      SELECT 1 FROM loans l
+      WHERE l.user_id = br.user_id AND l.book_id = br.book_id AND l.returned_date IS NOT NULL
+    )
+) r
+JOIN books b ON b.id = r.book_id
+ORDER BY r.finished_on
+`
+
+type ListUserReadsRow struct {
+	BookID     uuid.UUID   `json:"book_id"`
+	FinishedOn pgtype.Date `json:"finished_on"`
+	PageCount  pgtype.Int4 `json:"page_count"`
+}
+
+func (q *Queries) ListUserReads(ctx context.Context, userID uuid.UUID) ([]ListUserReadsRow, error) {
+	rows, err := q.db.Query(ctx, listUserReads, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserReadsRow
+	for rows.Next() {
+		var i ListUserReadsRow
+		if err := rows.Scan(
+			&i.BookID,
+			&i.FinishedOn,
+			&i.PageCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOverdueLoans = `-- name: MarkOverdueLoans :many
+UPDATE loans
+SET
+  status = 'overdue',
+  updated_at = CURRENT_TIMESTAMP
+WHERE status = 'active' AND due_date < CURRENT_DATE
+RETURNING id, user_id, book_id, borrowed_date, due_date, returned_date, status, created_at, updated_at
+`
+
+func (q *Queries) MarkOverdueLoans(ctx context.Context) ([]Loan, error) {
+	rows, err := q.db.Query(ctx, markOverdueLoans)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Loan
+	for rows.Next() {
+		var i Loan
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.BookID,
+			&i.BorrowedDate,
+			&i.DueDate,
+			&i.ReturnedDate,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateLoan = `-- name: UpdateLoan :one
 UPDATE loans
 SET 
@@ -9,6 +9,20 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AuditLog struct {
+	ID            uuid.UUID        `json:"id"`
+	ActorID       pgtype.UUID      `json:"actor_id"`
+	ActorUsername pgtype.Text      `json:"actor_username"`
+	Action        string           `json:"action"`
+	EntityType    string           `json:"entity_type"`
+	EntityID      pgtype.Text      `json:"entity_id"`
+	Before        []byte           `json:"before"`
+	After         []byte           `json:"after"`
+	RequestID     pgtype.Text      `json:"request_id"`
+	Ip            pgtype.Text      `json:"ip"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
 type Author struct {
 	ID             uuid.UUID        `json:"id"`
 	Name           string           `json:"name"`
@@ -25,20 +39,27 @@ type Author struct {
 }
 
 type Book struct {
-	ID              uuid.UUID        `json:"id"`
-	Isbn10          pgtype.Text      `json:"isbn_10"`
-	Isbn13          string           `json:"isbn_13"`
-	Title           string           `json:"title"`
-	Publisher       pgtype.Text      `json:"publisher"`
-	PublishedDate   pgtype.Text      `json:"published_date"`
-	Description     pgtype.Text      `json:"description"`
-	PageCount       pgtype.Int4      `json:"page_count"`
-	Language        pgtype.Text      `json:"language"`
-	ThumbnailUrl    pgtype.Text      `json:"thumbnail_url"`
-	TotalCopies     int32            `json:"total_copies"`
-	AvailableCopies int32            `json:"available_copies"`
-	CreatedAt       pgtype.Timestamp `json:"created_at"`
-	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+	ID                uuid.UUID        `json:"id"`
+	Isbn10            pgtype.Text      `json:"isbn_10"`
+	Isbn13            string           `json:"isbn_13"`
+	Title             string           `json:"title"`
+	Publisher         pgtype.Text      `json:"publisher"`
+	PublishedDate     pgtype.Text      `json:"published_date"`
+	Description       pgtype.Text      `json:"description"`
+	PageCount         pgtype.Int4      `json:"page_count"`
+	Language          pgtype.Text      `json:"language"`
+	ThumbnailUrl      pgtype.Text      `json:"thumbnail_url"`
+	TotalCopies       int32            `json:"total_copies"`
+	AvailableCopies   int32            `json:"available_copies"`
+	CreatedAt         pgtype.Timestamp `json:"created_at"`
+	UpdatedAt         pgtype.Timestamp `json:"updated_at"`
+	WorkID            uuid.UUID        `json:"work_id"`
+	DeweyDecimalClass pgtype.Text      `json:"dewey_decimal_class"`
+	LcClassification  pgtype.Text      `json:"lc_classification"`
+	CallNumber        pgtype.Text      `json:"call_number"`
+	ShelfKey          pgtype.Text      `json:"shelf_key"`
+	RatingAvg         float64          `json:"rating_avg"`
+	RatingCount       int32            `json:"rating_count"`
 }
 
 type BookAuthor struct {
@@ -51,13 +72,31 @@ type BookCategory struct {
 	CategoryID uuid.UUID `json:"category_id"`
 }
 
+type BookCover struct {
+	BookID    uuid.UUID        `json:"book_id"`
+	Etag      string           `json:"etag"`
+	SourceUrl pgtype.Text      `json:"source_url"`
+	Width     int32            `json:"width"`
+	Height    int32            `json:"height"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
 type BookReview struct {
-	ID         uuid.UUID        `json:"id"`
-	BookID     uuid.UUID        `json:"book_id"`
-	UserID     uuid.UUID        `json:"user_id"`
-	Rating     int32            `json:"rating"`
-	ReviewText pgtype.Text      `json:"review_text"`
-	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	ID             uuid.UUID        `json:"id"`
+	BookID         uuid.UUID        `json:"book_id"`
+	UserID         uuid.UUID        `json:"user_id"`
+	Rating         int32            `json:"rating"`
+	ReviewText     pgtype.Text      `json:"review_text"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+	Status         string           `json:"status"`
+	FlagReason     pgtype.Text      `json:"flag_reason"`
+	ModerationNote pgtype.Text      `json:"moderation_note"`
+	ModeratedBy    pgtype.UUID      `json:"moderated_by"`
+	ModeratedAt    pgtype.Timestamp `json:"moderated_at"`
+	HelpfulCount   int32            `json:"helpful_count"`
+	UnhelpfulCount int32            `json:"unhelpful_count"`
 }
 
 type Category struct {
@@ -65,6 +104,19 @@ type Category struct {
 	Name      string           `json:"name"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	ParentID  pgtype.UUID      `json:"parent_id"`
+}
+
+type Hold struct {
+	ID             uuid.UUID        `json:"id"`
+	UserID         uuid.UUID        `json:"user_id"`
+	WorkID         uuid.UUID        `json:"work_id"`
+	BookID         pgtype.UUID      `json:"book_id"`
+	AssignedBookID pgtype.UUID      `json:"assigned_book_id"`
+	Status         string           `json:"status"`
+	ReadyAt        pgtype.Timestamp `json:"ready_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
 }
 
 type Loan struct {
@@ -79,14 +131,160 @@ type Loan struct {
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 }
 
+type MergeRedirect struct {
+	EntityType string           `json:"entity_type"`
+	OldID      uuid.UUID        `json:"old_id"`
+	NewID      uuid.UUID        `json:"new_id"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type Notification struct {
+	ID            uuid.UUID        `json:"id"`
+	UserID        uuid.UUID        `json:"user_id"`
+	Kind          string           `json:"kind"`
+	DedupeKey     string           `json:"dedupe_key"`
+	Recipient     string           `json:"recipient"`
+	Subject       string           `json:"subject"`
+	BodyText      string           `json:"body_text"`
+	BodyHtml      string           `json:"body_html"`
+	Status        string           `json:"status"`
+	Attempts      int32            `json:"attempts"`
+	LastError     pgtype.Text      `json:"last_error"`
+	NextAttemptAt pgtype.Timestamp `json:"next_attempt_at"`
+	SentAt        pgtype.Timestamp `json:"sent_at"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
+}
+
+type NotificationPreference struct {
+	UserID          uuid.UUID        `json:"user_id"`
+	DueReminders    bool             `json:"due_reminders"`
+	DueReminderDays pgtype.Int4      `json:"due_reminder_days"`
+	OverdueNotices  bool             `json:"overdue_notices"`
+	HoldReady       bool             `json:"hold_ready"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+}
+
+type Outbox struct {
+	ID            uuid.UUID        `json:"id"`
+	EventType     string           `json:"event_type"`
+	DedupeKey     string           `json:"dedupe_key"`
+	Payload       []byte           `json:"payload"`
+	Attempts      int32            `json:"attempts"`
+	LastError     pgtype.Text      `json:"last_error"`
+	NextAttemptAt pgtype.Timestamp `json:"next_attempt_at"`
+	DispatchedAt  pgtype.Timestamp `json:"dispatched_at"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+type ReadingList struct {
+	ID          uuid.UUID        `json:"id"`
+	UserID      uuid.UUID        `json:"user_id"`
+	Name        string           `json:"name"`
+	Description pgtype.Text      `json:"description"`
+	Kind        string           `json:"kind"`
+	IsPublic    bool             `json:"is_public"`
+	ShareToken  pgtype.Text      `json:"share_token"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+type ReadingListItem struct {
+	ID        uuid.UUID        `json:"id"`
+	ListID    uuid.UUID        `json:"list_id"`
+	BookID    uuid.UUID        `json:"book_id"`
+	Position  int32            `json:"position"`
+	Note      pgtype.Text      `json:"note"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type ReviewRejection struct {
+	ID          uuid.UUID        `json:"id"`
+	UserID      uuid.UUID        `json:"user_id"`
+	ReviewID    uuid.UUID        `json:"review_id"`
+	ModeratedBy pgtype.UUID      `json:"moderated_by"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+type ReviewReport struct {
+	ID         uuid.UUID        `json:"id"`
+	ReviewID   uuid.UUID        `json:"review_id"`
+	UserID     uuid.UUID        `json:"user_id"`
+	Reason     string           `json:"reason"`
+	Details    pgtype.Text      `json:"details"`
+	Status     string           `json:"status"`
+	ResolvedBy pgtype.UUID      `json:"resolved_by"`
+	ResolvedAt pgtype.Timestamp `json:"resolved_at"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type ReviewVote struct {
+	ReviewID  uuid.UUID        `json:"review_id"`
+	UserID    uuid.UUID        `json:"user_id"`
+	Helpful   bool             `json:"helpful"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type Series struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Description pgtype.Text      `json:"description"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+type SeriesWork struct {
+	SeriesID uuid.UUID `json:"series_id"`
+	WorkID   uuid.UUID `json:"work_id"`
+	Volume   int32     `json:"volume"`
+}
+
 type User struct {
-	ID           uuid.UUID        `json:"id"`
-	Username     string           `json:"username"`
-	Email        string           `json:"email"`
-	PasswordHash string           `json:"password_hash"`
-	Role         string           `json:"role"`
-	FirstName    string           `json:"first_name"`
-	LastName     string           `json:"last_name"`
-	CreatedAt    pgtype.Timestamp `json:"created_at"`
-	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	ID            uuid.UUID        `json:"id"`
+	Username      string           `json:"username"`
+	Email         string           `json:"email"`
+	PasswordHash  string           `json:"-"`
+	Role          string           `json:"role"`
+	FirstName     string           `json:"first_name"`
+	LastName      string           `json:"last_name"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
+	RetainHistory bool             `json:"retain_history"`
+}
+
+type Webhook struct {
+	ID          uuid.UUID        `json:"id"`
+	Url         string           `json:"url"`
+	Secret      string           `json:"-"`
+	EventTypes  []string         `json:"event_types"`
+	Description pgtype.Text      `json:"description"`
+	IsActive    bool             `json:"is_active"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID        `json:"id"`
+	WebhookID      uuid.UUID        `json:"webhook_id"`
+	EventID        uuid.UUID        `json:"event_id"`
+	EventType      string           `json:"event_type"`
+	Payload        []byte           `json:"payload"`
+	Status         string           `json:"status"`
+	Attempts       int32            `json:"attempts"`
+	ResponseStatus pgtype.Int4      `json:"response_status"`
+	LastError      pgtype.Text      `json:"last_error"`
+	NextAttemptAt  pgtype.Timestamp `json:"next_attempt_at"`
+	DeliveredAt    pgtype.Timestamp `json:"delivered_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+}
+
+type Work struct {
+	ID             uuid.UUID        `json:"id"`
+	Title          string           `json:"title"`
+	OpenlibraryKey pgtype.Text      `json:"openlibrary_key"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
 }
@@ -12,13 +12,31 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countReviewRejectionsSince = `-- name: CountReviewRejectionsSince :one
+SELECT COUNT(*) FROM review_rejections
+WHERE user_id = $1
+  AND created_at >= $2
+`
+
+type CountReviewRejectionsSinceParams struct {
+	UserID    uuid.UUID        `json:"user_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+func (q *Queries) CountReviewRejectionsSince(ctx context.Context, arg CountReviewRejectionsSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countReviewRejectionsSince, arg.UserID, arg.CreatedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createReview = `-- name: CreateReview :one
 INSERT INTO book_reviews (
-  book_id, user_id, rating, review_text
+  book_id, user_id, rating, review_text, status, flag_reason
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6
 )
-RETURNING id, book_id, user_id, rating, review_text, created_at
+RETURNING id, book_id, user_id, rating, review_text, created_at, updated_at, status, flag_reason, moderation_note, moderated_by, moderated_at, helpful_count, unhelpful_count
 `
 
 type CreateReviewParams struct {
@@ -26,6 +44,8 @@ type CreateReviewParams struct {
 	UserID     uuid.UUID   `json:"user_id"`
 	Rating     int32       `json:"rating"`
 	ReviewText pgtype.Text `json:"review_text"`
+	Status     string      `json:"status"`
+	FlagReason pgtype.Text `json:"flag_reason"`
 }
 
 func (q *Queries) CreateReview(ctx context.Context, arg CreateReviewParams) (BookReview, error) {
@@ -34,6 +54,8 @@ func (q *Queries) CreateReview(ctx context.Context, arg CreateReviewParams) (Boo
 		arg.UserID,
 		arg.Rating,
 		arg.ReviewText,
+		arg.Status,
+		arg.FlagReason,
 	)
 	var i BookReview
 	err := row.Scan(
@@ -43,6 +65,71 @@ func (q *Queries) CreateReview(ctx context.Context, arg CreateReviewParams) (Boo
 		&i.Rating,
 		&i.ReviewText,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.FlagReason,
+		&i.ModerationNote,
+		&i.ModeratedBy,
+		&i.ModeratedAt,
+		&i.HelpfulCount,
+		&i.UnhelpfulCount,
+	)
+	return i, err
+}
+
+const createReviewRejection = `-- name: CreateReviewRejection :exec
+INSERT INTO review_rejections (
+  user_id, review_id, moderated_by
+) VALUES (
+  $1, $2, $3
+)
+`
+
+type CreateReviewRejectionParams struct {
+	UserID      uuid.UUID   `json:"user_id"`
+	ReviewID    uuid.UUID   `json:"review_id"`
+	ModeratedBy pgtype.UUID `json:"moderated_by"`
+}
+
+func (q *Queries) CreateReviewRejection(ctx context.Context, arg CreateReviewRejectionParams) error {
+	_, err := q.db.Exec(ctx, createReviewRejection, arg.UserID, arg.ReviewID, arg.ModeratedBy)
+	return err
+}
+
+const createReviewReport = `-- name: CreateReviewReport :one
+INSERT INTO review_reports (
+  review_id, user_id, reason, details
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, review_id, user_id, reason, details, status, resolved_by, resolved_at, created_at
+`
+
+type CreateReviewReportParams struct {
+	ReviewID uuid.UUID   `json:"review_id"`
+	UserID   uuid.UUID   `json:"user_id"`
+	Reason   string      `json:"reason"`
+	Details  pgtype.Text `json:"details"`
+}
+
+func (q *Queries) CreateReviewReport(ctx context.Context, arg CreateReviewReportParams) (ReviewReport, error) {
+	row := q.db.QueryRow(ctx, createReviewReport,
+		arg.ReviewID,
+		arg.UserID,
+		arg.Reason,
+		arg.Details,
+	)
+	var i ReviewReport
+	err := row.Scan(
+		&i.ID,
+		&i.ReviewID,
+		&i.UserID,
+		&i.Reason,
+		&i.Details,
+		&i.Status,
+		&i.ResolvedBy,
+		&i.ResolvedAt,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -57,8 +144,49 @@ func (q *Queries) DeleteReview(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const deleteReviewVote = `-- name: DeleteReviewVote :exec
+DELETE FROM review_votes
+WHERE review_id = $1 AND user_id = $2
+`
+
+type DeleteReviewVoteParams struct {
+	ReviewID uuid.UUID `json:"review_id"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteReviewVote(ctx context.Context, arg DeleteReviewVoteParams) error {
+	_, err := q.db.Exec(ctx, deleteReviewVote, arg.ReviewID, arg.UserID)
+	return err
+}
+
+const deleteReviewsByUserID = `-- name: DeleteReviewsByUserID :many
+DELETE FROM book_reviews
+WHERE user_id = $1
+RETURNING book_id
+`
+
+func (q *Queries) DeleteReviewsByUserID(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, deleteReviewsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var book_id uuid.UUID
+		if err := rows.Scan(&book_id); err != nil {
+			return nil, err
+		}
+		items = append(items, book_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getReview = `-- name: GetReview :one
-SELECT id, book_id, user_id, rating, review_text, created_at FROM book_reviews
+SELECT id, book_id, user_id, rating, review_text, created_at, updated_at, status, flag_reason, moderation_note, moderated_by, moderated_at, helpful_count, unhelpful_count FROM book_reviews
 WHERE id = $1
 `
 
@@ -72,12 +200,20 @@ func (q *Queries) GetReview(ctx context.Context, id uuid.UUID) (BookReview, erro
 		&i.Rating,
 		&i.ReviewText,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.FlagReason,
+		&i.ModerationNote,
+		&i.ModeratedBy,
+		&i.ModeratedAt,
+		&i.HelpfulCount,
+		&i.UnhelpfulCount,
 	)
 	return i, err
 }
 
 const getReviewByUserAndBook = `-- name: GetReviewByUserAndBook :one
-SELECT id, book_id, user_id, rating, review_text, created_at FROM book_reviews
+SELECT id, book_id, user_id, rating, review_text, created_at, updated_at, status, flag_reason, moderation_note, moderated_by, moderated_at, helpful_count, unhelpful_count FROM book_reviews
 WHERE user_id = $1 AND book_id = $2
 `
 
@@ -96,12 +232,108 @@ func (q *Queries) GetReviewByUserAndBook(ctx context.Context, arg GetReviewByUse
 		&i.Rating,
 		&i.ReviewText,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.FlagReason,
+		&i.ModerationNote,
+		&i.ModeratedBy,
+		&i.ModeratedAt,
+		&i.HelpfulCount,
+		&i.UnhelpfulCount,
 	)
 	return i, err
 }
 
+const listModerationQueue = `-- name: ListModerationQueue :many
+SELECT r.id, r.book_id, r.user_id, r.rating, r.review_text, r.created_at, r.updated_at, r.status, r.flag_reason, r.moderation_note, r.moderated_by, r.moderated_at, r.helpful_count, r.unhelpful_count FROM book_reviews r
+WHERE r.status = 'pending'
+  OR (r.status = 'published' AND EXISTS (
+    SELECT 1 FROM review_reports rr
+    WHERE rr.review_id = r.id AND rr.status = 'open'
+  ))
+ORDER BY r.created_at
+LIMIT $1 OFFSET $2
+`
+
+type ListModerationQueueParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListModerationQueue(ctx context.Context, arg ListModerationQueueParams) ([]BookReview, error) {
+	rows, err := q.db.Query(ctx, listModerationQueue, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BookReview
+	for rows.Next() {
+		var i BookReview
+		if err := rows.Scan(
+			&i.ID,
+			&i.BookID,
+			&i.UserID,
+			&i.Rating,
+			&i.ReviewText,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.FlagReason,
+			&i.ModerationNote,
+			&i.ModeratedBy,
+			&i.ModeratedAt,
+			&i.HelpfulCount,
+			&i.UnhelpfulCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReviewReports = `-- name: ListReviewReports :many
+SELECT id, review_id, user_id, reason, details, status, resolved_by, resolved_at, created_at FROM review_reports
+WHERE review_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListReviewReports(ctx context.Context, reviewID uuid.UUID) ([]ReviewReport, error) {
+	rows, err := q.db.Query(ctx, listReviewReports, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReviewReport
+	for rows.Next() {
+		var i ReviewReport
+		if err := rows.Scan(
+			&i.ID,
+			&i.ReviewID,
+			&i.UserID,
+			&i.Reason,
+			&i.Details,
+			&i.Status,
+			&i.ResolvedBy,
+			&i.ResolvedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listReviews = `-- name: ListReviews :many
-SELECT id, book_id, user_id, rating, review_text, created_at FROM book_reviews
+SELECT id, book_id, user_id, rating, review_text, created_at, updated_at, status, flag_reason, moderation_note, moderated_by, moderated_at, helpful_count, unhelpful_count FROM book_reviews
+WHERE status = 'published'
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -127,6 +359,14 @@ func (q *Queries) ListReviews(ctx context.Context, arg ListReviewsParams) ([]Boo
 			&i.Rating,
 			&i.ReviewText,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.FlagReason,
+			&i.ModerationNote,
+			&i.ModeratedBy,
+			&i.ModeratedAt,
+			&i.HelpfulCount,
+			&i.UnhelpfulCount,
 		); err != nil {
 			return nil, err
 		}
@@ -139,20 +379,30 @@ func (q *Queries) ListReviews(ctx context.Context, arg ListReviewsParams) ([]Boo
 }
 
 const listReviewsByBookID = `-- name: ListReviewsByBookID :many
-SELECT id, book_id, user_id, rating, review_text, created_at FROM book_reviews
-WHERE book_id = $1
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3
+SELECT id, book_id, user_id, rating, review_text, created_at, updated_at, status, flag_reason, moderation_note, moderated_by, moderated_at, helpful_count, unhelpful_count FROM book_reviews
+WHERE book_id = $1 AND status = 'published'
+ORDER BY
+  CASE WHEN $2::text = 'helpful' THEN helpful_count - unhelpful_count END DESC,
+  CASE WHEN $2::text = 'helpful' THEN helpful_count END DESC,
+  CASE WHEN $2::text = 'rating' THEN rating END DESC,
+  created_at DESC
+LIMIT $3 OFFSET $4
 `
 
 type ListReviewsByBookIDParams struct {
 	BookID uuid.UUID `json:"book_id"`
+	Sort   string    `json:"sort"`
 	Limit  int32     `json:"limit"`
 	Offset int32     `json:"offset"`
 }
 
 func (q *Queries) ListReviewsByBookID(ctx context.Context, arg ListReviewsByBookIDParams) ([]BookReview, error) {
-	rows, err := q.db.Query(ctx, listReviewsByBookID, arg.BookID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listReviewsByBookID,
+		arg.BookID,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +417,14 @@ func (q *Queries) ListReviewsByBookID(ctx context.Context, arg ListReviewsByBook
 			&i.Rating,
 			&i.ReviewText,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.FlagReason,
+			&i.ModerationNote,
+			&i.ModeratedBy,
+			&i.ModeratedAt,
+			&i.HelpfulCount,
+			&i.UnhelpfulCount,
 		); err != nil {
 			return nil, err
 		}
@@ -179,20 +437,27 @@ func (q *Queries) ListReviewsByBookID(ctx context.Context, arg ListReviewsByBook
 }
 
 const listReviewsByUserID = `-- name: ListReviewsByUserID :many
-SELECT id, book_id, user_id, rating, review_text, created_at FROM book_reviews
+SELECT id, book_id, user_id, rating, review_text, created_at, updated_at, status, flag_reason, moderation_note, moderated_by, moderated_at, helpful_count, unhelpful_count FROM book_reviews
 WHERE user_id = $1
+  AND ($2::bool OR status = 'published')
 ORDER BY created_at DESC
-LIMIT $2 OFFSET $3
+LIMIT $3 OFFSET $4
 `
 
 type ListReviewsByUserIDParams struct {
-	UserID uuid.UUID `json:"user_id"`
-	Limit  int32     `json:"limit"`
-	Offset int32     `json:"offset"`
+	UserID             uuid.UUID `json:"user_id"`
+	IncludeUnpublished bool      `json:"include_unpublished"`
+	Limit              int32     `json:"limit"`
+	Offset             int32     `json:"offset"`
 }
 
 func (q *Queries) ListReviewsByUserID(ctx context.Context, arg ListReviewsByUserIDParams) ([]BookReview, error) {
-	rows, err := q.db.Query(ctx, listReviewsByUserID, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listReviewsByUserID,
+		arg.UserID,
+		arg.IncludeUnpublished,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +472,14 @@ func (q *Queries) ListReviewsByUserID(ctx context.Context, arg ListReviewsByUser
 			&i.Rating,
 			&i.ReviewText,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.FlagReason,
+			&i.ModerationNote,
+			&i.ModeratedBy,
+			&i.ModeratedAt,
+			&i.HelpfulCount,
+			&i.UnhelpfulCount,
 		); err != nil {
 			return nil, err
 		}
@@ -218,23 +491,172 @@ func (q *Queries) ListReviewsByUserID(ctx context.Context, arg ListReviewsByUser
 	return items, nil
 }
 
+const listVerifiedReviewIDs = `-- name: ListVerifiedReviewIDs :many
+SELECT r.id FROM book_reviews r
+WHERE r.id = ANY($1::uuid[])
+  AND EXISTS (
+    SELECT 1 FROM loans l
+    WHERE l.user_id = r.user_id
+      AND l.book_id = r.book_id
+      AND l.status = 'returned'
+  )
+`
+
+func (q *Queries) ListVerifiedReviewIDs(ctx context.Context, reviewIds []uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listVerifiedReviewIDs, reviewIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockReview = `-- name: LockReview :exec
+SELECT id FROM book_reviews
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockReview(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, lockReview, id)
+	return err
+}
+
+const moderateReview = `-- name: ModerateReview :one
+UPDATE book_reviews
+SET
+  status = $2,
+  moderation_note = $3,
+  moderated_by = $4,
+  moderated_at = CURRENT_TIMESTAMP,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, book_id, user_id, rating, review_text, created_at, updated_at, status, flag_reason, moderation_note, moderated_by, moderated_at, helpful_count, unhelpful_count
+`
+
+type ModerateReviewParams struct {
+	ID             uuid.UUID   `json:"id"`
+	Status         string      `json:"status"`
+	ModerationNote pgtype.Text `json:"moderation_note"`
+	ModeratedBy    pgtype.UUID `json:"moderated_by"`
+}
+
+func (q *Queries) ModerateReview(ctx context.Context, arg ModerateReviewParams) (BookReview, error) {
+	row := q.db.QueryRow(ctx, moderateReview,
+		arg.ID,
+		arg.Status,
+		arg.ModerationNote,
+		arg.ModeratedBy,
+	)
+	var i BookReview
+	err := row.Scan(
+		&i.ID,
+		&i.BookID,
+		&i.UserID,
+		&i.Rating,
+		&i.ReviewText,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.FlagReason,
+		&i.ModerationNote,
+		&i.ModeratedBy,
+		&i.ModeratedAt,
+		&i.HelpfulCount,
+		&i.UnhelpfulCount,
+	)
+	return i, err
+}
+
+const refreshReviewVotes = `-- name: RefreshReviewVotes :one
+UPDATE book_reviews
+SET
+  helpful_count = (SELECT COUNT(*) FROM review_votes WHERE review_id = $1 AND helpful),
+  unhelpful_count = (SELECT COUNT(*) FROM review_votes WHERE review_id = $1 AND NOT helpful)
+WHERE id = $1
+RETURNING id, book_id, user_id, rating, review_text, created_at, updated_at, status, flag_reason, moderation_note, moderated_by, moderated_at, helpful_count, unhelpful_count
+`
+
+func (q *Queries) RefreshReviewVotes(ctx context.Context, id uuid.UUID) (BookReview, error) {
+	row := q.db.QueryRow(ctx, refreshReviewVotes, id)
+	var i BookReview
+	err := row.Scan(
+		&i.ID,
+		&i.BookID,
+		&i.UserID,
+		&i.Rating,
+		&i.ReviewText,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.FlagReason,
+		&i.ModerationNote,
+		&i.ModeratedBy,
+		&i.ModeratedAt,
+		&i.HelpfulCount,
+		&i.UnhelpfulCount,
+	)
+	return i, err
+}
+
+const resolveReviewReports = `-- name: ResolveReviewReports :exec
+UPDATE review_reports
+SET
+  status = 'resolved',
+  resolved_by = $2,
+  resolved_at = CURRENT_TIMESTAMP
+WHERE review_id = $1 AND status = 'open'
+`
+
+type ResolveReviewReportsParams struct {
+	ReviewID   uuid.UUID   `json:"review_id"`
+	ResolvedBy pgtype.UUID `json:"resolved_by"`
+}
+
+func (q *Queries) ResolveReviewReports(ctx context.Context, arg ResolveReviewReportsParams) error {
+	_, err := q.db.Exec(ctx, resolveReviewReports, arg.ReviewID, arg.ResolvedBy)
+	return err
+}
+
 const updateReview = `-- name: UpdateReview :one
 UPDATE book_reviews
 SET 
   rating = $2,
-  review_text = $3
+  review_text = $3,
+  status = $4,
+  flag_reason = $5,
+  updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, book_id, user_id, rating, review_text, created_at
+RETURNING id, book_id, user_id, rating, review_text, created_at, updated_at, status, flag_reason, moderation_note, moderated_by, moderated_at, helpful_count, unhelpful_count
 `
 
 type UpdateReviewParams struct {
 	ID         uuid.UUID   `json:"id"`
 	Rating     int32       `json:"rating"`
 	ReviewText pgtype.Text `json:"review_text"`
+	Status     string      `json:"status"`
+	FlagReason pgtype.Text `json:"flag_reason"`
 }
 
 func (q *Queries) UpdateReview(ctx context.Context, arg UpdateReviewParams) (BookReview, error) {
-	row := q.db.QueryRow(ctx, updateReview, arg.ID, arg.Rating, arg.ReviewText)
+	row := q.db.QueryRow(ctx, updateReview,
+		arg.ID,
+		arg.Rating,
+		arg.ReviewText,
+		arg.Status,
+		arg.FlagReason,
+	)
 	var i BookReview
 	err := row.Scan(
 		&i.ID,
@@ -243,6 +665,35 @@ func (q *Queries) UpdateReview(ctx context.Context, arg UpdateReviewParams) (Boo
 		&i.Rating,
 		&i.ReviewText,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.FlagReason,
+		&i.ModerationNote,
+		&i.ModeratedBy,
+		&i.ModeratedAt,
+		&i.HelpfulCount,
+		&i.UnhelpfulCount,
 	)
 	return i, err
 }
+
+const upsertReviewVote = `-- name: UpsertReviewVote :exec
+INSERT INTO review_votes (
+  review_id, user_id, helpful
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (review_id, user_id) DO UPDATE
+SET helpful = EXCLUDED.helpful, updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertReviewVoteParams struct {
+	ReviewID uuid.UUID `json:"review_id"`
+	UserID   uuid.UUID `json:"user_id"`
+	Helpful  bool      `json:"helpful"`
+}
+
+func (q *Queries) UpsertReviewVote(ctx context.Context, arg UpsertReviewVoteParams) error {
+	_, err := q.db.Exec(ctx, upsertReviewVote, arg.ReviewID, arg.UserID, arg.Helpful)
+	return err
+}
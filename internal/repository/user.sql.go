@@ -17,7 +17,7 @@ INSERT INTO users (
 ) VALUES (
   $1, $2, $3, $4, $5, $6
 )
-RETURNING id, username, email, password_hash, role, first_name, last_name, created_at, updated_at
+RETURNING id, username, email, password_hash, role, first_name, last_name, created_at, updated_at, retain_history
 `
 
 type CreateUserParams struct {
@@ -49,6 +49,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.LastName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RetainHistory,
 	)
 	return i, err
 }
@@ -64,7 +65,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, username, email, password_hash, role, first_name, last_name, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, role, first_name, last_name, created_at, updated_at, retain_history FROM users
 WHERE id = $1
 `
 
@@ -81,12 +82,13 @@ func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.LastName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RetainHistory,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password_hash, role, first_name, last_name, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, role, first_name, last_name, created_at, updated_at, retain_history FROM users
 WHERE email = $1
 `
 
@@ -103,12 +105,13 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.LastName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RetainHistory,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, email, password_hash, role, first_name, last_name, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, role, first_name, last_name, created_at, updated_at, retain_history FROM users
 WHERE username = $1
 `
 
@@ -125,12 +128,13 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.LastName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RetainHistory,
 	)
 	return i, err
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, username, email, password_hash, role, first_name, last_name, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, role, first_name, last_name, created_at, updated_at, retain_history FROM users
 ORDER BY username
 LIMIT $1 OFFSET $2
 `
@@ -159,6 +163,7 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.LastName,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RetainHistory,
 		); err != nil {
 			return nil, err
 		}
@@ -170,6 +175,38 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const setUserHistoryRetention = `-- name: SetUserHistoryRetention :one
+UPDATE users
+SET
+  retain_history = $2,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, username, email, password_hash, role, first_name, last_name, created_at, updated_at, retain_history
+`
+
+type SetUserHistoryRetentionParams struct {
+	ID            uuid.UUID `json:"id"`
+	RetainHistory bool      `json:"retain_history"`
+}
+
+func (q *Queries) SetUserHistoryRetention(ctx context.Context, arg SetUserHistoryRetentionParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserHistoryRetention, arg.ID, arg.RetainHistory)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Role,
+		&i.FirstName,
+		&i.LastName,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RetainHistory,
+	)
+	return i, err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET 
@@ -181,7 +218,7 @@ SET
   last_name = $7,
   updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, username, email, password_hash, role, first_name, last_name, created_at, updated_at
+RETURNING id, username, email, password_hash, role, first_name, last_name, created_at, updated_at, retain_history
 `
 
 type UpdateUserParams struct {
@@ -215,6 +252,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.LastName,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RetainHistory,
 	)
 	return i, err
 }
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// Audited entity types
+const (
+	AuditEntityUser = "users"
+	AuditEntityBook = "books"
+)
+
+// Audited actions
+const (
+	AuditUserCreate           = "users.create"
+	AuditUserUpdate           = "users.update"
+	AuditUserDelete           = "users.delete"
+	AuditUserHistoryRetention = "users.history_retention"
+	AuditBookCreate           = "books.create"
+)
+
+// auditRedacted lists fields whose values are never written to the audit log; a change to one is
+// recorded with this placeholder in place of the values
+var auditRedacted = map[string]bool{"password_hash": true}
+
+const auditRedactedValue = "[redacted]"
+
+// auditIgnored lists fields left out of diffs because they change on every update
+var auditIgnored = map[string]bool{"updated_at": true}
+
+// auditedUser is a user as written to the audit log. Users are never serialized with their password
+// hash, so it is added back here for a password change to show up, redacted, in the diff.
+type auditedUser struct {
+	*repository.User
+	PasswordHash string `json:"password_hash"`
+}
+
+func auditUser(user *repository.User) auditedUser {
+	return auditedUser{User: user, PasswordHash: user.PasswordHash}
+}
+
+// AuditSource describes who made the changes during a request, carried in its context
+type AuditSource struct {
+	ActorID       uuid.UUID
+	ActorUsername string
+	RequestID     string
+	IP            string
+
+	recorded atomic.Bool
+}
+
+// Recorded reports whether a service has written an audit entry for the request
+func (src *AuditSource) Recorded() bool {
+	return src.recorded.Load()
+}
+
+type auditSourceKey struct{}
+
+// WithAuditSource returns a copy of ctx whose audit entries are attributed to src
+func WithAuditSource(ctx context.Context, src *AuditSource) context.Context {
+	return context.WithValue(ctx, auditSourceKey{}, src)
+}
+
+func auditSourceFrom(ctx context.Context) *AuditSource {
+	src, _ := ctx.Value(auditSourceKey{}).(*AuditSource)
+	return src
+}
+
+// AuditRecord is an audit entry to write. Before and After are the entity before and after the
+// change, nil when it is created or deleted; only the fields that differ are kept.
+type AuditRecord struct {
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     any
+	After      any
+}
+
+// AuditFilter narrows the audit log. Empty fields match everything; Actor matches the actor's ID or
+// username, and entries are from From up to but not including To.
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	From       *time.Time
+	To         *time.Time
+}
+
+// AuditServiceImpl implements the AuditService interface
+type AuditServiceImpl struct {
+	repo *repository.Queries
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(repo *repository.Queries) AuditService {
+	return &AuditServiceImpl{
+		repo: repo,
+	}
+}
+
+// Record writes an audit entry attributed to the request in ctx
+func (s *AuditServiceImpl) Record(ctx context.Context, record AuditRecord) error {
+	ctx, span := tracer.Start(ctx, "AuditService.Record")
+	defer span.End()
+
+	return recordAudit(ctx, s.repo, record)
+}
+
+// List gets the audit entries matching filter, newest first
+func (s *AuditServiceImpl) List(ctx context.Context, filter AuditFilter, limit, offset int32) ([]*AuditEntry, error) {
+	ctx, span := tracer.Start(ctx, "AuditService.List")
+	defer span.End()
+
+	params := repository.ListAuditEntriesParams{
+		EntityType: filter.EntityType,
+		EntityID:   filter.EntityID,
+		Actor:      filter.Actor,
+		Limit:      limit,
+		Offset:     offset,
+	}
+	if filter.From != nil {
+		params.CreatedFrom = pgtype.Timestamp{Time: *filter.From, Valid: true}
+	}
+	if filter.To != nil {
+		params.CreatedTo = pgtype.Timestamp{Time: *filter.To, Valid: true}
+	}
+
+	rows, err := s.repo.ListAuditEntries(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	entries := make([]*AuditEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = &AuditEntry{
+			ID:            row.ID,
+			ActorID:       row.ActorID,
+			ActorUsername: row.ActorUsername,
+			Action:        row.Action,
+			EntityType:    row.EntityType,
+			EntityID:      row.EntityID,
+			Before:        row.Before,
+			After:         row.After,
+			RequestID:     row.RequestID,
+			IP:            row.Ip,
+			CreatedAt:     row.CreatedAt,
+		}
+	}
+	return entries, nil
+}
+
+// recordAudit writes an audit entry, as part of the caller's transaction when q is bound to one, and
+// marks the request in ctx as audited. An update that changed nothing is not recorded.
+func recordAudit(ctx context.Context, q *repository.Queries, record AuditRecord) error {
+	before, after, err := auditDiff(record.Before, record.After)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s audit entry: %w", record.Action, err)
+	}
+
+	src := auditSourceFrom(ctx)
+	if src != nil {
+		src.recorded.Store(true)
+	}
+	if record.Before != nil && record.After != nil && before == nil {
+		return nil
+	}
+
+	params := repository.CreateAuditEntryParams{
+		Action:     record.Action,
+		EntityType: record.EntityType,
+		EntityID:   util.StringToPgText(record.EntityID),
+		Before:     before,
+		After:      after,
+	}
+	if src != nil {
+		if src.ActorID != uuid.Nil {
+			params.ActorID = pgtype.UUID{Bytes: src.ActorID, Valid: true}
+		}
+		params.ActorUsername = util.StringToPgText(src.ActorUsername)
+		params.RequestID = util.StringToPgText(src.RequestID)
+		params.Ip = util.StringToPgText(src.IP)
+	}
+
+	if _, err := q.CreateAuditEntry(ctx, params); err != nil {
+		return fmt.Errorf("failed to record %s audit entry: %w", record.Action, err)
+	}
+	return nil
+}
+
+// auditDiff encodes the fields of before and after that differ. Either may be nil, in which case all
+// fields of the other are kept. Both results are nil when nothing changed.
+func auditDiff(before, after any) ([]byte, []byte, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for name, value := range beforeFields {
+			if reflect.DeepEqual(value, afterFields[name]) {
+				delete(beforeFields, name)
+				delete(afterFields, name)
+			}
+		}
+		if len(beforeFields) == 0 && len(afterFields) == 0 {
+			return nil, nil, nil
+		}
+	}
+	for name := range auditRedacted {
+		if _, ok := beforeFields[name]; ok {
+			beforeFields[name] = auditRedactedValue
+		}
+		if _, ok := afterFields[name]; ok {
+			afterFields[name] = auditRedactedValue
+		}
+	}
+
+	beforeJSON, err := encodeAuditFields(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := encodeAuditFields(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+// auditFields decodes an entity's JSON representation into its fields, leaving out ignored ones
+func auditFields(v any) (map[string]any, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for name := range auditIgnored {
+		delete(fields, name)
+	}
+	return fields, nil
+}
+
+func encodeAuditFields(fields map[string]any) ([]byte, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vasujain275/bookbridge-api/internal/citation"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+var (
+	// ErrAuthorExists is returned when creating or renaming an author to a name already in use
+	ErrAuthorExists = errors.New("author with this name already exists")
+	// ErrBookAuthorExists is returned when attaching an author a book already credits
+	ErrBookAuthorExists = errors.New("author is already attached to this book")
+	// ErrBookAuthorTarget is returned when attaching an author to a book when either does not exist
+	ErrBookAuthorTarget = errors.New("book or author not found")
+)
+
+// AuthorParams holds the editable fields of an author
+type AuthorParams struct {
+	Name           string
+	Bio            string
+	OpenLibraryKey string
+	Photos         []int
+	AlternateNames []string
+	PersonalName   string
+	Links          []AuthorLink
+	BirthDate      *time.Time
+	DeathDate      *time.Time
+}
+
+// AuthorServiceImpl implements the AuthorService interface
+type AuthorServiceImpl struct {
+	repo *repository.Queries
+}
+
+// NewAuthorService creates a new author service
+func NewAuthorService(repo *repository.Queries) AuthorService {
+	return &AuthorServiceImpl{
+		repo: repo,
+	}
+}
+
+// GetByID gets an author by ID
+func (s *AuthorServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*Author, error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.GetByID")
+	defer span.End()
+
+	author, err := s.repo.GetAuthor(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author: %w", mergedInto(ctx, s.repo, MergeEntityAuthor, id, err))
+	}
+	return toAuthor(author)
+}
+
+// GetByName gets an author by name
+func (s *AuthorServiceImpl) GetByName(ctx context.Context, name string) (*Author, error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.GetByName")
+	defer span.End()
+
+	author, err := s.repo.GetAuthorByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author by name: %w", err)
+	}
+	return toAuthor(author)
+}
+
+// List gets a list of authors
+func (s *AuthorServiceImpl) List(ctx context.Context, limit, offset int32) ([]*Author, error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.List")
+	defer span.End()
+
+	authors, err := s.repo.ListAuthors(ctx, repository.ListAuthorsParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list authors: %w", err)
+	}
+	return toAuthors(authors)
+}
+
+// Create creates a new author
+func (s *AuthorServiceImpl) Create(ctx context.Context, params AuthorParams) (*Author, error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.Create")
+	defer span.End()
+
+	photos, alternateNames, links, err := encodeAuthorJSON(params)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.repo.CreateAuthor(ctx, repository.CreateAuthorParams{
+		Name:           params.Name,
+		Bio:            util.StringToPgText(params.Bio),
+		OpenlibraryKey: util.StringToPgText(params.OpenLibraryKey),
+		Photos:         photos,
+		AlternateNames: alternateNames,
+		PersonalName:   util.StringToPgText(params.PersonalName),
+		Links:          links,
+		BirthDate:      timeToPgDate(params.BirthDate),
+		DeathDate:      timeToPgDate(params.DeathDate),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAuthorExists
+		}
+		return nil, fmt.Errorf("failed to create author: %w", err)
+	}
+	return toAuthor(author)
+}
+
+// Update replaces an author's fields
+func (s *AuthorServiceImpl) Update(ctx context.Context, id uuid.UUID, params AuthorParams) (*Author, error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.Update")
+	defer span.End()
+
+	photos, alternateNames, links, err := encodeAuthorJSON(params)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.repo.UpdateAuthor(ctx, repository.UpdateAuthorParams{
+		ID:             id,
+		Name:           params.Name,
+		Bio:            util.StringToPgText(params.Bio),
+		OpenlibraryKey: util.StringToPgText(params.OpenLibraryKey),
+		Photos:         photos,
+		AlternateNames: alternateNames,
+		PersonalName:   util.StringToPgText(params.PersonalName),
+		Links:          links,
+		BirthDate:      timeToPgDate(params.BirthDate),
+		DeathDate:      timeToPgDate(params.DeathDate),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAuthorExists
+		}
+		return nil, fmt.Errorf("failed to update author: %w", err)
+	}
+	return toAuthor(author)
+}
+
+// Delete deletes an author. Books keep their other authors.
+func (s *AuthorServiceImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "AuthorService.Delete")
+	defer span.End()
+
+	err := s.repo.DeleteAuthor(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete author: %w", err)
+	}
+	return nil
+}
+
+// ListByBookID gets the authors of a book
+func (s *AuthorServiceImpl) ListByBookID(ctx context.Context, bookID uuid.UUID) ([]*Author, error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.ListByBookID")
+	defer span.End()
+
+	authors, err := s.repo.ListAuthorsByBookID(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list authors: %w", err)
+	}
+	return toAuthors(authors)
+}
+
+// Bibliography gets an author's works in publication order, each with the library's editions
+func (s *AuthorServiceImpl) Bibliography(ctx context.Context, id uuid.UUID) (*AuthorBibliography, error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.Bibliography")
+	defer span.End()
+
+	author, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := s.repo.ListBooksByAuthorID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list books: %w", err)
+	}
+
+	entries := make(map[uuid.UUID]*BibliographyEntry)
+	bibliography := &AuthorBibliography{
+		Author: author,
+		Works:  make([]*BibliographyEntry, 0, len(books)),
+	}
+	for i := range books {
+		book := &books[i]
+		entry, ok := entries[book.WorkID]
+		if !ok {
+			entry = &BibliographyEntry{WorkID: book.WorkID, Title: book.Title}
+			entries[book.WorkID] = entry
+			bibliography.Works = append(bibliography.Works, entry)
+		}
+		entry.Editions = append(entry.Editions, book)
+
+		// A work is dated by its earliest edition
+		year := citation.ExtractYear(util.PgTextToString(book.PublishedDate))
+		if year != "" && (entry.Year == "" || year < entry.Year) {
+			entry.Year = year
+		}
+	}
+
+	// Undated works go last
+	sort.SliceStable(bibliography.Works, func(i, j int) bool {
+		a, b := bibliography.Works[i], bibliography.Works[j]
+		if a.Year != b.Year {
+			return b.Year == "" || (a.Year != "" && a.Year < b.Year)
+		}
+		return a.Title < b.Title
+	})
+	return bibliography, nil
+}
+
+// AddBookAuthor credits an author on a book
+func (s *AuthorServiceImpl) AddBookAuthor(ctx context.Context, bookID, authorID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "AuthorService.AddBookAuthor")
+	defer span.End()
+
+	err := s.repo.AddBookAuthor(ctx, repository.AddBookAuthorParams{
+		BookID:   bookID,
+		AuthorID: authorID,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return ErrBookAuthorExists
+			case "23503":
+				return ErrBookAuthorTarget
+			}
+		}
+		return fmt.Errorf("failed to add book author: %w", err)
+	}
+	return nil
+}
+
+// RemoveBookAuthor removes an author from a book
+func (s *AuthorServiceImpl) RemoveBookAuthor(ctx context.Context, bookID, authorID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "AuthorService.RemoveBookAuthor")
+	defer span.End()
+
+	err := s.repo.RemoveBookAuthor(ctx, repository.RemoveBookAuthorParams{
+		BookID:   bookID,
+		AuthorID: authorID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove book author: %w", err)
+	}
+	return nil
+}
+
+// RemoveAllBookAuthors removes every author from a book
+func (s *AuthorServiceImpl) RemoveAllBookAuthors(ctx context.Context, bookID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "AuthorService.RemoveAllBookAuthors")
+	defer span.End()
+
+	err := s.repo.RemoveAllBookAuthors(ctx, bookID)
+	if err != nil {
+		return fmt.Errorf("failed to remove book authors: %w", err)
+	}
+	return nil
+}
+
+// toAuthor decodes an author's JSONB columns
+func toAuthor(a repository.Author) (*Author, error) {
+	author := &Author{
+		ID:             a.ID,
+		Name:           a.Name,
+		Bio:            a.Bio,
+		OpenlibraryKey: a.OpenlibraryKey,
+		PersonalName:   a.PersonalName,
+		BirthDate:      a.BirthDate,
+		DeathDate:      a.DeathDate,
+		CreatedAt:      a.CreatedAt,
+		UpdatedAt:      a.UpdatedAt,
+	}
+	if err := decodeJSONB(a.Photos, &author.Photos); err != nil {
+		return nil, fmt.Errorf("failed to decode author photos: %w", err)
+	}
+	if err := decodeJSONB(a.AlternateNames, &author.AlternateNames); err != nil {
+		return nil, fmt.Errorf("failed to decode author alternate names: %w", err)
+	}
+	if err := decodeJSONB(a.Links, &author.Links); err != nil {
+		return nil, fmt.Errorf("failed to decode author links: %w", err)
+	}
+	return author, nil
+}
+
+func toAuthors(authors []repository.Author) ([]*Author, error) {
+	result := make([]*Author, len(authors))
+	for i := range authors {
+		author, err := toAuthor(authors[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = author
+	}
+	return result, nil
+}
+
+// encodeAuthorJSON encodes the JSONB columns of an author, leaving empty lists NULL
+func encodeAuthorJSON(params AuthorParams) (photos, alternateNames, links []byte, err error) {
+	if photos, err = encodeJSONB(params.Photos, len(params.Photos)); err != nil {
+		return nil, nil, nil, err
+	}
+	if alternateNames, err = encodeJSONB(params.AlternateNames, len(params.AlternateNames)); err != nil {
+		return nil, nil, nil, err
+	}
+	if links, err = encodeJSONB(params.Links, len(params.Links)); err != nil {
+		return nil, nil, nil, err
+	}
+	return photos, alternateNames, links, nil
+}
+
+func encodeJSONB(v any, n int) ([]byte, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode author fields: %w", err)
+	}
+	return b, nil
+}
+
+func decodeJSONB(b []byte, v any) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
+
+func timeToPgDate(t *time.Time) pgtype.Date {
+	if t == nil {
+		return pgtype.Date{}
+	}
+	return pgtype.Date{Time: *t, Valid: true}
+}
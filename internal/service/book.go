@@ -2,50 +2,146 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/callnumber"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/isbn"
 	"github.com/vasujain275/bookbridge-api/internal/repository"
 	"github.com/vasujain275/bookbridge-api/internal/util"
 )
 
+// Book list orderings
+const (
+	BookSortTitle  = "title"
+	BookSortRating = "rating"
+)
+
+// ErrBookSort is returned for book list orderings other than title and rating
+var ErrBookSort = errors.New("sort must be title or rating")
+
 type BookServiceImpl struct {
+	db                 *database.DB
 	repo               *repository.Queries
 	openLibraryService OpenLibraryService
+	coverService       CoverService
+	callNumberScheme   string
 }
 
-// NewBookService creates a new book service
-func NewBookService(repo *repository.Queries, openLibraryService OpenLibraryService) BookService {
+// NewBookService creates a new book service. New books get call numbers in callNumberScheme when Open Library has both classifications.
+func NewBookService(db *database.DB, repo *repository.Queries, openLibraryService OpenLibraryService, coverService CoverService, callNumberScheme string) BookService {
 	return &BookServiceImpl{
+		db:                 db,
 		repo:               repo,
 		openLibraryService: openLibraryService,
+		coverService:       coverService,
+		callNumberScheme:   callNumberScheme,
 	}
 }
 
 // GetByID gets a book by ID
 func (s *BookServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*repository.Book, error) {
+	ctx, span := tracer.Start(ctx, "BookService.GetByID")
+	defer span.End()
+
 	book, err := s.repo.GetBook(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get book: %w", err)
+		return nil, fmt.Errorf("failed to get book: %w", mergedInto(ctx, s.repo, MergeEntityBook, id, err))
 	}
 	return &book, nil
 }
 
-// GetByISBN gets a book by ISBN
-func (s *BookServiceImpl) GetByISBN(ctx context.Context, isbn string) (*repository.Book, error) {
-	book, err := s.repo.GetBookByISBN(ctx, isbn)
+// GetByISBN gets a book by ISBN-10 or ISBN-13, with or without hyphens
+func (s *BookServiceImpl) GetByISBN(ctx context.Context, rawISBN string) (*repository.Book, error) {
+	ctx, span := tracer.Start(ctx, "BookService.GetByISBN")
+	defer span.End()
+
+	parsed, err := isbn.Parse(rawISBN)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := s.repo.GetBookByISBN(ctx, parsed.ISBN13)
+	if errors.Is(err, pgx.ErrNoRows) && parsed.ISBN10 != "" {
+		// Fall back to the ISBN-10 column for books stored without a matching ISBN-13
+		book, err = s.repo.GetBookByISBN10(ctx, util.StringToPgText(parsed.ISBN10))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get book by ISBN: %w", err)
 	}
 	return &book, nil
 }
 
-// List gets a list of books
-func (s *BookServiceImpl) List(ctx context.Context, limit, offset int32) ([]*repository.Book, error) {
-	books, err := s.repo.ListBooks(ctx, repository.ListBooksParams{
-		Limit:  limit,
-		Offset: offset,
-	})
+// GetFullBookDetails gets a book with its authors, categories, place in any series and the books its borrowers also borrowed
+func (s *BookServiceImpl) GetFullBookDetails(ctx context.Context, id uuid.UUID) (*BookDetails, error) {
+	ctx, span := tracer.Start(ctx, "BookService.GetFullBookDetails")
+	defer span.End()
+
+	book, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := s.repo.ListAuthorsByBookID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list authors: %w", err)
+	}
+	bookAuthors, err := toAuthors(authors)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.repo.ListCategoriesByBookID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	series, err := seriesNavigation(ctx, s.repo, book.WorkID)
+	if err != nil {
+		return nil, err
+	}
+	also, err := alsoBorrowed(ctx, s.repo, id, alsoBorrowedLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &BookDetails{
+		Book:         book,
+		Authors:      bookAuthors,
+		Categories:   make([]*repository.Category, len(categories)),
+		Series:       series,
+		CallNumbers:  callnumber.Copies(util.PgTextToString(book.CallNumber), int(book.TotalCopies)),
+		AlsoBorrowed: also,
+	}
+	for i := range categories {
+		details.Categories[i] = &categories[i]
+	}
+	return details, nil
+}
+
+// List gets a list of books ordered by title, or by average rating with the most reviewed first among ties
+func (s *BookServiceImpl) List(ctx context.Context, sort string, limit, offset int32) ([]*repository.Book, error) {
+	ctx, span := tracer.Start(ctx, "BookService.List")
+	defer span.End()
+
+	var books []repository.Book
+	var err error
+	switch sort {
+	case "", BookSortTitle:
+		books, err = s.repo.ListBooks(ctx, repository.ListBooksParams{
+			Limit:  limit,
+			Offset: offset,
+		})
+	case BookSortRating:
+		books, err = s.repo.ListBooksByRating(ctx, repository.ListBooksByRatingParams{
+			Limit:  limit,
+			Offset: offset,
+		})
+	default:
+		return nil, ErrBookSort
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to list books: %w", err)
 	}
@@ -58,27 +154,98 @@ func (s *BookServiceImpl) List(ctx context.Context, limit, offset int32) ([]*rep
 }
 
 // Create creates a new book
-func (s *BookServiceImpl) Create(ctx context.Context, isbn string) (*repository.Book, error) {
-	fetchedBook, err := s.openLibraryService.GetByISBN(isbn)
+func (s *BookServiceImpl) Create(ctx context.Context, rawISBN string) (*repository.Book, error) {
+	ctx, span := tracer.Start(ctx, "BookService.Create")
+	defer span.End()
 
+	// Reject malformed ISBNs before calling OpenLibrary
+	parsed, err := isbn.Parse(rawISBN)
 	if err != nil {
-		fmt.Println("Error fetching book from OpenLibrary:", err)
-	}
-
-	book, err := s.repo.CreateBook(ctx, repository.CreateBookParams{
-		Isbn10:        util.StringToPgText(fetchedBook.ISBN10[0]),
-		Isbn13:        fetchedBook.ISBN13[0],
-		Title:         fetchedBook.Title,
-		Publisher:     util.StringToPgText(fetchedBook.Publishers[0]),
-		PublishedDate: util.StringToPgText(fetchedBook.PublishDate),
-		Description:   util.StringToPgText(fetchedBook.Bio),
-		PageCount:     util.Int32ToPgInt(int32(fetchedBook.NumberOfPages)),
-		Language:      util.StringToPgText(fetchedBook.Languages[0].Key),
-		ThumbnailUrl:  util.StringToPgText(fmt.Sprintf("https://covers.openlibrary.org/b/id/%d-L.jpg", fetchedBook.Covers[0])),
-	})
+		return nil, err
+	}
 
+	fetchedBook, err := s.openLibraryService.GetByISBN(ctx, parsed.ISBN13)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create book: %w", err)
+		return nil, fmt.Errorf("failed to fetch book from OpenLibrary: %w", err)
+	}
+
+	var openLibraryCoverURL string
+	if len(fetchedBook.Covers) > 0 {
+		openLibraryCoverURL = fmt.Sprintf("https://covers.openlibrary.org/b/id/%d-L.jpg?default=false", fetchedBook.Covers[0])
+	}
+
+	// Group the edition under its Open Library work so other editions share it
+	var workKey string
+	if len(fetchedBook.Works) > 0 {
+		workKey = fetchedBook.Works[0].Key
+	}
+
+	var publisher, language string
+	if len(fetchedBook.Publishers) > 0 {
+		publisher = fetchedBook.Publishers[0]
+	}
+	if len(fetchedBook.Languages) > 0 {
+		language = fetchedBook.Languages[0].Key
+	}
+
+	var book repository.Book
+	err = s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		work, err := resolveWork(ctx, q, fetchedBook.Title, workKey)
+		if err != nil {
+			return err
+		}
+
+		book, err = q.CreateBook(ctx, repository.CreateBookParams{
+			Isbn10:        util.StringToPgText(parsed.ISBN10),
+			Isbn13:        parsed.ISBN13,
+			Title:         fetchedBook.Title,
+			Publisher:     util.StringToPgText(publisher),
+			PublishedDate: util.StringToPgText(fetchedBook.PublishDate),
+			Description:   util.StringToPgText(fetchedBook.Bio),
+			PageCount:     util.Int32ToPgInt(int32(fetchedBook.NumberOfPages)),
+			Language:      util.StringToPgText(language),
+			ThumbnailUrl:  util.StringToPgText(openLibraryCoverURL),
+			WorkID:        work.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create book: %w", err)
+		}
+
+		book, err = classifyBook(ctx, q, book,
+			fetchedBook.Classification("dewey_decimal_class"),
+			fetchedBook.Classification("lc_classifications"),
+			s.callNumberScheme)
+		if err != nil {
+			return err
+		}
+
+		if err := attachSeries(ctx, q, work.ID, fetchedBook.Series); err != nil {
+			return err
+		}
+		err = recordAudit(ctx, q, AuditRecord{
+			Action:     AuditBookCreate,
+			EntityType: AuditEntityBook,
+			EntityID:   book.ID.String(),
+			After:      &book,
+		})
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, q, EventBookCreated, EventBookCreated+":"+book.ID.String(), &book)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep a local copy of the cover; if that fails the OpenLibrary URL stays as the thumbnail
+	if openLibraryCoverURL != "" {
+		if _, err := s.coverService.ImportFromURL(ctx, book.ID, openLibraryCoverURL); err != nil {
+			slog.WarnContext(ctx, "Failed to import cover", "book_id", book.ID, "error", err)
+		} else {
+			book.ThumbnailUrl = util.StringToPgText(coverURL(book.ID))
+		}
 	}
 
 	return &book, nil
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+)
+
+var (
+	// ErrCategoryExists is returned when creating or renaming a category to a name already in use
+	ErrCategoryExists = errors.New("category with this name already exists")
+	// ErrCategoryCycle is returned when moving a category under itself or one of its descendants
+	ErrCategoryCycle = errors.New("category cannot be moved under itself or its descendants")
+	// ErrBookCategoryExists is returned when filing a book under a category it is already in
+	ErrBookCategoryExists = errors.New("book is already in this category")
+	// ErrBookCategoryTarget is returned when filing a book under a category when either does not exist
+	ErrBookCategoryTarget = errors.New("book or category not found")
+)
+
+// CategoryServiceImpl implements the CategoryService interface
+type CategoryServiceImpl struct {
+	db   *database.DB
+	repo *repository.Queries
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(db *database.DB, repo *repository.Queries) CategoryService {
+	return &CategoryServiceImpl{
+		db:   db,
+		repo: repo,
+	}
+}
+
+// GetByID gets a category by ID
+func (s *CategoryServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*repository.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryService.GetByID")
+	defer span.End()
+
+	category, err := s.repo.GetCategory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return &category, nil
+}
+
+// GetByName gets a category by name
+func (s *CategoryServiceImpl) GetByName(ctx context.Context, name string) (*repository.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryService.GetByName")
+	defer span.End()
+
+	category, err := s.repo.GetCategoryByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category by name: %w", err)
+	}
+	return &category, nil
+}
+
+// GetDetails gets a category with its breadcrumbs and direct children
+func (s *CategoryServiceImpl) GetDetails(ctx context.Context, id uuid.UUID) (*CategoryDetails, error) {
+	ctx, span := tracer.Start(ctx, "CategoryService.GetDetails")
+	defer span.End()
+
+	ancestors, err := s.repo.ListCategoryAncestors(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list category ancestors: %w", err)
+	}
+	if len(ancestors) == 0 {
+		return nil, fmt.Errorf("failed to get category: %w", pgx.ErrNoRows)
+	}
+
+	children, err := s.repo.ListCategoryChildren(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list child categories: %w", err)
+	}
+
+	return &CategoryDetails{
+		Category:    &ancestors[len(ancestors)-1],
+		Breadcrumbs: categoryPtrs(ancestors),
+		Children:    categoryPtrs(children),
+	}, nil
+}
+
+// List gets a flat list of categories
+func (s *CategoryServiceImpl) List(ctx context.Context, limit, offset int32) ([]*repository.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryService.List")
+	defer span.End()
+
+	categories, err := s.repo.ListCategories(ctx, repository.ListCategoriesParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return categoryPtrs(categories), nil
+}
+
+// Tree gets the whole taxonomy as a forest of root categories, with book counts on each node
+func (s *CategoryServiceImpl) Tree(ctx context.Context) ([]*CategoryNode, error) {
+	ctx, span := tracer.Start(ctx, "CategoryService.Tree")
+	defer span.End()
+
+	_, roots, err := s.buildTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return roots, nil
+}
+
+// Subtree gets the part of the taxonomy rooted at a category, with book counts on each node
+func (s *CategoryServiceImpl) Subtree(ctx context.Context, id uuid.UUID) (*CategoryNode, error) {
+	ctx, span := tracer.Start(ctx, "CategoryService.Subtree")
+	defer span.End()
+
+	nodes, _, err := s.buildTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := nodes[id]
+	if !ok {
+		return nil, fmt.Errorf("failed to get category: %w", pgx.ErrNoRows)
+	}
+	return node, nil
+}
+
+// ListBooks gets the books filed under a category or any of its descendants
+func (s *CategoryServiceImpl) ListBooks(ctx context.Context, id uuid.UUID, limit, offset int32) ([]*repository.Book, error) {
+	ctx, span := tracer.Start(ctx, "CategoryService.ListBooks")
+	defer span.End()
+
+	books, err := s.repo.ListBooksByCategoryTree(ctx, repository.ListBooksByCategoryTreeParams{
+		CategoryID: id,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list category books: %w", err)
+	}
+	bookPtrs := make([]*repository.Book, len(books))
+	for i := range books {
+		bookPtrs[i] = &books[i]
+	}
+	return bookPtrs, nil
+}
+
+// Create creates a new category, at the root when parentID is nil
+func (s *CategoryServiceImpl) Create(ctx context.Context, name string, parentID *uuid.UUID) (*repository.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryService.Create")
+	defer span.End()
+
+	category, err := s.repo.CreateCategory(ctx, repository.CreateCategoryParams{
+		Name:     name,
+		ParentID: uuidPtrToPg(parentID),
+	})
+	if err != nil {
+		return nil, categoryError("failed to create category", err)
+	}
+	return &category, nil
+}
+
+// Update renames a category. Its place in the tree and its books are unchanged.
+func (s *CategoryServiceImpl) Update(ctx context.Context, id uuid.UUID, name string) (*repository.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryService.Update")
+	defer span.End()
+
+	category, err := s.repo.UpdateCategory(ctx, repository.UpdateCategoryParams{
+		ID:   id,
+		Name: name,
+	})
+	if err != nil {
+		return nil, categoryError("failed to update category", err)
+	}
+	return &category, nil
+}
+
+// Move re-parents a category, carrying its whole subtree and book links with it.
+// A nil parentID moves it to the root.
+func (s *CategoryServiceImpl) Move(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) (*repository.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryService.Move")
+	defer span.End()
+
+	var category repository.Category
+	err := s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		// Moves run one at a time, so the ancestry checked below cannot change before this one commits
+		if err := q.LockCategoryTree(ctx); err != nil {
+			return fmt.Errorf("failed to lock categories: %w", err)
+		}
+
+		if parentID != nil {
+			// The new parent's ancestry must not pass through the category being moved
+			ancestors, err := q.ListCategoryAncestors(ctx, *parentID)
+			if err != nil {
+				return fmt.Errorf("failed to list category ancestors: %w", err)
+			}
+			if len(ancestors) == 0 {
+				return fmt.Errorf("failed to get parent category: %w", pgx.ErrNoRows)
+			}
+			for _, ancestor := range ancestors {
+				if ancestor.ID == id {
+					return ErrCategoryCycle
+				}
+			}
+		}
+
+		var err error
+		category, err = q.MoveCategory(ctx, repository.MoveCategoryParams{
+			ID:       id,
+			ParentID: uuidPtrToPg(parentID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to move category: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Delete deletes a category. Its children move up to its parent; its books lose only this category.
+func (s *CategoryServiceImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "CategoryService.Delete")
+	defer span.End()
+
+	return s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		category, err := q.GetCategory(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get category: %w", err)
+		}
+
+		err = q.ReparentCategoryChildren(ctx, repository.ReparentCategoryChildrenParams{
+			NewParentID: category.ParentID,
+			CategoryID:  pgtype.UUID{Bytes: id, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to move child categories: %w", err)
+		}
+
+		if err := q.DeleteCategory(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete category: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListByBookID gets the categories a book is filed under
+func (s *CategoryServiceImpl) ListByBookID(ctx context.Context, bookID uuid.UUID) ([]*repository.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryService.ListByBookID")
+	defer span.End()
+
+	categories, err := s.repo.ListCategoriesByBookID(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return categoryPtrs(categories), nil
+}
+
+// AddBookCategory files a book under a category
+func (s *CategoryServiceImpl) AddBookCategory(ctx context.Context, bookID, categoryID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "CategoryService.AddBookCategory")
+	defer span.End()
+
+	err := s.repo.AddBookCategory(ctx, repository.AddBookCategoryParams{
+		BookID:     bookID,
+		CategoryID: categoryID,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return ErrBookCategoryExists
+			case "23503":
+				return ErrBookCategoryTarget
+			}
+		}
+		return fmt.Errorf("failed to add book category: %w", err)
+	}
+	return nil
+}
+
+// RemoveBookCategory removes a book from a category
+func (s *CategoryServiceImpl) RemoveBookCategory(ctx context.Context, bookID, categoryID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "CategoryService.RemoveBookCategory")
+	defer span.End()
+
+	err := s.repo.RemoveBookCategory(ctx, repository.RemoveBookCategoryParams{
+		BookID:     bookID,
+		CategoryID: categoryID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove book category: %w", err)
+	}
+	return nil
+}
+
+// RemoveAllBookCategories removes a book from every category
+func (s *CategoryServiceImpl) RemoveAllBookCategories(ctx context.Context, bookID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "CategoryService.RemoveAllBookCategories")
+	defer span.End()
+
+	err := s.repo.RemoveAllBookCategories(ctx, bookID)
+	if err != nil {
+		return fmt.Errorf("failed to remove book categories: %w", err)
+	}
+	return nil
+}
+
+// buildTree loads every category and links them into a forest, returning the nodes by ID and the roots
+func (s *CategoryServiceImpl) buildTree(ctx context.Context) (map[uuid.UUID]*CategoryNode, []*CategoryNode, error) {
+	categories, err := s.repo.ListAllCategories(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	direct, err := s.repo.ListCategoryBookCounts(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count category books: %w", err)
+	}
+	totals, err := s.repo.ListCategoryTreeBookCounts(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count category books: %w", err)
+	}
+
+	nodes := make(map[uuid.UUID]*CategoryNode, len(categories))
+	for i := range categories {
+		nodes[categories[i].ID] = &CategoryNode{
+			Category: &categories[i],
+			Children: []*CategoryNode{},
+		}
+	}
+	for _, count := range direct {
+		if node, ok := nodes[count.CategoryID]; ok {
+			node.BookCount = count.BookCount
+		}
+	}
+	for _, count := range totals {
+		if node, ok := nodes[count.CategoryID]; ok {
+			node.TotalBookCount = count.BookCount
+		}
+	}
+
+	// Categories are sorted by name, so children end up in name order too
+	roots := []*CategoryNode{}
+	for i := range categories {
+		node := nodes[categories[i].ID]
+		parent, ok := nodes[categories[i].ParentID.Bytes]
+		if !categories[i].ParentID.Valid || !ok {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return nodes, roots, nil
+}
+
+func categoryError(msg string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return ErrCategoryExists
+		case "23503":
+			return fmt.Errorf("%s: parent category not found: %w", msg, pgx.ErrNoRows)
+		}
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+func categoryPtrs(categories []repository.Category) []*repository.Category {
+	ptrs := make([]*repository.Category, len(categories))
+	for i := range categories {
+		ptrs[i] = &categories[i]
+	}
+	return ptrs
+}
+
+func uuidPtrToPg(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}
+}
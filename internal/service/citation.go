@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/vasujain275/bookbridge-api/internal/citation"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// CitationServiceImpl implements the CitationService interface
+type CitationServiceImpl struct {
+	repo *repository.Queries
+}
+
+// NewCitationService creates a new citation service
+func NewCitationService(repo *repository.Queries) CitationService {
+	return &CitationServiceImpl{
+		repo: repo,
+	}
+}
+
+// Cite renders citations for the given books in the requested format, preserving the order of ids
+func (s *CitationServiceImpl) Cite(ctx context.Context, ids []uuid.UUID, format string) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "CitationService.Cite")
+	defer span.End()
+
+	if !citation.IsSupported(format) {
+		return nil, citation.ErrUnsupportedFormat
+	}
+
+	works := make([]citation.Work, 0, len(ids))
+	for _, id := range ids {
+		book, err := s.repo.GetBook(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get book %s: %w", id, err)
+		}
+		authors, err := s.repo.ListAuthorsByBookID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list authors for book %s: %w", id, err)
+		}
+		works = append(works, bookToWork(&book, authors))
+	}
+
+	return citation.Render(format, works)
+}
+
+// bookToWork maps a book and its authors to citation data
+func bookToWork(book *repository.Book, authors []repository.Author) citation.Work {
+	work := citation.Work{
+		ID:        book.ID.String(),
+		Title:     book.Title,
+		Publisher: util.PgTextToString(book.Publisher),
+		Year:      citation.ExtractYear(util.PgTextToString(book.PublishedDate)),
+		ISBN:      book.Isbn13,
+		Language:  strings.TrimPrefix(util.PgTextToString(book.Language), "/languages/"),
+		PageCount: util.PgIntToInt32(book.PageCount),
+	}
+	for _, author := range authors {
+		work.Authors = append(work.Authors, citation.ParseName(author.Name))
+	}
+	return work
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vasujain275/bookbridge-api/internal/cover"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/storage"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// maxCoverSize caps the size of a downloaded or uploaded cover image (10 MB)
+const maxCoverSize = 10 << 20
+
+// ErrCoverTooLarge is returned when a cover image is larger than maxCoverSize
+var ErrCoverTooLarge = errors.New("cover exceeds 10 MB")
+
+// CoverServiceImpl implements the CoverService interface
+type CoverServiceImpl struct {
+	repo    *repository.Queries
+	storage storage.Storage
+	client  *http.Client
+}
+
+// NewCoverService creates a new cover service
+func NewCoverService(repo *repository.Queries, store storage.Storage) CoverService {
+	return &CoverServiceImpl{
+		repo:    repo,
+		storage: store,
+		client: &http.Client{
+			Timeout:   15 * time.Second,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+	}
+}
+
+// coverKey is the storage key for a rendition of a book's cover
+func coverKey(bookID uuid.UUID, size cover.Size) string {
+	return fmt.Sprintf("covers/%s/%s.jpg", bookID, size)
+}
+
+// coverURL is the API path that serves a book's locally stored cover
+func coverURL(bookID uuid.UUID) string {
+	return fmt.Sprintf("/books/%s/cover?size=%s", bookID, cover.SizeMedium)
+}
+
+// Upload stores an uploaded cover image for a book
+func (s *CoverServiceImpl) Upload(ctx context.Context, bookID uuid.UUID, r io.Reader) (*repository.BookCover, error) {
+	ctx, span := tracer.Start(ctx, "CoverService.Upload")
+	defer span.End()
+
+	data, err := readCover(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.store(ctx, bookID, data, pgtype.Text{})
+}
+
+// ImportFromURL downloads a cover image and stores it for a book
+func (s *CoverServiceImpl) ImportFromURL(ctx context.Context, bookID uuid.UUID, url string) (*repository.BookCover, error) {
+	ctx, span := tracer.Start(ctx, "CoverService.ImportFromURL")
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cover request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download cover: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download cover: %s", resp.Status)
+	}
+
+	data, err := readCover(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return s.store(ctx, bookID, data, util.StringToPgText(url))
+}
+
+// Get opens a stored cover rendition for a book
+func (s *CoverServiceImpl) Get(ctx context.Context, bookID uuid.UUID, size cover.Size) (io.ReadCloser, *repository.BookCover, error) {
+	ctx, span := tracer.Start(ctx, "CoverService.Get")
+	defer span.End()
+
+	meta, err := s.repo.GetBookCover(ctx, bookID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cover: %w", err)
+	}
+
+	rc, err := s.storage.Get(ctx, coverKey(bookID, size))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read cover: %w", err)
+	}
+	return rc, &meta, nil
+}
+
+// store generates every rendition, writes them to storage and points the book's thumbnail at them
+func (s *CoverServiceImpl) store(ctx context.Context, bookID uuid.UUID, data []byte, sourceURL pgtype.Text) (*repository.BookCover, error) {
+	if _, err := s.repo.GetBook(ctx, bookID); err != nil {
+		return nil, fmt.Errorf("failed to get book: %w", err)
+	}
+
+	processed, err := cover.Process(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for size, rendition := range processed.Renditions {
+		if err := s.storage.Put(ctx, coverKey(bookID, size), rendition, cover.ContentType); err != nil {
+			return nil, fmt.Errorf("failed to store %s cover: %w", size, err)
+		}
+	}
+
+	meta, err := s.repo.UpsertBookCover(ctx, repository.UpsertBookCoverParams{
+		BookID:    bookID,
+		Etag:      processed.ETag,
+		SourceUrl: sourceURL,
+		Width:     int32(processed.Width),
+		Height:    int32(processed.Height),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save cover: %w", err)
+	}
+
+	if err := s.repo.UpdateBookThumbnail(ctx, repository.UpdateBookThumbnailParams{
+		ID:           bookID,
+		ThumbnailUrl: util.StringToPgText(coverURL(bookID)),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update book thumbnail: %w", err)
+	}
+
+	return &meta, nil
+}
+
+func readCover(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxCoverSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cover: %w", err)
+	}
+	if len(data) > maxCoverSize {
+		return nil, ErrCoverTooLarge
+	}
+	return data, nil
+}
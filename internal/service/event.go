@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+)
+
+// Domain event types
+const (
+	EventBookCreated   = "book.created"
+	EventLoanOverdue   = "loan.overdue"
+	EventHoldReady     = "hold.ready"
+	EventUserCreated   = "user.created"
+	EventReviewCreated = "review.created" // Recorded when a review is first published, on creation or approval
+)
+
+// EventTypes lists every domain event type
+var EventTypes = []string{
+	EventBookCreated,
+	EventLoanOverdue,
+	EventHoldReady,
+	EventUserCreated,
+	EventReviewCreated,
+}
+
+// Event is a domain event as delivered to subscribers
+type Event struct {
+	ID         uuid.UUID `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// UserEventData is the user in user events, leaving out the password hash
+type UserEventData struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewEvent creates an event of the given type that occurred now
+func NewEvent(eventType string, data any) Event {
+	return Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// decodeEventData decodes an event's data into v, whether it was read back from the outbox as raw
+// JSON or is still the value it was recorded with
+func decodeEventData(event Event, v any) error {
+	raw, ok := event.Data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(event.Data); err != nil {
+			return fmt.Errorf("failed to encode %s event data: %w", event.Type, err)
+		}
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("failed to decode %s event data: %w", event.Type, err)
+	}
+	return nil
+}
+
+func isEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func userEventData(user *repository.User) UserEventData {
+	return UserEventData{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		CreatedAt: user.CreatedAt.Time,
+	}
+}
+
+// EventPublisherFunc adapts a function to an EventPublisher, for in-process subscribers
+type EventPublisherFunc func(ctx context.Context, event Event) error
+
+// Publish calls f(ctx, event)
+func (f EventPublisherFunc) Publish(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// recordEvent writes an event to the outbox as part of the caller's transaction, so it is relayed to
+// subscribers once and only if the transaction commits. An event already recorded under the same
+// dedupe key is not recorded again.
+func recordEvent(ctx context.Context, q *repository.Queries, eventType, dedupeKey string, data any) error {
+	event := NewEvent(eventType, data)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	_, err = q.CreateOutboxEvent(ctx, repository.CreateOutboxEventParams{
+		ID:        event.ID,
+		EventType: eventType,
+		DedupeKey: dedupeKey,
+		Payload:   payload,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	if err := q.NotifyOutbox(ctx); err != nil {
+		return fmt.Errorf("failed to notify outbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+)
+
+// StreamEventTypes lists the circulation event types sent to live event streams
+var StreamEventTypes = []string{
+	EventLoanOverdue,
+	EventHoldReady,
+	EventBookCreated,
+}
+
+// Event stream tuning. Events are read from the outbox in batches of eventStreamBatch. An event is
+// stamped with the start of the transaction that recorded it, so one committed up to
+// eventStreamLookback after a later-stamped event has been read is still picked up.
+const (
+	eventStreamQueue    = 64 // How many events a stream may fall behind by before it is dropped
+	eventStreamBatch    = 100
+	eventStreamLookback = time.Minute
+)
+
+var (
+	ErrEventStreamTypes  = errors.New("unknown event type")
+	ErrEventStreamClosed = errors.New("event stream is shut down")
+)
+
+// EventStreamServiceImpl implements the EventStreamService interface. It reads the circulation events
+// committed to the outbox, by this or any other instance, and keeps the most recent so a reconnecting
+// stream can resume.
+type EventStreamServiceImpl struct {
+	db   *database.DB
+	repo *repository.Queries
+	size int
+
+	mu      sync.Mutex
+	recent  []Event
+	seen    map[uuid.UUID]time.Time // Events read within the lookback, by when they were recorded
+	cursor  pgtype.Timestamp        // When the newest event read was recorded
+	streams map[*EventStream]struct{}
+	closed  bool
+}
+
+// NewEventStreamService creates a new event stream service remembering the last bufferSize events
+func NewEventStreamService(db *database.DB, repo *repository.Queries, bufferSize int) EventStreamService {
+	return &EventStreamServiceImpl{
+		db:      db,
+		repo:    repo,
+		size:    max(bufferSize, 1),
+		seen:    make(map[uuid.UUID]time.Time),
+		streams: make(map[*EventStream]struct{}),
+	}
+}
+
+// EventStream is one subscriber's live feed of events. Backlog holds the buffered events missed
+// since the event it resumed after; Events is closed when the stream falls too far behind or the
+// service shuts down.
+type EventStream struct {
+	Backlog []Event
+	Events  <-chan Event
+
+	events  chan Event
+	types   map[string]bool
+	service *EventStreamServiceImpl
+}
+
+// Close stops delivering events to the stream
+func (s *EventStream) Close() {
+	s.service.mu.Lock()
+	defer s.service.mu.Unlock()
+	s.service.drop(s)
+}
+
+func (s *EventStream) wants(event Event) bool {
+	return len(s.types) == 0 || s.types[event.Type]
+}
+
+// Run sends newly recorded circulation events to the open streams until ctx is cancelled. The most
+// recent events are loaded first so streams can resume from them. Events are read as soon as the
+// transaction recording them commits, and at least every interval.
+func (s *EventStreamServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	if err := s.load(ctx); err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "Failed to load recent events for event streams", "error", err)
+	}
+
+	var conn *pgxpool.Conn
+	defer func() {
+		if conn != nil {
+			conn.Release()
+		}
+	}()
+
+	for {
+		if conn == nil {
+			var err error
+			if conn, err = listenOutbox(ctx, s.db); err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "Failed to listen for outbox events, polling instead", "error", err)
+			}
+		}
+		if err := waitOutbox(ctx, conn, interval); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.WarnContext(ctx, "Lost outbox notification connection", "error", err)
+			conn.Release()
+			conn = nil
+		}
+
+		if err := s.poll(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Failed to read events for event streams", "error", err)
+		}
+	}
+}
+
+// load fills the buffer with the most recent events, without sending them to any stream
+func (s *EventStreamServiceImpl) load(ctx context.Context) error {
+	rows, err := s.repo.ListLatestOutboxEvents(ctx, repository.ListLatestOutboxEventsParams{
+		EventTypes: StreamEventTypes,
+		Limit:      int32(s.size),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list recent events: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(rows) - 1; i >= 0; i-- {
+		s.add(rows[i], false)
+	}
+	return nil
+}
+
+// poll reads the events recorded since the last poll, less the lookback, and sends those not seen yet
+func (s *EventStreamServiceImpl) poll(ctx context.Context) error {
+	s.mu.Lock()
+	after := pgtype.Timestamp{InfinityModifier: pgtype.NegativeInfinity, Valid: true}
+	if s.cursor.Valid {
+		after = pgtype.Timestamp{Time: s.cursor.Time.Add(-eventStreamLookback), Valid: true}
+	}
+	s.mu.Unlock()
+	afterID := uuid.Nil
+
+	for {
+		rows, err := s.repo.ListOutboxEventsAfter(ctx, repository.ListOutboxEventsAfterParams{
+			AfterCreatedAt: after,
+			AfterID:        afterID,
+			EventTypes:     StreamEventTypes,
+			Limit:          eventStreamBatch,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list events: %w", err)
+		}
+
+		s.mu.Lock()
+		for _, row := range rows {
+			s.add(row, true)
+		}
+		s.forget()
+		s.mu.Unlock()
+
+		if len(rows) < eventStreamBatch {
+			return nil
+		}
+		last := rows[len(rows)-1]
+		after, afterID = last.CreatedAt, last.ID
+	}
+}
+
+// add buffers an event read from the outbox unless it was already read, and optionally sends it to
+// every open stream that wants it. The caller must hold s.mu.
+func (s *EventStreamServiceImpl) add(row repository.Outbox, send bool) {
+	if _, ok := s.seen[row.ID]; ok || s.closed {
+		return
+	}
+	event, err := decodeOutboxEvent(row)
+	if err != nil {
+		slog.Warn("Skipping undecodable event", "event_id", row.ID, "error", err)
+		return
+	}
+
+	s.seen[row.ID] = row.CreatedAt.Time
+	if !s.cursor.Valid || row.CreatedAt.Time.After(s.cursor.Time) {
+		s.cursor = row.CreatedAt
+	}
+	if len(s.recent) == s.size {
+		s.recent = append(s.recent[:0], s.recent[1:]...)
+	}
+	s.recent = append(s.recent, event)
+	if !send {
+		return
+	}
+
+	for stream := range s.streams {
+		if !stream.wants(event) {
+			continue
+		}
+		select {
+		case stream.events <- event:
+		default:
+			// Too slow; the client reconnects and resumes from the buffer
+			s.drop(stream)
+		}
+	}
+}
+
+// forget drops the seen events recorded before the lookback, which polls no longer read. The caller
+// must hold s.mu.
+func (s *EventStreamServiceImpl) forget() {
+	cutoff := s.cursor.Time.Add(-eventStreamLookback)
+	for id, recorded := range s.seen {
+		if recorded.Before(cutoff) {
+			delete(s.seen, id)
+		}
+	}
+}
+
+// Subscribe opens a stream of the given event types, or all circulation events if none are given.
+// With lastEventID, the buffered events after it are replayed first; an ID no longer buffered
+// replays nothing.
+func (s *EventStreamServiceImpl) Subscribe(lastEventID string, types []string) (*EventStream, error) {
+	wanted := make(map[string]bool, len(types))
+	for _, t := range types {
+		if !isStreamEventType(t) {
+			return nil, fmt.Errorf("%w %q, expected one of %s", ErrEventStreamTypes, t, strings.Join(StreamEventTypes, ", "))
+		}
+		wanted[t] = true
+	}
+
+	events := make(chan Event, eventStreamQueue)
+	stream := &EventStream{
+		Events:  events,
+		events:  events,
+		types:   wanted,
+		service: s,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrEventStreamClosed
+	}
+
+	if id, err := uuid.Parse(lastEventID); err == nil {
+		replay := false
+		for _, event := range s.recent {
+			if replay && stream.wants(event) {
+				stream.Backlog = append(stream.Backlog, event)
+			}
+			if event.ID == id {
+				replay = true
+			}
+		}
+	}
+
+	s.streams[stream] = struct{}{}
+	return stream, nil
+}
+
+// Close ends every open stream and refuses new ones, so streaming requests finish before the
+// server shuts down
+func (s *EventStreamServiceImpl) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for stream := range s.streams {
+		s.drop(stream)
+	}
+}
+
+// drop removes a stream and closes its channel. The caller must hold s.mu.
+func (s *EventStreamServiceImpl) drop(stream *EventStream) {
+	if _, ok := s.streams[stream]; !ok {
+		return
+	}
+	delete(s.streams, stream)
+	close(stream.events)
+}
+
+func isStreamEventType(eventType string) bool {
+	for _, t := range StreamEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
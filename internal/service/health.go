@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vasujain275/bookbridge-api/internal/database"
+)
+
+// Readiness statuses. A degraded service still takes traffic.
+const (
+	ReadinessReady        = "ready"
+	ReadinessDegraded     = "degraded"
+	ReadinessNotReady     = "not_ready"
+	ReadinessShuttingDown = "shutting_down"
+)
+
+// Health check statuses
+const (
+	HealthCheckOK       = "ok"
+	HealthCheckFailed   = "failed"
+	HealthCheckDegraded = "degraded"
+)
+
+// HealthPolicy configures the readiness checks. MigrationVersion is the schema version the server
+// needs; the database must be at it or newer. Open Library is only checked if CheckOpenLibrary is set.
+type HealthPolicy struct {
+	MigrationVersion int64
+	CheckOpenLibrary bool
+	Timeout          time.Duration
+}
+
+// HealthServiceImpl implements the HealthService interface
+type HealthServiceImpl struct {
+	db                 *database.DB
+	openLibraryService OpenLibraryService
+	policy             HealthPolicy
+
+	shuttingDown atomic.Bool
+}
+
+// NewHealthService creates a new health service
+func NewHealthService(db *database.DB, openLibraryService OpenLibraryService, policy HealthPolicy) HealthService {
+	return &HealthServiceImpl{
+		db:                 db,
+		openLibraryService: openLibraryService,
+		policy:             policy,
+	}
+}
+
+// ShutDown marks the server as shutting down, so it reports not ready from now on
+func (s *HealthServiceImpl) ShutDown() {
+	s.shuttingDown.Store(true)
+}
+
+// Ready runs the readiness checks concurrently, each within the policy's timeout. The server is not
+// ready if the database is unreachable or behind on migrations, and degraded if Open Library is
+// unreachable.
+func (s *HealthServiceImpl) Ready(ctx context.Context) *Readiness {
+	if s.shuttingDown.Load() {
+		return &Readiness{Status: ReadinessShuttingDown}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.policy.Timeout)
+	defer cancel()
+
+	readiness := &Readiness{
+		Status: ReadinessReady,
+		Checks: make(map[string]*HealthCheck),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	check := func(name, failure string, fn func(ctx context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := fn(ctx)
+			result := &HealthCheck{Status: HealthCheckOK, DurationMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				// The error may name hosts or schema details, so it is logged rather than returned
+				result.Status = failure
+				slog.WarnContext(ctx, "Readiness check failed", "check", name, "error", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			readiness.Checks[name] = result
+			switch {
+			case failure == HealthCheckFailed && err != nil:
+				readiness.Status = ReadinessNotReady
+			case failure == HealthCheckDegraded && err != nil && readiness.Status == ReadinessReady:
+				readiness.Status = ReadinessDegraded
+			}
+		}()
+	}
+
+	check("database", HealthCheckFailed, s.db.Pool.Ping)
+	check("migrations", HealthCheckFailed, s.checkMigrations)
+	if s.policy.CheckOpenLibrary {
+		check("openlibrary", HealthCheckDegraded, s.openLibraryService.Ping)
+	}
+	wg.Wait()
+
+	return readiness
+}
+
+// checkMigrations fails while the database's schema is older than the server needs
+func (s *HealthServiceImpl) checkMigrations(ctx context.Context) error {
+	version, err := s.db.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version < s.policy.MigrationVersion {
+		return fmt.Errorf("database is at migration %d, need %d", version, s.policy.MigrationVersion)
+	}
+	return nil
+}
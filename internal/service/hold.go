@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+)
+
+// Hold statuses
+const (
+	HoldStatusWaiting   = "waiting"
+	HoldStatusReady     = "ready"
+	HoldStatusFulfilled = "fulfilled"
+	HoldStatusCancelled = "cancelled"
+)
+
+var (
+	// ErrHoldExists is returned when a member already has an open hold on the work
+	ErrHoldExists = errors.New("user already has an open hold on this work")
+	// ErrHoldNotOpen is returned when cancelling a hold that is already fulfilled or cancelled
+	ErrHoldNotOpen = errors.New("hold is not open")
+	// ErrHoldTarget is returned when a hold names neither a work nor a book, or an edition of a different work
+	ErrHoldTarget = errors.New("hold must target a work or one of its editions")
+	// ErrHoldUser is returned when placing a hold for a user that does not exist
+	ErrHoldUser = errors.New("hold must be placed for an existing user")
+	// ErrHoldForbidden is returned when someone other than the hold's member or an admin reads or cancels a hold
+	ErrHoldForbidden = errors.New("only the hold's member or an admin may access this hold")
+)
+
+// PlaceHoldParams describes a new hold. At least one of WorkID and BookID must be set;
+// a hold with only a WorkID is satisfied by any edition of the work.
+type PlaceHoldParams struct {
+	UserID uuid.UUID
+	WorkID *uuid.UUID
+	BookID *uuid.UUID
+}
+
+// HoldServiceImpl implements the HoldService interface
+type HoldServiceImpl struct {
+	db   *database.DB
+	repo *repository.Queries
+}
+
+// NewHoldService creates a new hold service
+func NewHoldService(db *database.DB, repo *repository.Queries) HoldService {
+	return &HoldServiceImpl{
+		db:   db,
+		repo: repo,
+	}
+}
+
+// GetByID gets a hold by ID, returning ErrHoldForbidden unless the actor placed it or is an admin
+func (s *HoldServiceImpl) GetByID(ctx context.Context, actor *repository.User, id uuid.UUID) (*repository.Hold, error) {
+	ctx, span := tracer.Start(ctx, "HoldService.GetByID")
+	defer span.End()
+
+	hold, err := s.repo.GetHold(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+	if !ownsHold(actor, hold) {
+		return nil, ErrHoldForbidden
+	}
+	return &hold, nil
+}
+
+// ListByUserID gets a member's holds, newest first
+func (s *HoldServiceImpl) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]*repository.Hold, error) {
+	ctx, span := tracer.Start(ctx, "HoldService.ListByUserID")
+	defer span.End()
+
+	holds, err := s.repo.ListHoldsByUserID(ctx, repository.ListHoldsByUserIDParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holds: %w", err)
+	}
+	return holdPtrs(holds), nil
+}
+
+// ListByWorkID gets the open holds queue for a work, oldest first
+func (s *HoldServiceImpl) ListByWorkID(ctx context.Context, workID uuid.UUID) ([]*repository.Hold, error) {
+	ctx, span := tracer.Start(ctx, "HoldService.ListByWorkID")
+	defer span.End()
+
+	holds, err := s.repo.ListHoldsByWorkID(ctx, workID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list holds: %w", err)
+	}
+	return holdPtrs(holds), nil
+}
+
+// Place places a hold and, when a matching copy is on the shelf, sets it aside straight away
+func (s *HoldServiceImpl) Place(ctx context.Context, params PlaceHoldParams) (*repository.Hold, error) {
+	ctx, span := tracer.Start(ctx, "HoldService.Place")
+	defer span.End()
+
+	var hold repository.Hold
+	err := s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		workID, bookID, err := holdTarget(ctx, q, params)
+		if err != nil {
+			return err
+		}
+
+		hold, err = q.CreateHold(ctx, repository.CreateHoldParams{
+			UserID: params.UserID,
+			WorkID: workID,
+			BookID: bookID,
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				return ErrHoldExists
+			}
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return ErrHoldUser
+			}
+			return fmt.Errorf("failed to create hold: %w", err)
+		}
+
+		// Set aside a copy of the requested edition, or of any edition for work-level holds
+		candidate := bookID
+		if !candidate.Valid {
+			available, err := q.GetAvailableBookForWork(ctx, workID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to find an available edition: %w", err)
+			}
+			candidate = pgtype.UUID{Bytes: available.ID, Valid: true}
+		}
+
+		ready, err := readyHold(ctx, q, hold, candidate.Bytes)
+		if err != nil {
+			return err
+		}
+		if ready != nil {
+			hold = *ready
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// Cancel cancels an open hold. A copy set aside for it is released to the next hold in the queue.
+// Only the hold's member or an admin may cancel it.
+func (s *HoldServiceImpl) Cancel(ctx context.Context, actor *repository.User, id uuid.UUID) (*repository.Hold, error) {
+	ctx, span := tracer.Start(ctx, "HoldService.Cancel")
+	defer span.End()
+
+	var hold repository.Hold
+	err := s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		current, err := q.GetHold(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get hold: %w", err)
+		}
+		if !ownsHold(actor, current) {
+			return ErrHoldForbidden
+		}
+		if current.Status != HoldStatusWaiting && current.Status != HoldStatusReady {
+			return ErrHoldNotOpen
+		}
+
+		hold, err = q.UpdateHoldStatus(ctx, repository.UpdateHoldStatusParams{
+			ID:     id,
+			Status: HoldStatusCancelled,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to cancel hold: %w", err)
+		}
+
+		if current.Status == HoldStatusReady && current.AssignedBookID.Valid {
+			if _, err := q.ReleaseBookCopy(ctx, current.AssignedBookID.Bytes); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("failed to release copy: %w", err)
+			}
+			if _, err := promoteNext(ctx, q, current.AssignedBookID.Bytes); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// PromoteNext sets aside a copy of a book for the oldest waiting hold it satisfies.
+// It should be called whenever a copy is returned. It returns nil when nobody is waiting.
+func (s *HoldServiceImpl) PromoteNext(ctx context.Context, bookID uuid.UUID) (*repository.Hold, error) {
+	ctx, span := tracer.Start(ctx, "HoldService.PromoteNext")
+	defer span.End()
+
+	var hold *repository.Hold
+	err := s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		hold, err = promoteNext(ctx, s.repo.WithTx(tx), bookID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func promoteNext(ctx context.Context, q *repository.Queries, bookID uuid.UUID) (*repository.Hold, error) {
+	book, err := q.GetBook(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get book: %w", err)
+	}
+
+	next, err := q.GetNextWaitingHold(ctx, repository.GetNextWaitingHoldParams{
+		WorkID: book.WorkID,
+		BookID: pgtype.UUID{Bytes: book.ID, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next hold: %w", err)
+	}
+
+	return readyHold(ctx, q, next, book.ID)
+}
+
+// readyHold reserves a copy of bookID and marks the hold ready. It returns nil if no copy is available.
+func readyHold(ctx context.Context, q *repository.Queries, hold repository.Hold, bookID uuid.UUID) (*repository.Hold, error) {
+	if _, err := q.ReserveBookCopy(ctx, bookID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to reserve copy: %w", err)
+	}
+
+	ready, err := q.MarkHoldReady(ctx, repository.MarkHoldReadyParams{
+		ID:             hold.ID,
+		AssignedBookID: pgtype.UUID{Bytes: bookID, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark hold ready: %w", err)
+	}
+
+	dedupeKey := fmt.Sprintf("%s:%s:%s", EventHoldReady, ready.ID, ready.ReadyAt.Time.Format(time.RFC3339Nano))
+	if err := recordEvent(ctx, q, EventHoldReady, dedupeKey, ready); err != nil {
+		return nil, err
+	}
+	return &ready, nil
+}
+
+// holdTarget resolves the work a hold belongs to and the specific edition, if any
+func holdTarget(ctx context.Context, q *repository.Queries, params PlaceHoldParams) (uuid.UUID, pgtype.UUID, error) {
+	if params.BookID != nil {
+		book, err := q.GetBook(ctx, *params.BookID)
+		if err != nil {
+			return uuid.Nil, pgtype.UUID{}, fmt.Errorf("failed to get book: %w", err)
+		}
+		if params.WorkID != nil && *params.WorkID != book.WorkID {
+			return uuid.Nil, pgtype.UUID{}, ErrHoldTarget
+		}
+		return book.WorkID, pgtype.UUID{Bytes: book.ID, Valid: true}, nil
+	}
+
+	if params.WorkID == nil {
+		return uuid.Nil, pgtype.UUID{}, ErrHoldTarget
+	}
+	work, err := q.GetWork(ctx, *params.WorkID)
+	if err != nil {
+		return uuid.Nil, pgtype.UUID{}, fmt.Errorf("failed to get work: %w", err)
+	}
+	return work.ID, pgtype.UUID{}, nil
+}
+
+func ownsHold(actor *repository.User, hold repository.Hold) bool {
+	return actor != nil && (actor.ID == hold.UserID || actor.Role == RoleAdmin)
+}
+
+func holdPtrs(holds []repository.Hold) []*repository.Hold {
+	ptrs := make([]*repository.Hold, len(holds))
+	for i := range holds {
+		ptrs[i] = &holds[i]
+	}
+	return ptrs
+}
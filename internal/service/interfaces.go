@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vasujain275/bookbridge-api/internal/cover"
 	"github.com/vasujain275/bookbridge-api/internal/repository"
 	"github.com/vasujain275/bookbridge-api/internal/types"
 )
@@ -14,37 +18,97 @@ type UserService interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*repository.User, error)
 	GetByUsername(ctx context.Context, username string) (*repository.User, error)
 	GetByEmail(ctx context.Context, email string) (*repository.User, error)
+	Authenticate(ctx context.Context, username, password string) (*repository.User, error)
 	List(ctx context.Context, limit, offset int32) ([]*repository.User, error)
 	Create(ctx context.Context, params repository.CreateUserParams) (*repository.User, error)
+	EnsureAdmin(ctx context.Context, params repository.CreateUserParams) error
 	Update(ctx context.Context, params repository.UpdateUserParams) (*repository.User, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	Stats(ctx context.Context, id uuid.UUID, year int) (*ReadingStats, error)
+	SetHistoryRetention(ctx context.Context, id uuid.UUID, retain bool) (*repository.User, error)
+}
+
+// NotificationService defines the interface for email notifications
+type NotificationService interface {
+	EventPublisher
+	Preferences(ctx context.Context, userID uuid.UUID) (*repository.NotificationPreference, error)
+	SetPreferences(ctx context.Context, params repository.UpsertNotificationPreferencesParams) (*repository.NotificationPreference, error)
+	List(ctx context.Context, status string, limit, offset int32) ([]*repository.Notification, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]*repository.Notification, error)
+	Retry(ctx context.Context, id uuid.UUID) (*repository.Notification, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// EventPublisher defines the interface for subscribers that domain events are published to
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// OutboxService defines the interface for relaying recorded domain events to subscribers
+type OutboxService interface {
+	Subscribe(subscriber EventPublisher)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// HealthService defines the interface for readiness checks
+type HealthService interface {
+	Ready(ctx context.Context) *Readiness
+	ShutDown()
+}
+
+// AuditService defines the interface for the audit log of changes
+type AuditService interface {
+	Record(ctx context.Context, record AuditRecord) error
+	List(ctx context.Context, filter AuditFilter, limit, offset int32) ([]*AuditEntry, error)
+}
+
+// EventStreamService defines the interface for live circulation event streams
+type EventStreamService interface {
+	Subscribe(lastEventID string, types []string) (*EventStream, error)
+	Run(ctx context.Context, interval time.Duration)
+	Close()
+}
+
+// WebhookService defines the interface for webhook registration and delivery
+type WebhookService interface {
+	EventPublisher
+	GetByID(ctx context.Context, id uuid.UUID) (*repository.Webhook, error)
+	List(ctx context.Context) ([]*repository.Webhook, error)
+	Create(ctx context.Context, params WebhookParams) (*repository.Webhook, error)
+	Update(ctx context.Context, id uuid.UUID, params WebhookParams) (*repository.Webhook, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, status string, limit, offset int32) ([]*repository.WebhookDelivery, error)
+	Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*repository.WebhookDelivery, error)
+	Run(ctx context.Context, interval time.Duration)
 }
 
 // BookService defines the interface for book operations
 type BookService interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*repository.Book, error)
 	GetByISBN(ctx context.Context, isbn string) (*repository.Book, error)
-	List(ctx context.Context, limit, offset int32) ([]*repository.Book, error)
+	List(ctx context.Context, sort string, limit, offset int32) ([]*repository.Book, error)
 	Create(ctx context.Context, isbn string) (*repository.Book, error)
 	// Update(ctx context.Context, params repository.UpdateBookParams) (*repository.Book, error)
 	// UpdateCopies(ctx context.Context, params repository.UpdateBookCopiesParams) (*repository.Book, error)
 	// Delete(ctx context.Context, id uuid.UUID) error
-	// GetFullBookDetails(ctx context.Context, id uuid.UUID) (*BookDetails, error)
+	GetFullBookDetails(ctx context.Context, id uuid.UUID) (*BookDetails, error)
 }
 
 type OpenLibraryService interface {
-	GetByISBN(isbn string) (*types.OpenLibraryBook, error)
+	GetByISBN(ctx context.Context, isbn string) (*types.OpenLibraryBook, error)
+	Ping(ctx context.Context) error
 }
 
 // AuthorService defines the interface for author operations
 type AuthorService interface {
-	GetByID(ctx context.Context, id uuid.UUID) (*repository.Author, error)
-	GetByName(ctx context.Context, name string) (*repository.Author, error)
-	List(ctx context.Context, limit, offset int32) ([]*repository.Author, error)
-	Create(ctx context.Context, name string) (*repository.Author, error)
-	Update(ctx context.Context, id uuid.UUID, name string) (*repository.Author, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Author, error)
+	GetByName(ctx context.Context, name string) (*Author, error)
+	List(ctx context.Context, limit, offset int32) ([]*Author, error)
+	Create(ctx context.Context, params AuthorParams) (*Author, error)
+	Update(ctx context.Context, id uuid.UUID, params AuthorParams) (*Author, error)
 	Delete(ctx context.Context, id uuid.UUID) error
-	ListByBookID(ctx context.Context, bookID uuid.UUID) ([]*repository.Author, error)
+	ListByBookID(ctx context.Context, bookID uuid.UUID) ([]*Author, error)
+	Bibliography(ctx context.Context, id uuid.UUID) (*AuthorBibliography, error)
 	AddBookAuthor(ctx context.Context, bookID, authorID uuid.UUID) error
 	RemoveBookAuthor(ctx context.Context, bookID, authorID uuid.UUID) error
 	RemoveAllBookAuthors(ctx context.Context, bookID uuid.UUID) error
@@ -54,9 +118,14 @@ type AuthorService interface {
 type CategoryService interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*repository.Category, error)
 	GetByName(ctx context.Context, name string) (*repository.Category, error)
+	GetDetails(ctx context.Context, id uuid.UUID) (*CategoryDetails, error)
 	List(ctx context.Context, limit, offset int32) ([]*repository.Category, error)
-	Create(ctx context.Context, name string) (*repository.Category, error)
+	Tree(ctx context.Context) ([]*CategoryNode, error)
+	Subtree(ctx context.Context, id uuid.UUID) (*CategoryNode, error)
+	ListBooks(ctx context.Context, id uuid.UUID, limit, offset int32) ([]*repository.Book, error)
+	Create(ctx context.Context, name string, parentID *uuid.UUID) (*repository.Category, error)
 	Update(ctx context.Context, id uuid.UUID, name string) (*repository.Category, error)
+	Move(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) (*repository.Category, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	ListByBookID(ctx context.Context, bookID uuid.UUID) ([]*repository.Category, error)
 	AddBookCategory(ctx context.Context, bookID, categoryID uuid.UUID) error
@@ -78,22 +147,295 @@ type LoanService interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+// OverdueService defines the interface for the job marking loans overdue
+type OverdueService interface {
+	Run(ctx context.Context, interval time.Duration)
+}
+
 // ReviewService defines the interface for review operations
 type ReviewService interface {
-	GetByID(ctx context.Context, id uuid.UUID) (*repository.BookReview, error)
-	GetByUserAndBook(ctx context.Context, userID, bookID uuid.UUID) (*repository.BookReview, error)
-	List(ctx context.Context, limit, offset int32) ([]*repository.BookReview, error)
-	ListByBookID(ctx context.Context, bookID uuid.UUID, limit, offset int32) ([]*repository.BookReview, error)
-	ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]*repository.BookReview, error)
-	Create(ctx context.Context, params repository.CreateReviewParams) (*repository.BookReview, error)
-	Update(ctx context.Context, id uuid.UUID, rating int32, reviewText *string) (*repository.BookReview, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Review, error)
+	GetByUserAndBook(ctx context.Context, userID, bookID uuid.UUID) (*Review, error)
+	List(ctx context.Context, limit, offset int32) ([]*Review, error)
+	ListByBookID(ctx context.Context, bookID uuid.UUID, sort string, limit, offset int32) ([]*Review, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, includeUnpublished bool, limit, offset int32) ([]*Review, error)
+	Create(ctx context.Context, params repository.CreateReviewParams) (*Review, error)
+	Update(ctx context.Context, actor *repository.User, id uuid.UUID, rating int32, reviewText *string) (*Review, error)
+	Delete(ctx context.Context, actor *repository.User, id uuid.UUID) error
+	Vote(ctx context.Context, actor *repository.User, id uuid.UUID, helpful bool) (*Review, error)
+	Unvote(ctx context.Context, actor *repository.User, id uuid.UUID) (*Review, error)
+	Report(ctx context.Context, actor *repository.User, reviewID uuid.UUID, reason, details string) (*repository.ReviewReport, error)
+	ModerationQueue(ctx context.Context, limit, offset int32) ([]*ModerationItem, error)
+	Moderate(ctx context.Context, moderator *repository.User, id uuid.UUID, action, note string) (*Review, error)
+}
+
+// MarcService defines the interface for MARC21 and MARCXML import/export
+type MarcService interface {
+	ExportBook(ctx context.Context, id uuid.UUID, format string) ([]byte, error)
+	ExportAll(ctx context.Context, w io.Writer, format string) error
+	Import(ctx context.Context, r io.Reader, format string) (*MarcImportResult, error)
+}
+
+// CitationService defines the interface for rendering book citations
+type CitationService interface {
+	Cite(ctx context.Context, ids []uuid.UUID, format string) ([]byte, error)
+}
+
+// CoverService defines the interface for locally stored cover images
+type CoverService interface {
+	Upload(ctx context.Context, bookID uuid.UUID, r io.Reader) (*repository.BookCover, error)
+	ImportFromURL(ctx context.Context, bookID uuid.UUID, url string) (*repository.BookCover, error)
+	Get(ctx context.Context, bookID uuid.UUID, size cover.Size) (io.ReadCloser, *repository.BookCover, error)
+}
+
+// WorkService defines the interface for work operations
+type WorkService interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*WorkDetails, error)
+	Search(ctx context.Context, query string, limit, offset int32) ([]*WorkDetails, error)
+}
+
+// HoldService defines the interface for hold operations
+type HoldService interface {
+	GetByID(ctx context.Context, actor *repository.User, id uuid.UUID) (*repository.Hold, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]*repository.Hold, error)
+	ListByWorkID(ctx context.Context, workID uuid.UUID) ([]*repository.Hold, error)
+	Place(ctx context.Context, params PlaceHoldParams) (*repository.Hold, error)
+	Cancel(ctx context.Context, actor *repository.User, id uuid.UUID) (*repository.Hold, error)
+	PromoteNext(ctx context.Context, bookID uuid.UUID) (*repository.Hold, error)
+}
+
+// SeriesService defines the interface for series operations
+type SeriesService interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*SeriesDetails, error)
+	List(ctx context.Context, limit, offset int32) ([]*repository.Series, error)
+	Create(ctx context.Context, name, description string) (*repository.Series, error)
+	Update(ctx context.Context, id uuid.UUID, name, description string) (*repository.Series, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	SetVolume(ctx context.Context, seriesID, workID uuid.UUID, volume int32) error
+	RemoveWork(ctx context.Context, seriesID, workID uuid.UUID) error
+	ListByWorkID(ctx context.Context, workID uuid.UUID) ([]*SeriesNavigation, error)
+}
+
+// MergeService defines the interface for finding and merging duplicate authors and books
+type MergeService interface {
+	AuthorDuplicates(ctx context.Context, minSimilarity float64, limit, offset int32) ([]*DuplicateCandidate, error)
+	BookDuplicates(ctx context.Context, minSimilarity float64, limit, offset int32) ([]*DuplicateCandidate, error)
+	MergeAuthors(ctx context.Context, survivorID uuid.UUID, duplicateIDs []uuid.UUID) (*Author, error)
+	MergeBooks(ctx context.Context, survivorID uuid.UUID, duplicateIDs []uuid.UUID) (*repository.Book, error)
+}
+
+// ShelfService defines the interface for classifying books and browsing them in call-number order
+type ShelfService interface {
+	Browse(ctx context.Context, from, to, scheme string, limit, offset int32) ([]*ShelfEntry, error)
+	Classify(ctx context.Context, bookID uuid.UUID, dewey, lc string) (*ShelfEntry, error)
+}
+
+// ReadingListService defines the interface for members' reading lists
+type ReadingListService interface {
+	GetByID(ctx context.Context, actor *repository.User, id uuid.UUID) (*ReadingListDetails, error)
+	GetByShareToken(ctx context.Context, token string) (*ReadingListDetails, error)
+	ListByUserID(ctx context.Context, actor *repository.User, userID uuid.UUID, limit, offset int32) ([]*repository.ReadingList, error)
+	Create(ctx context.Context, actor *repository.User, kind string, params ReadingListParams) (*repository.ReadingList, error)
+	Update(ctx context.Context, actor *repository.User, id uuid.UUID, params ReadingListParams) (*repository.ReadingList, error)
+	Delete(ctx context.Context, actor *repository.User, id uuid.UUID) error
+	AddItem(ctx context.Context, actor *repository.User, listID, bookID uuid.UUID, note string) (*repository.ReadingListItem, error)
+	UpdateItem(ctx context.Context, actor *repository.User, listID, itemID uuid.UUID, note string) (*repository.ReadingListItem, error)
+	RemoveItem(ctx context.Context, actor *repository.User, listID, itemID uuid.UUID) error
+	Reorder(ctx context.Context, actor *repository.User, listID uuid.UUID, itemIDs []uuid.UUID) (*ReadingListDetails, error)
+	HoldUnavailable(ctx context.Context, actor *repository.User, listID uuid.UUID) (*ReadingListHolds, error)
+}
+
+// RecommendationService defines the interface for precomputed book recommendations
+type RecommendationService interface {
+	ForUser(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]*repository.ListUserRecommendationsRow, error)
+	AlsoBorrowed(ctx context.Context, bookID uuid.UUID, limit int32) ([]*repository.ListAlsoBorrowedRow, error)
+	Refresh(ctx context.Context) error
+	Run(ctx context.Context, interval time.Duration)
 }
 
 // BookDetails contains all information about a book including its related entities
 type BookDetails struct {
-	Book       *repository.Book         `json:"book"`
-	Authors    []*repository.Author     `json:"authors"`
-	Categories []*repository.Category   `json:"categories"`
-	Reviews    []*repository.BookReview `json:"reviews,omitempty"`
+	Book         *repository.Book                  `json:"book"`
+	Authors      []*Author                         `json:"authors"`
+	Categories   []*repository.Category            `json:"categories"`
+	Reviews      []*repository.BookReview          `json:"reviews,omitempty"`
+	Series       []*SeriesNavigation               `json:"series"`
+	CallNumbers  []string                          `json:"call_numbers"`
+	AlsoBorrowed []*repository.ListAlsoBorrowedRow `json:"also_borrowed"`
+}
+
+// Author is an author with its Open Library JSONB fields decoded
+type Author struct {
+	ID             uuid.UUID        `json:"id"`
+	Name           string           `json:"name"`
+	Bio            pgtype.Text      `json:"bio"`
+	OpenlibraryKey pgtype.Text      `json:"openlibrary_key"`
+	Photos         []int            `json:"photos"`
+	AlternateNames []string         `json:"alternate_names"`
+	PersonalName   pgtype.Text      `json:"personal_name"`
+	Links          []AuthorLink     `json:"links"`
+	BirthDate      pgtype.Date      `json:"birth_date"`
+	DeathDate      pgtype.Date      `json:"death_date"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+}
+
+// AuthorLink is an external link for an author, such as a website or Wikipedia page
+type AuthorLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// AuthorBibliography contains an author and their works held by the library
+type AuthorBibliography struct {
+	Author *Author              `json:"author"`
+	Works  []*BibliographyEntry `json:"works"`
+}
+
+// BibliographyEntry is one work in a bibliography with the library's editions of it
+type BibliographyEntry struct {
+	WorkID   uuid.UUID          `json:"work_id"`
+	Title    string             `json:"title"`
+	Year     string             `json:"year,omitempty"`
+	Editions []*repository.Book `json:"editions"`
+}
+
+// WorkDetails contains a work and all of its editions
+type WorkDetails struct {
+	Work            *repository.Work   `json:"work"`
+	Editions        []*repository.Book `json:"editions"`
+	AvailableCopies int32              `json:"available_copies"`
+}
+
+// SeriesDetails contains a series and its volumes in reading order
+type SeriesDetails struct {
+	Series  *repository.Series                 `json:"series"`
+	Volumes []*repository.ListSeriesVolumesRow `json:"volumes"`
+}
+
+// SeriesNavigation places a work within a series, with the volumes before and after it
+type SeriesNavigation struct {
+	Series   *repository.Series               `json:"series"`
+	Volume   int32                            `json:"volume"`
+	Previous *repository.ListSeriesVolumesRow `json:"previous"`
+	Next     *repository.ListSeriesVolumesRow `json:"next"`
+}
+
+// CategoryDetails contains a category, its breadcrumbs from the root down to itself, and its direct children
+type CategoryDetails struct {
+	Category    *repository.Category   `json:"category"`
+	Breadcrumbs []*repository.Category `json:"breadcrumbs"`
+	Children    []*repository.Category `json:"children"`
+}
+
+// CategoryNode is a category in the taxonomy tree. BookCount counts books filed directly under it;
+// TotalBookCount counts distinct books in it and all of its descendants.
+type CategoryNode struct {
+	Category       *repository.Category `json:"category"`
+	BookCount      int32                `json:"book_count"`
+	TotalBookCount int32                `json:"total_book_count"`
+	Children       []*CategoryNode      `json:"children"`
+}
+
+// DuplicateCandidate is a pair of authors or books that may be the same record.
+// Name holds the author name or book title.
+type DuplicateCandidate struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	DuplicateID   uuid.UUID `json:"duplicate_id"`
+	DuplicateName string    `json:"duplicate_name"`
+	Similarity    float64   `json:"similarity"`
+	Reasons       []string  `json:"reasons"`
+}
+
+// ShelfEntry is a book as it stands on the shelf, with the call number on each copy's spine label
+type ShelfEntry struct {
+	Book        *repository.Book `json:"book"`
+	CallNumbers []string         `json:"call_numbers"`
+}
+
+// Review is a book review with whether its author has borrowed and returned the book
+type Review struct {
+	repository.BookReview
+	VerifiedBorrower bool `json:"verified_borrower"`
+}
+
+// ModerationItem is a review awaiting a moderator with the reports members have filed against it
+type ModerationItem struct {
+	Review  *repository.BookReview     `json:"review"`
+	Reports []*repository.ReviewReport `json:"reports"`
+}
+
+// ReadingListDetails contains a reading list and its items in order, each with its book's availability
+type ReadingListDetails struct {
+	List  *repository.ReadingList               `json:"list"`
+	Items []*repository.ListReadingListItemsRow `json:"items"`
+}
+
+// ReadingListHolds reports the holds placed for a reading list's unavailable items and the items skipped
+type ReadingListHolds struct {
+	Placed  []*repository.Hold `json:"placed"`
+	Skipped []*SkippedHold     `json:"skipped"`
+}
+
+// SkippedHold is a reading list item no hold was placed for, with the reason
+type SkippedHold struct {
+	ItemID uuid.UUID `json:"item_id"`
+	BookID uuid.UUID `json:"book_id"`
+	Title  string    `json:"title"`
+	Reason string    `json:"reason"`
+}
+
+// ReadingStats summarizes a member's reading. Totals cover all retained history;
+// the InYear totals and Months cover Year only. Streaks are counted in months.
+type ReadingStats struct {
+	UserID             uuid.UUID                               `json:"user_id"`
+	Year               int                                     `json:"year"`
+	BooksRead          int32                                   `json:"books_read"`
+	PagesRead          int32                                   `json:"pages_read"`
+	BooksReadInYear    int32                                   `json:"books_read_in_year"`
+	PagesReadInYear    int32                                   `json:"pages_read_in_year"`
+	Months             []*MonthlyReading                       `json:"months"`
+	FavoriteCategories []*repository.ListFavoriteCategoriesRow `json:"favorite_categories"`
+	FavoriteAuthors    []*repository.ListFavoriteAuthorsRow    `json:"favorite_authors"`
+	TotalLoans         int32                                   `json:"total_loans"`
+	OpenLoans          int32                                   `json:"open_loans"`
+	AverageLoanDays    float64                                 `json:"average_loan_days"`
+	CurrentStreak      int                                     `json:"current_streak"`
+	LongestStreak      int                                     `json:"longest_streak"`
+	HistoryRetained    bool                                    `json:"history_retained"`
+}
+
+// MonthlyReading counts the books and pages a member read in a month, given as YYYY-MM
+type MonthlyReading struct {
+	Month string `json:"month"`
+	Books int32  `json:"books"`
+	Pages int32  `json:"pages"`
+}
+
+// AuditEntry is a change recorded in the audit log, with the changed fields before and after it
+type AuditEntry struct {
+	ID            uuid.UUID        `json:"id"`
+	ActorID       pgtype.UUID      `json:"actor_id"`
+	ActorUsername pgtype.Text      `json:"actor_username"`
+	Action        string           `json:"action"`
+	EntityType    string           `json:"entity_type"`
+	EntityID      pgtype.Text      `json:"entity_id"`
+	Before        json.RawMessage  `json:"before"`
+	After         json.RawMessage  `json:"after"`
+	RequestID     pgtype.Text      `json:"request_id"`
+	IP            pgtype.Text      `json:"ip"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+// Readiness reports whether the server can take traffic, with the outcome of each check
+type Readiness struct {
+	Status string                  `json:"status"`
+	Checks map[string]*HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck is the outcome of one readiness check
+type HealthCheck struct {
+	Status     string `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
 }
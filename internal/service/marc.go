@@ -0,0 +1,493 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/citation"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/isbn"
+	"github.com/vasujain275/bookbridge-api/internal/marc"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// Supported MARC serializations
+const (
+	MarcFormatBinary = "marc21"
+	MarcFormatXML    = "marcxml"
+)
+
+// exportPageSize is the number of books fetched per query during bulk export
+const exportPageSize = 100
+
+var (
+	// ErrUnsupportedMarcFormat is returned for formats other than marc21 and marcxml
+	ErrUnsupportedMarcFormat = errors.New("unsupported MARC format")
+	// ErrInvalidMarcData is returned when uploaded records cannot be parsed
+	ErrInvalidMarcData = errors.New("invalid MARC data")
+)
+
+// MarcImportError describes a record that could not be imported
+type MarcImportError struct {
+	Record int    `json:"record"`
+	Error  string `json:"error"`
+}
+
+// MarcImportResult summarizes a MARC import run
+type MarcImportResult struct {
+	Imported   []*repository.Book `json:"imported"`
+	Duplicates []string           `json:"duplicates"`
+	Errors     []MarcImportError  `json:"errors"`
+}
+
+// MarcServiceImpl implements the MarcService interface
+type MarcServiceImpl struct {
+	db               *database.DB
+	repo             *repository.Queries
+	callNumberScheme string
+}
+
+// NewMarcService creates a new MARC service. Imported books get call numbers in callNumberScheme when a record has both classifications.
+func NewMarcService(db *database.DB, repo *repository.Queries, callNumberScheme string) MarcService {
+	return &MarcServiceImpl{
+		db:               db,
+		repo:             repo,
+		callNumberScheme: callNumberScheme,
+	}
+}
+
+// ExportBook renders a single book as a MARC record
+func (s *MarcServiceImpl) ExportBook(ctx context.Context, id uuid.UUID, format string) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "MarcService.ExportBook")
+	defer span.End()
+
+	book, err := s.repo.GetBook(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get book: %w", err)
+	}
+
+	record, err := s.bookRecord(ctx, &book)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case MarcFormatBinary:
+		return record.MarshalBinary()
+	case MarcFormatXML:
+		return marc.MarshalXML(record)
+	default:
+		return nil, ErrUnsupportedMarcFormat
+	}
+}
+
+// ExportAll streams every book in the catalog as MARC records
+func (s *MarcServiceImpl) ExportAll(ctx context.Context, w io.Writer, format string) error {
+	ctx, span := tracer.Start(ctx, "MarcService.ExportAll")
+	defer span.End()
+
+	var write func(*marc.Record) error
+	var closeWriter func() error
+
+	switch format {
+	case MarcFormatBinary:
+		write = marc.NewWriter(w).Write
+		closeWriter = func() error { return nil }
+	case MarcFormatXML:
+		xw := marc.NewXMLWriter(w)
+		write = xw.Write
+		closeWriter = xw.Close
+	default:
+		return ErrUnsupportedMarcFormat
+	}
+
+	for offset := int32(0); ; offset += exportPageSize {
+		books, err := s.repo.ListBooks(ctx, repository.ListBooksParams{
+			Limit:  exportPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list books: %w", err)
+		}
+
+		for i := range books {
+			record, err := s.bookRecord(ctx, &books[i])
+			if err != nil {
+				return err
+			}
+			if err := write(record); err != nil {
+				return fmt.Errorf("failed to write MARC record: %w", err)
+			}
+		}
+
+		if len(books) < exportPageSize {
+			break
+		}
+	}
+
+	return closeWriter()
+}
+
+// Import parses MARC records and creates the books, authors and categories they describe.
+// Records whose ISBN is already in the catalog are reported as duplicates and skipped.
+func (s *MarcServiceImpl) Import(ctx context.Context, r io.Reader, format string) (*MarcImportResult, error) {
+	ctx, span := tracer.Start(ctx, "MarcService.Import")
+	defer span.End()
+
+	records, err := readMarcRecords(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MarcImportResult{
+		Imported:   []*repository.Book{},
+		Duplicates: []string{},
+		Errors:     []MarcImportError{},
+	}
+
+	for i, record := range records {
+		entry := parseMarcRecord(record)
+		if entry.book.Isbn13 == "" {
+			result.Errors = append(result.Errors, MarcImportError{Record: i, Error: "record has no valid ISBN"})
+			continue
+		}
+		if entry.book.Title == "" {
+			result.Errors = append(result.Errors, MarcImportError{Record: i, Error: "record has no title"})
+			continue
+		}
+
+		_, err := s.repo.GetBookByISBN(ctx, entry.book.Isbn13)
+		if err == nil {
+			result.Duplicates = append(result.Duplicates, entry.book.Isbn13)
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to check for existing book: %w", err)
+		}
+
+		book, err := s.importEntry(ctx, entry)
+		if err != nil {
+			result.Errors = append(result.Errors, MarcImportError{Record: i, Error: err.Error()})
+			continue
+		}
+		result.Imported = append(result.Imported, book)
+	}
+
+	return result, nil
+}
+
+// importEntry creates a book and links its authors and categories in a single transaction
+func (s *MarcServiceImpl) importEntry(ctx context.Context, entry marcEntry) (*repository.Book, error) {
+	var book repository.Book
+	err := s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		work, err := marcWork(ctx, q, entry)
+		if err != nil {
+			return err
+		}
+
+		params := entry.book
+		params.WorkID = work.ID
+		book, err = q.CreateBook(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to create book: %w", err)
+		}
+
+		for _, name := range entry.authors {
+			author, err := q.GetAuthorByName(ctx, name)
+			if errors.Is(err, pgx.ErrNoRows) {
+				author, err = q.CreateAuthor(ctx, repository.CreateAuthorParams{Name: name})
+			}
+			if err != nil {
+				return fmt.Errorf("failed to resolve author %q: %w", name, err)
+			}
+			if err := q.AddBookAuthor(ctx, repository.AddBookAuthorParams{BookID: book.ID, AuthorID: author.ID}); err != nil {
+				return fmt.Errorf("failed to link author %q: %w", name, err)
+			}
+		}
+
+		for _, name := range entry.categories {
+			category, err := q.GetCategoryByName(ctx, name)
+			if errors.Is(err, pgx.ErrNoRows) {
+				category, err = q.CreateCategory(ctx, repository.CreateCategoryParams{Name: name})
+			}
+			if err != nil {
+				return fmt.Errorf("failed to resolve category %q: %w", name, err)
+			}
+			if err := q.AddBookCategory(ctx, repository.AddBookCategoryParams{BookID: book.ID, CategoryID: category.ID}); err != nil {
+				return fmt.Errorf("failed to link category %q: %w", name, err)
+			}
+		}
+
+		// Classify last so the call number is cut from the main entry linked above
+		if entry.dewey != "" || entry.lc != "" {
+			book, err = classifyBook(ctx, q, book, entry.dewey, entry.lc, s.callNumberScheme)
+			if err != nil {
+				return err
+			}
+		}
+		return recordEvent(ctx, q, EventBookCreated, EventBookCreated+":"+book.ID.String(), &book)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// bookRecord loads a book's authors and categories and maps them to a MARC record
+func (s *MarcServiceImpl) bookRecord(ctx context.Context, book *repository.Book) (*marc.Record, error) {
+	authors, err := s.repo.ListAuthorsByBookID(ctx, book.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list authors for book: %w", err)
+	}
+	categories, err := s.repo.ListCategoriesByBookID(ctx, book.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories for book: %w", err)
+	}
+	return bookToMarcRecord(book, authors, categories), nil
+}
+
+// bookToMarcRecord maps a book to MARC21 bibliographic fields
+func bookToMarcRecord(book *repository.Book, authors []repository.Author, categories []repository.Category) *marc.Record {
+	record := marc.NewRecord()
+
+	record.AddControlField("001", book.ID.String())
+	if book.UpdatedAt.Valid {
+		record.AddControlField("005", book.UpdatedAt.Time.Format("20060102150405.0"))
+	}
+	record.AddControlField("008", fixedLengthData(book))
+
+	record.AddDataField("020", ' ', ' ', marc.Subfield{Code: 'a', Value: book.Isbn13})
+	record.AddDataField("020", ' ', ' ', marc.Subfield{Code: 'a', Value: util.PgTextToString(book.Isbn10)})
+	record.AddDataField("050", ' ', '4', marc.Subfield{Code: 'a', Value: util.PgTextToString(book.LcClassification)})
+	record.AddDataField("082", '0', '4', marc.Subfield{Code: 'a', Value: util.PgTextToString(book.DeweyDecimalClass)})
+
+	// The first author is the main entry, the rest are added entries
+	titleInd1 := byte('0')
+	for i, author := range authors {
+		tag := "700"
+		if i == 0 {
+			tag = "100"
+			titleInd1 = '1'
+		}
+		record.AddDataField(tag, '1', ' ', marc.Subfield{Code: 'a', Value: author.Name})
+	}
+
+	record.AddDataField("245", titleInd1, '0', marc.Subfield{Code: 'a', Value: book.Title})
+	record.AddDataField("260", ' ', ' ',
+		marc.Subfield{Code: 'b', Value: util.PgTextToString(book.Publisher)},
+		marc.Subfield{Code: 'c', Value: util.PgTextToString(book.PublishedDate)},
+	)
+	if pages := util.PgIntToInt32(book.PageCount); pages > 0 {
+		record.AddDataField("300", ' ', ' ', marc.Subfield{Code: 'a', Value: fmt.Sprintf("%d pages", pages)})
+	}
+	record.AddDataField("520", ' ', ' ', marc.Subfield{Code: 'a', Value: util.PgTextToString(book.Description)})
+
+	for _, category := range categories {
+		record.AddDataField("650", ' ', '4', marc.Subfield{Code: 'a', Value: category.Name})
+	}
+
+	record.AddDataField("856", '4', '2',
+		marc.Subfield{Code: '3', Value: "Cover image"},
+		marc.Subfield{Code: 'u', Value: util.PgTextToString(book.ThumbnailUrl)},
+	)
+
+	return record
+}
+
+// fixedLengthData builds the 40 character 008 field
+func fixedLengthData(book *repository.Book) string {
+	entered := "000000"
+	if book.CreatedAt.Valid {
+		entered = book.CreatedAt.Time.Format("060102")
+	}
+
+	dateType, year := "n", "uuuu"
+	if y := citation.ExtractYear(util.PgTextToString(book.PublishedDate)); y != "" {
+		dateType, year = "s", y
+	}
+
+	lang := strings.TrimPrefix(util.PgTextToString(book.Language), "/languages/")
+	if len(lang) != 3 {
+		lang = "und"
+	}
+
+	return entered + dateType + year + "    " + "xx " + strings.Repeat(" ", 17) + lang + " d"
+}
+
+// marcEntry is a book and its related names extracted from a MARC record
+type marcEntry struct {
+	book       repository.CreateBookParams
+	isbns      []string // Every valid ISBN-13 in the record, including the book's own
+	authors    []string
+	categories []string
+	dewey      string
+	lc         string
+}
+
+// marcWork finds the work an imported record is an edition of: that of an existing edition with another
+// of the record's ISBNs, or one with the same title and main author. Otherwise a new work is created.
+func marcWork(ctx context.Context, q *repository.Queries, entry marcEntry) (repository.Work, error) {
+	for _, isbn13 := range entry.isbns {
+		if isbn13 == entry.book.Isbn13 {
+			continue
+		}
+		book, err := q.GetBookByISBN(ctx, isbn13)
+		if err == nil {
+			work, err := q.GetWork(ctx, book.WorkID)
+			if err != nil {
+				return repository.Work{}, fmt.Errorf("failed to get work: %w", err)
+			}
+			return work, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return repository.Work{}, fmt.Errorf("failed to get book by ISBN: %w", err)
+		}
+	}
+
+	if len(entry.authors) > 0 {
+		work, err := q.GetWorkByTitleAndAuthor(ctx, repository.GetWorkByTitleAndAuthorParams{
+			Title:      entry.book.Title,
+			AuthorName: entry.authors[0],
+		})
+		if err == nil {
+			return work, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return repository.Work{}, fmt.Errorf("failed to find work: %w", err)
+		}
+	}
+
+	return resolveWork(ctx, q, entry.book.Title, "")
+}
+
+// parseMarcRecord maps MARC21 bibliographic fields back to book parameters
+func parseMarcRecord(record *marc.Record) marcEntry {
+	var entry marcEntry
+
+	// Use the first valid ISBN in either form; ISBN-10s are converted so dedupe works on isbn_13.
+	// The others, often of the same work in another binding, help find the work.
+	for _, field := range record.FieldsByTag("020") {
+		parsed, err := isbn.Parse(firstToken(field.Subfield('a')))
+		if err != nil {
+			continue
+		}
+		if entry.book.Isbn13 == "" {
+			entry.book.Isbn13 = parsed.ISBN13
+			entry.book.Isbn10 = util.StringToPgText(parsed.ISBN10)
+		}
+		entry.isbns = append(entry.isbns, parsed.ISBN13)
+	}
+
+	title := marc.TrimPunctuation(record.SubfieldValue("245", 'a'))
+	if subtitle := marc.TrimPunctuation(record.SubfieldValue("245", 'b')); subtitle != "" {
+		title += ": " + subtitle
+	}
+	entry.book.Title = title
+
+	// 050 splits the LC call number into class ($a) and item ($b) parts
+	entry.lc = strings.TrimSpace(record.SubfieldValue("050", 'a') + " " + record.SubfieldValue("050", 'b'))
+	entry.dewey = strings.TrimSpace(record.SubfieldValue("082", 'a'))
+
+	// RDA records use 264 for publication, older AACR2 records use 260
+	publisher := record.SubfieldValue("264", 'b')
+	date := record.SubfieldValue("264", 'c')
+	if publisher == "" && date == "" {
+		publisher = record.SubfieldValue("260", 'b')
+		date = record.SubfieldValue("260", 'c')
+	}
+	entry.book.Publisher = util.StringToPgText(marc.TrimPunctuation(publisher))
+	entry.book.PublishedDate = util.StringToPgText(marc.TrimPunctuation(date))
+
+	entry.book.PageCount = util.Int32ToPgInt(leadingNumber(record.SubfieldValue("300", 'a')))
+	entry.book.Description = util.StringToPgText(strings.TrimSpace(record.SubfieldValue("520", 'a')))
+	entry.book.ThumbnailUrl = util.StringToPgText(strings.TrimSpace(record.SubfieldValue("856", 'u')))
+
+	if fixed := record.ControlField("008"); len(fixed) >= 38 {
+		if lang := strings.TrimSpace(fixed[35:38]); len(lang) == 3 && lang != "und" {
+			entry.book.Language = util.StringToPgText("/languages/" + lang)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, tag := range []string{"100", "700"} {
+		for _, field := range record.FieldsByTag(tag) {
+			name := marc.TrimPunctuation(field.Subfield('a'))
+			if name != "" && !seen[name] {
+				seen[name] = true
+				entry.authors = append(entry.authors, name)
+			}
+		}
+	}
+
+	seen = map[string]bool{}
+	for _, field := range record.FieldsByTag("650") {
+		name := marc.TrimPunctuation(field.Subfield('a'))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			entry.categories = append(entry.categories, name)
+		}
+	}
+
+	return entry
+}
+
+// firstToken drops qualifiers such as "(pbk.)" from a 020 $a value
+func firstToken(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// leadingNumber returns the first run of digits in s, e.g. 310 for "xii, 310 p."
+func leadingNumber(s string) int32 {
+	start := strings.IndexFunc(s, func(r rune) bool { return r >= '0' && r <= '9' })
+	if start < 0 {
+		return 0
+	}
+	end := start
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	n, err := strconv.ParseInt(s[start:end], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(n)
+}
+
+func readMarcRecords(r io.Reader, format string) ([]*marc.Record, error) {
+	switch format {
+	case MarcFormatBinary:
+		reader := marc.NewReader(r)
+		var records []*marc.Record
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return records, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidMarcData, err)
+			}
+			records = append(records, record)
+		}
+	case MarcFormatXML:
+		records, err := marc.ReadXML(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMarcData, err)
+		}
+		return records, nil
+	default:
+		return nil, ErrUnsupportedMarcFormat
+	}
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+)
+
+// Merge entity types, as stored in merge_redirects
+const (
+	MergeEntityAuthor = "author"
+	MergeEntityBook   = "book"
+)
+
+// Reasons a pair of records is reported as a possible duplicate
+const (
+	DuplicateReasonOpenLibraryKey = "openlibrary_key"
+	DuplicateReasonNormalizedName = "normalized_name"
+	DuplicateReasonISBN           = "isbn"
+	DuplicateReasonSimilarName    = "similar_name"
+)
+
+// DefaultDuplicateSimilarity is the trigram similarity above which names are reported as possible duplicates
+const DefaultDuplicateSimilarity = 0.6
+
+var (
+	// ErrMergeSelf is returned when the survivor is also listed as a duplicate
+	ErrMergeSelf = errors.New("cannot merge a record into itself")
+	// ErrMergeConflict is returned when the merged records cannot be combined, e.g. a member has open holds on both
+	ErrMergeConflict = errors.New("records conflict and cannot be merged")
+)
+
+// MergedError is returned when looking up a record that was merged into another
+type MergedError struct {
+	EntityType string
+	NewID      uuid.UUID
+}
+
+func (e *MergedError) Error() string {
+	return fmt.Sprintf("%s was merged into %s", e.EntityType, e.NewID)
+}
+
+// MergeServiceImpl implements the MergeService interface
+type MergeServiceImpl struct {
+	db   *database.DB
+	repo *repository.Queries
+}
+
+// NewMergeService creates a new merge service
+func NewMergeService(db *database.DB, repo *repository.Queries) MergeService {
+	return &MergeServiceImpl{
+		db:   db,
+		repo: repo,
+	}
+}
+
+// AuthorDuplicates reports pairs of authors that may be the same person
+func (s *MergeServiceImpl) AuthorDuplicates(ctx context.Context, minSimilarity float64, limit, offset int32) ([]*DuplicateCandidate, error) {
+	ctx, span := tracer.Start(ctx, "MergeService.AuthorDuplicates")
+	defer span.End()
+
+	rows, err := s.repo.ListDuplicateAuthorCandidates(ctx, repository.ListDuplicateAuthorCandidatesParams{
+		MinSimilarity: minSimilarity,
+		Limit:         limit,
+		Offset:        offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list duplicate authors: %w", err)
+	}
+
+	candidates := make([]*DuplicateCandidate, len(rows))
+	for i, row := range rows {
+		candidate := &DuplicateCandidate{
+			ID:            row.AuthorID,
+			Name:          row.AuthorName,
+			DuplicateID:   row.DuplicateID,
+			DuplicateName: row.DuplicateName,
+			Similarity:    row.NameSimilarity,
+		}
+		if row.SameOpenlibraryKey {
+			candidate.Reasons = append(candidate.Reasons, DuplicateReasonOpenLibraryKey)
+		}
+		if row.SameNormalizedName {
+			candidate.Reasons = append(candidate.Reasons, DuplicateReasonNormalizedName)
+		}
+		if row.NameSimilarity >= minSimilarity {
+			candidate.Reasons = append(candidate.Reasons, DuplicateReasonSimilarName)
+		}
+		candidates[i] = candidate
+	}
+	return candidates, nil
+}
+
+// BookDuplicates reports pairs of books that may be the same edition
+func (s *MergeServiceImpl) BookDuplicates(ctx context.Context, minSimilarity float64, limit, offset int32) ([]*DuplicateCandidate, error) {
+	ctx, span := tracer.Start(ctx, "MergeService.BookDuplicates")
+	defer span.End()
+
+	rows, err := s.repo.ListDuplicateBookCandidates(ctx, repository.ListDuplicateBookCandidatesParams{
+		MinSimilarity: minSimilarity,
+		Limit:         limit,
+		Offset:        offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list duplicate books: %w", err)
+	}
+
+	candidates := make([]*DuplicateCandidate, len(rows))
+	for i, row := range rows {
+		candidate := &DuplicateCandidate{
+			ID:            row.BookID,
+			Name:          row.BookTitle,
+			DuplicateID:   row.DuplicateID,
+			DuplicateName: row.DuplicateTitle,
+			Similarity:    row.TitleSimilarity,
+		}
+		if row.SameIsbn {
+			candidate.Reasons = append(candidate.Reasons, DuplicateReasonISBN)
+		}
+		if row.TitleSimilarity >= minSimilarity {
+			candidate.Reasons = append(candidate.Reasons, DuplicateReasonSimilarName)
+		}
+		candidates[i] = candidate
+	}
+	return candidates, nil
+}
+
+// MergeAuthors folds the duplicate authors into the survivor. Their books are credited to the survivor,
+// blank survivor fields are filled from the duplicates, and their names are kept as alternate names.
+func (s *MergeServiceImpl) MergeAuthors(ctx context.Context, survivorID uuid.UUID, duplicateIDs []uuid.UUID) (*Author, error) {
+	ctx, span := tracer.Start(ctx, "MergeService.MergeAuthors")
+	defer span.End()
+
+	if err := checkMergeIDs(survivorID, duplicateIDs); err != nil {
+		return nil, err
+	}
+
+	var survivor *Author
+	err := s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		current, err := q.GetAuthor(ctx, survivorID)
+		if err != nil {
+			return fmt.Errorf("failed to get author: %w", err)
+		}
+		survivor, err = toAuthor(current)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range duplicateIDs {
+			dup, err := q.GetAuthor(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get author: %w", err)
+			}
+			duplicate, err := toAuthor(dup)
+			if err != nil {
+				return err
+			}
+
+			ids := repository.MoveAuthorBooksParams{SurvivorID: survivorID, MergedID: id}
+			if err := q.MoveAuthorBooks(ctx, ids); err != nil {
+				return fmt.Errorf("failed to move author books: %w", err)
+			}
+			// Removes the duplicate's remaining book_authors rows with it
+			if err := q.DeleteAuthor(ctx, id); err != nil {
+				return fmt.Errorf("failed to delete merged author: %w", err)
+			}
+			if err := redirect(ctx, q, MergeEntityAuthor, id, survivorID); err != nil {
+				return err
+			}
+
+			absorbAuthor(survivor, duplicate)
+		}
+
+		photos, alternateNames, links, err := encodeAuthorJSON(AuthorParams{
+			Photos:         survivor.Photos,
+			AlternateNames: survivor.AlternateNames,
+			Links:          survivor.Links,
+		})
+		if err != nil {
+			return err
+		}
+		updated, err := q.UpdateAuthor(ctx, repository.UpdateAuthorParams{
+			ID:             survivor.ID,
+			Name:           survivor.Name,
+			Bio:            survivor.Bio,
+			OpenlibraryKey: survivor.OpenlibraryKey,
+			Photos:         photos,
+			AlternateNames: alternateNames,
+			PersonalName:   survivor.PersonalName,
+			Links:          links,
+			BirthDate:      survivor.BirthDate,
+			DeathDate:      survivor.DeathDate,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update author: %w", err)
+		}
+		survivor, err = toAuthor(updated)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return survivor, nil
+}
+
+// MergeBooks folds the duplicate books into the survivor. Authors, categories, loans, reviews and holds
+// move to the survivor, and the duplicates' copies are added to its stock. A member who reviewed more
+// than one of the books keeps only their most recently updated review.
+func (s *MergeServiceImpl) MergeBooks(ctx context.Context, survivorID uuid.UUID, duplicateIDs []uuid.UUID) (*repository.Book, error) {
+	ctx, span := tracer.Start(ctx, "MergeService.MergeBooks")
+	defer span.End()
+
+	if err := checkMergeIDs(survivorID, duplicateIDs); err != nil {
+		return nil, err
+	}
+
+	var survivor repository.Book
+	err := s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		var err error
+		survivor, err = q.GetBook(ctx, survivorID)
+		if err != nil {
+			return fmt.Errorf("failed to get book: %w", err)
+		}
+
+		for _, id := range duplicateIDs {
+			duplicate, err := q.GetBook(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get book: %w", err)
+			}
+			if err := moveBook(ctx, q, survivor, duplicate); err != nil {
+				return err
+			}
+			survivor.TotalCopies += duplicate.TotalCopies
+			survivor.AvailableCopies += duplicate.AvailableCopies
+		}
+
+		survivor, err = q.UpdateBookCopies(ctx, repository.UpdateBookCopiesParams{
+			ID:              survivor.ID,
+			TotalCopies:     survivor.TotalCopies,
+			AvailableCopies: survivor.AvailableCopies,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update book copies: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrMergeConflict
+		}
+		return nil, err
+	}
+	return &survivor, nil
+}
+
+// moveBook re-points everything referencing the duplicate at the survivor, then deletes the duplicate.
+// If that leaves the duplicate's work without editions, the work is folded into the survivor's.
+func moveBook(ctx context.Context, q *repository.Queries, survivor, duplicate repository.Book) error {
+	if err := q.MoveBookAuthors(ctx, repository.MoveBookAuthorsParams{SurvivorID: survivor.ID, MergedID: duplicate.ID}); err != nil {
+		return fmt.Errorf("failed to move book authors: %w", err)
+	}
+	if err := q.MoveBookCategories(ctx, repository.MoveBookCategoriesParams{SurvivorID: survivor.ID, MergedID: duplicate.ID}); err != nil {
+		return fmt.Errorf("failed to move book categories: %w", err)
+	}
+	if err := q.MoveBookLoans(ctx, repository.MoveBookLoansParams{SurvivorID: survivor.ID, MergedID: duplicate.ID}); err != nil {
+		return fmt.Errorf("failed to move loans: %w", err)
+	}
+	if err := q.DeleteSupersededBookReviews(ctx, repository.DeleteSupersededBookReviewsParams{SurvivorID: survivor.ID, MergedID: duplicate.ID}); err != nil {
+		return fmt.Errorf("failed to resolve duplicate reviews: %w", err)
+	}
+	if err := q.MoveBookReviews(ctx, repository.MoveBookReviewsParams{SurvivorID: survivor.ID, MergedID: duplicate.ID}); err != nil {
+		return fmt.Errorf("failed to move reviews: %w", err)
+	}
+	if err := q.MoveBookReadingListItems(ctx, repository.MoveBookReadingListItemsParams{SurvivorID: survivor.ID, MergedID: duplicate.ID}); err != nil {
+		return fmt.Errorf("failed to move reading list items: %w", err)
+	}
+	err := q.MoveBookHolds(ctx, repository.MoveBookHoldsParams{
+		MergedID:       duplicate.ID,
+		SurvivorID:     survivor.ID,
+		SurvivorWorkID: survivor.WorkID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to move holds: %w", err)
+	}
+
+	if err := q.DeleteBook(ctx, duplicate.ID); err != nil {
+		return fmt.Errorf("failed to delete merged book: %w", err)
+	}
+	if err := q.RefreshBookRating(ctx, survivor.ID); err != nil {
+		return fmt.Errorf("failed to update book rating: %w", err)
+	}
+	if err := redirect(ctx, q, MergeEntityBook, duplicate.ID, survivor.ID); err != nil {
+		return err
+	}
+
+	if duplicate.WorkID == survivor.WorkID {
+		return nil
+	}
+	editions, err := q.CountWorkEditions(ctx, duplicate.WorkID)
+	if err != nil {
+		return fmt.Errorf("failed to count editions: %w", err)
+	}
+	if editions > 0 {
+		return nil
+	}
+	if err := q.MoveWorkHolds(ctx, repository.MoveWorkHoldsParams{SurvivorID: survivor.WorkID, MergedID: duplicate.WorkID}); err != nil {
+		return fmt.Errorf("failed to move work holds: %w", err)
+	}
+	if err := q.MoveWorkSeries(ctx, repository.MoveWorkSeriesParams{SurvivorID: survivor.WorkID, MergedID: duplicate.WorkID}); err != nil {
+		return fmt.Errorf("failed to move work series: %w", err)
+	}
+	if err := q.DeleteWork(ctx, duplicate.WorkID); err != nil {
+		return fmt.Errorf("failed to delete merged work: %w", err)
+	}
+	return nil
+}
+
+// redirect records that oldID now lives at newID, updating earlier redirects to oldID as well
+func redirect(ctx context.Context, q *repository.Queries, entityType string, oldID, newID uuid.UUID) error {
+	err := q.RepointMergeRedirects(ctx, repository.RepointMergeRedirectsParams{
+		SurvivorID: newID,
+		EntityType: entityType,
+		MergedID:   oldID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update merge redirects: %w", err)
+	}
+
+	err = q.CreateMergeRedirect(ctx, repository.CreateMergeRedirectParams{
+		EntityType: entityType,
+		OldID:      oldID,
+		NewID:      newID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create merge redirect: %w", err)
+	}
+	return nil
+}
+
+// mergedInto turns a not-found error for a merged-away record into a MergedError pointing at its survivor.
+// Any other error is returned unchanged.
+func mergedInto(ctx context.Context, q *repository.Queries, entityType string, id uuid.UUID, err error) error {
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	newID, lookupErr := q.GetMergeRedirect(ctx, repository.GetMergeRedirectParams{
+		EntityType: entityType,
+		OldID:      id,
+	})
+	if lookupErr != nil {
+		return err
+	}
+	return &MergedError{EntityType: entityType, NewID: newID}
+}
+
+// absorbAuthor fills the survivor's blank fields from the duplicate and keeps the duplicate's names
+func absorbAuthor(survivor, duplicate *Author) {
+	if !survivor.Bio.Valid {
+		survivor.Bio = duplicate.Bio
+	}
+	if !survivor.OpenlibraryKey.Valid {
+		survivor.OpenlibraryKey = duplicate.OpenlibraryKey
+	}
+	if !survivor.PersonalName.Valid {
+		survivor.PersonalName = duplicate.PersonalName
+	}
+	if !survivor.BirthDate.Valid {
+		survivor.BirthDate = duplicate.BirthDate
+	}
+	if !survivor.DeathDate.Valid {
+		survivor.DeathDate = duplicate.DeathDate
+	}
+	if len(survivor.Photos) == 0 {
+		survivor.Photos = duplicate.Photos
+	}
+	if len(survivor.Links) == 0 {
+		survivor.Links = duplicate.Links
+	}
+
+	names := append([]string{duplicate.Name}, duplicate.AlternateNames...)
+	for _, name := range names {
+		if name == survivor.Name || slices.Contains(survivor.AlternateNames, name) {
+			continue
+		}
+		survivor.AlternateNames = append(survivor.AlternateNames, name)
+	}
+}
+
+func checkMergeIDs(survivorID uuid.UUID, duplicateIDs []uuid.UUID) error {
+	for _, id := range duplicateIDs {
+		if id == survivorID {
+			return ErrMergeSelf
+		}
+	}
+	return nil
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vasujain275/bookbridge-api/internal/mail"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+)
+
+// Notification kinds, which are also the names of their email templates
+const (
+	NotificationWelcome     = "welcome"
+	NotificationDueReminder = "due_reminder"
+	NotificationOverdue     = "overdue"
+	NotificationHoldReady   = "hold_ready"
+)
+
+// Notification delivery statuses
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed"
+)
+
+// Notification delivery tuning. A failed delivery is retried after notificationBackoff, doubling with
+// every attempt up to notificationMaxBackoff. Notifications being sent are leased for notificationLease
+// so that a second dispatcher does not send them too.
+const (
+	notificationBatchSize  = 50
+	notificationLease      = 5 * time.Minute
+	notificationBackoff    = time.Minute
+	notificationMaxBackoff = 6 * time.Hour
+)
+
+var (
+	// ErrNotificationStatus is returned when listing notifications by a status other than pending, sent and failed
+	ErrNotificationStatus = errors.New("status must be pending, sent or failed")
+	// ErrNotificationNotFailed is returned when retrying a notification that has not failed
+	ErrNotificationNotFailed = errors.New("only failed notifications can be retried")
+)
+
+// NotificationPolicy configures which emails are sent and how they are delivered
+type NotificationPolicy struct {
+	// From is the sender address of every email
+	From string
+	// DueReminderDays is how many days before the due date members are reminded, unless they choose otherwise
+	DueReminderDays int
+	// MaxAttempts is how many times delivery is tried before a notification is marked failed
+	MaxAttempts int
+}
+
+// NotificationServiceImpl implements the NotificationService interface
+type NotificationServiceImpl struct {
+	repo   *repository.Queries
+	sender mail.Sender
+	policy NotificationPolicy
+	wake   chan struct{}
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(repo *repository.Queries, sender mail.Sender, policy NotificationPolicy) NotificationService {
+	return &NotificationServiceImpl{
+		repo:   repo,
+		sender: sender,
+		policy: policy,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Publish queues the email for an event: new members are sent a welcome email
+func (s *NotificationServiceImpl) Publish(ctx context.Context, event Event) error {
+	ctx, span := tracer.Start(ctx, "NotificationService.Publish")
+	defer span.End()
+
+	if event.Type != EventUserCreated {
+		return nil
+	}
+
+	var data UserEventData
+	if err := decodeEventData(event, &data); err != nil {
+		return err
+	}
+	user, err := s.repo.GetUser(ctx, data.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	return s.welcome(ctx, &user)
+}
+
+// welcome queues the welcome email for a new member
+func (s *NotificationServiceImpl) welcome(ctx context.Context, user *repository.User) error {
+	return s.enqueue(ctx, user.ID, user.Email, NotificationWelcome, "welcome:"+user.ID.String(), map[string]any{
+		"FirstName": user.FirstName,
+		"Username":  user.Username,
+	})
+}
+
+// Preferences gets a member's notification preferences. Members who never chose get every email.
+func (s *NotificationServiceImpl) Preferences(ctx context.Context, userID uuid.UUID) (*repository.NotificationPreference, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.Preferences")
+	defer span.End()
+
+	preferences, err := s.repo.GetNotificationPreferences(ctx, userID)
+	if err == nil {
+		return &preferences, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &repository.NotificationPreference{
+		UserID:         userID,
+		DueReminders:   true,
+		OverdueNotices: true,
+		HoldReady:      true,
+	}, nil
+}
+
+// SetPreferences saves a member's notification preferences
+func (s *NotificationServiceImpl) SetPreferences(ctx context.Context, params repository.UpsertNotificationPreferencesParams) (*repository.NotificationPreference, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.SetPreferences")
+	defer span.End()
+
+	preferences, err := s.repo.UpsertNotificationPreferences(ctx, params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, fmt.Errorf("failed to save notification preferences: user not found: %w", pgx.ErrNoRows)
+		}
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return &preferences, nil
+}
+
+// List gets the notification log, newest first, optionally only those with a delivery status
+func (s *NotificationServiceImpl) List(ctx context.Context, status string, limit, offset int32) ([]*repository.Notification, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.List")
+	defer span.End()
+
+	switch status {
+	case "", NotificationPending, NotificationSent, NotificationFailed:
+	default:
+		return nil, ErrNotificationStatus
+	}
+
+	notifications, err := s.repo.ListNotifications(ctx, repository.ListNotificationsParams{
+		Status: status,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return notificationPtrs(notifications), nil
+}
+
+// ListByUserID gets the notifications sent to a member, newest first
+func (s *NotificationServiceImpl) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]*repository.Notification, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.ListByUserID")
+	defer span.End()
+
+	notifications, err := s.repo.ListNotificationsByUserID(ctx, repository.ListNotificationsByUserIDParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return notificationPtrs(notifications), nil
+}
+
+// Retry queues a failed notification for one more delivery attempt straight away
+func (s *NotificationServiceImpl) Retry(ctx context.Context, id uuid.UUID) (*repository.Notification, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.Retry")
+	defer span.End()
+
+	notification, err := s.repo.GetNotification(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+	if notification.Status != NotificationFailed {
+		return nil, ErrNotificationNotFailed
+	}
+
+	notification, err = s.repo.RetryNotification(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotificationNotFailed
+		}
+		return nil, fmt.Errorf("failed to retry notification: %w", err)
+	}
+	s.notify()
+	return &notification, nil
+}
+
+// Run queues due-date reminders, overdue notices and hold-ready emails and delivers pending
+// notifications, straight away and then every interval until ctx is cancelled. Newly queued
+// notifications are delivered without waiting for the next interval.
+func (s *NotificationServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	scan := true
+	for {
+		if scan {
+			if err := s.scan(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "Failed to queue notifications", "error", err)
+			}
+		}
+		if err := s.deliver(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Failed to deliver notifications", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			scan = true
+		case <-s.wake:
+			scan = false
+		}
+	}
+}
+
+// scan queues an email for every loan and hold that needs one and has not had it yet
+func (s *NotificationServiceImpl) scan(ctx context.Context) error {
+	dueSoon, err := s.repo.ListDueReminderCandidates(ctx, int32(s.policy.DueReminderDays))
+	if err != nil {
+		return fmt.Errorf("failed to list loans due soon: %w", err)
+	}
+	for _, loan := range dueSoon {
+		err := s.enqueue(ctx, loan.UserID, loan.Email, NotificationDueReminder, loan.DedupeKey, map[string]any{
+			"FirstName": loan.FirstName,
+			"BookTitle": loan.BookTitle,
+			"DueDate":   formatDueDate(loan.DueDate.Time),
+			"Days":      loan.Days,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	overdue, err := s.repo.ListOverdueCandidates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list overdue loans: %w", err)
+	}
+	for _, loan := range overdue {
+		err := s.enqueue(ctx, loan.UserID, loan.Email, NotificationOverdue, loan.DedupeKey, map[string]any{
+			"FirstName": loan.FirstName,
+			"BookTitle": loan.BookTitle,
+			"DueDate":   formatDueDate(loan.DueDate.Time),
+			"Days":      loan.Days,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	ready, err := s.repo.ListHoldReadyCandidates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list ready holds: %w", err)
+	}
+	for _, hold := range ready {
+		err := s.enqueue(ctx, hold.UserID, hold.Email, NotificationHoldReady, hold.DedupeKey, map[string]any{
+			"FirstName":  hold.FirstName,
+			"BookTitle":  hold.BookTitle,
+			"CallNumber": hold.CallNumber.String,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver sends pending notifications whose next attempt is due, batch by batch
+func (s *NotificationServiceImpl) deliver(ctx context.Context) error {
+	for {
+		notifications, err := s.repo.ClaimPendingNotifications(ctx, repository.ClaimPendingNotificationsParams{
+			LeaseSeconds: int32(notificationLease / time.Second),
+			Limit:        notificationBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to claim notifications: %w", err)
+		}
+
+		for _, notification := range notifications {
+			if err := s.send(ctx, notification); err != nil {
+				return err
+			}
+		}
+		if len(notifications) < notificationBatchSize {
+			return nil
+		}
+	}
+}
+
+// send delivers one notification and records the outcome
+func (s *NotificationServiceImpl) send(ctx context.Context, notification repository.Notification) error {
+	sendErr := s.sender.Send(ctx, mail.Message{
+		From:    s.policy.From,
+		To:      notification.Recipient,
+		Subject: notification.Subject,
+		Text:    notification.BodyText,
+		HTML:    notification.BodyHtml,
+	})
+	if sendErr == nil {
+		if err := s.repo.MarkNotificationSent(ctx, notification.ID); err != nil {
+			return fmt.Errorf("failed to mark notification sent: %w", err)
+		}
+		return nil
+	}
+	if ctx.Err() != nil {
+		// Shutting down; the lease runs out and the notification is tried again
+		return ctx.Err()
+	}
+
+	backoff := notificationMaxBackoff
+	if notification.Attempts < 16 {
+		backoff = min(notificationBackoff<<notification.Attempts, notificationMaxBackoff)
+	}
+	failed, err := s.repo.MarkNotificationFailed(ctx, repository.MarkNotificationFailedParams{
+		MaxAttempts:    int32(s.policy.MaxAttempts),
+		LastError:      pgtype.Text{String: sendErr.Error(), Valid: true},
+		BackoffSeconds: int32(backoff / time.Second),
+		ID:             notification.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record notification failure: %w", err)
+	}
+	if failed.Status == NotificationFailed {
+		slog.WarnContext(ctx, "Giving up on notification",
+			"kind", failed.Kind,
+			"notification_id", failed.ID,
+			"recipient", failed.Recipient,
+			"attempts", failed.Attempts,
+			"error", sendErr,
+		)
+	}
+	return nil
+}
+
+// enqueue renders an email and queues it for delivery. An email already queued under the same
+// dedupe key is not queued again.
+func (s *NotificationServiceImpl) enqueue(ctx context.Context, userID uuid.UUID, recipient, kind, dedupeKey string, data map[string]any) error {
+	rendered, err := mail.Render(kind, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %s email: %w", kind, err)
+	}
+
+	_, err = s.repo.CreateNotification(ctx, repository.CreateNotificationParams{
+		UserID:    userID,
+		Kind:      kind,
+		DedupeKey: dedupeKey,
+		Recipient: recipient,
+		Subject:   rendered.Subject,
+		BodyText:  rendered.Text,
+		BodyHtml:  rendered.HTML,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to queue %s notification: %w", kind, err)
+	}
+	s.notify()
+	return nil
+}
+
+// notify wakes the dispatcher without blocking when it is already due to run
+func (s *NotificationServiceImpl) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func notificationPtrs(notifications []repository.Notification) []*repository.Notification {
+	ptrs := make([]*repository.Notification, len(notifications))
+	for i := range notifications {
+		ptrs[i] = &notifications[i]
+	}
+	return ptrs
+}
+
+func formatDueDate(t time.Time) string {
+	return t.Format("Monday, 2 January 2006")
+}
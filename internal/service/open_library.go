@@ -1,34 +1,84 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/vasujain275/bookbridge-api/internal/metrics"
 	"github.com/vasujain275/bookbridge-api/internal/types"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-type OpenLibraryServiceImpl struct{}
+type OpenLibraryServiceImpl struct {
+	client *http.Client
+}
 
 // NewOpenLibraryService creates a new OpenLibrary service
 func NewOpenLibraryService() OpenLibraryService {
-	return &OpenLibraryServiceImpl{}
+	return &OpenLibraryServiceImpl{
+		client: &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
+	}
 }
 
 // GetByISBN gets a book by ISBN from OpenLibrary
-func (s *OpenLibraryServiceImpl) GetByISBN(isbn string) (*types.OpenLibraryBook, error) {
+func (s *OpenLibraryServiceImpl) GetByISBN(ctx context.Context, isbn string) (book *types.OpenLibraryBook, err error) {
+	ctx, span := tracer.Start(ctx, "OpenLibraryService.GetByISBN")
+	defer span.End()
+
+	start := time.Now()
+	defer func() {
+		metrics.ObserveOpenLibraryCall("isbn", time.Since(start), err)
+	}()
+
 	url := fmt.Sprintf("https://openlibrary.org/isbn/%s.json", isbn)
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenLibrary request: %w", err)
+	}
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch book from OpenLibrary: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OpenLibrary returned status %d for ISBN %s", resp.StatusCode, isbn)
+	}
+
 	// Encode the response into the OpenLibraryBook struct
-	var book types.OpenLibraryBook
-	if err := json.NewDecoder(resp.Body).Decode(&book); err != nil {
+	book = &types.OpenLibraryBook{}
+	if err := json.NewDecoder(resp.Body).Decode(book); err != nil {
 		return nil, fmt.Errorf("failed to decode OpenLibrary response: %w", err)
 	}
 
-	return &book, nil
+	return book, nil
+}
+
+// Ping checks that OpenLibrary is reachable and answering
+func (s *OpenLibraryServiceImpl) Ping(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "OpenLibraryService.Ping")
+	defer span.End()
+
+	start := time.Now()
+	defer func() {
+		metrics.ObserveOpenLibraryCall("ping", time.Since(start), err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, "https://openlibrary.org/", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create OpenLibrary request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach OpenLibrary: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("OpenLibrary returned status %d", resp.StatusCode)
+	}
+	return nil
 }
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+)
+
+// outboxChannel is the Postgres notification channel recordEvent signals on commit
+const outboxChannel = "outbox"
+
+// Outbox dispatch tuning. Events are retried until every subscriber accepts them, after outboxBackoff
+// doubling up to outboxMaxBackoff. Dispatched events are kept for outboxRetention.
+const (
+	outboxBatchSize  = 100
+	outboxLease      = time.Minute
+	outboxBackoff    = 5 * time.Second
+	outboxMaxBackoff = 10 * time.Minute
+	outboxRetention  = 7 * 24 * time.Hour
+	outboxCleanup    = time.Hour
+)
+
+// OutboxServiceImpl implements the OutboxService interface
+type OutboxServiceImpl struct {
+	db   *database.DB
+	repo *repository.Queries
+
+	mu          sync.RWMutex
+	subscribers []EventPublisher
+}
+
+// NewOutboxService creates a new outbox service relaying events to subscribers
+func NewOutboxService(db *database.DB, repo *repository.Queries, subscribers ...EventPublisher) OutboxService {
+	return &OutboxServiceImpl{
+		db:          db,
+		repo:        repo,
+		subscribers: subscribers,
+	}
+}
+
+// Subscribe adds a subscriber to every event dispatched from now on
+func (s *OutboxServiceImpl) Subscribe(subscriber EventPublisher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, subscriber)
+}
+
+// Run relays recorded events to the subscribers until ctx is cancelled. Events are relayed as soon as
+// the transaction recording them commits, and at least every interval for those being retried.
+// Delivery is at least once: an event is offered to every subscriber again if any of them fails, so
+// subscribers must tolerate seeing an event ID twice.
+func (s *OutboxServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	var conn *pgxpool.Conn
+	defer func() {
+		if conn != nil {
+			conn.Release()
+		}
+	}()
+
+	var cleaned time.Time
+	for {
+		if err := s.dispatch(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Failed to dispatch outbox events", "error", err)
+		}
+		if time.Since(cleaned) > outboxCleanup {
+			cutoff := pgtype.Timestamp{Time: time.Now().Add(-outboxRetention), Valid: true}
+			if err := s.repo.DeleteDispatchedOutboxEvents(ctx, cutoff); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "Failed to delete dispatched outbox events", "error", err)
+			}
+			cleaned = time.Now()
+		}
+
+		if conn == nil {
+			var err error
+			if conn, err = listenOutbox(ctx, s.db); err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "Failed to listen for outbox events, polling instead", "error", err)
+			}
+		}
+		if err := waitOutbox(ctx, conn, interval); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.WarnContext(ctx, "Lost outbox notification connection", "error", err)
+			conn.Release()
+			conn = nil
+		}
+	}
+}
+
+// listenOutbox takes a connection from the pool to receive outbox notifications on
+func listenOutbox(ctx context.Context, db *database.DB) (*pgxpool.Conn, error) {
+	conn, err := db.Pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+outboxChannel); err != nil {
+		conn.Release()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// waitOutbox blocks until an event is recorded, interval passes or ctx is cancelled. It returns an
+// error only when the notification connection fails or ctx is cancelled.
+func waitOutbox(ctx context.Context, conn *pgxpool.Conn, interval time.Duration) error {
+	if conn == nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+			return nil
+		}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, interval)
+	defer cancel()
+	_, err := conn.Conn().WaitForNotification(waitCtx)
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case waitCtx.Err() != nil:
+		// Interval passed without a notification
+		return nil
+	default:
+		return err
+	}
+}
+
+// dispatch relays due events to the subscribers, batch by batch
+func (s *OutboxServiceImpl) dispatch(ctx context.Context) error {
+	for {
+		rows, err := s.repo.ClaimOutboxEvents(ctx, repository.ClaimOutboxEventsParams{
+			LeaseSeconds: int32(outboxLease / time.Second),
+			Limit:        outboxBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to claim outbox events: %w", err)
+		}
+
+		for _, row := range rows {
+			if err := s.relay(ctx, row); err != nil {
+				return err
+			}
+		}
+		if len(rows) < outboxBatchSize {
+			return nil
+		}
+	}
+}
+
+// relay offers one event to every subscriber and records the outcome
+func (s *OutboxServiceImpl) relay(ctx context.Context, row repository.Outbox) error {
+	event, relayErr := decodeOutboxEvent(row)
+	if relayErr == nil {
+		relayErr = s.publish(ctx, event)
+	}
+
+	if relayErr == nil {
+		if err := s.repo.MarkOutboxEventDispatched(ctx, row.ID); err != nil {
+			return fmt.Errorf("failed to mark outbox event dispatched: %w", err)
+		}
+		return nil
+	}
+	if ctx.Err() != nil {
+		// Shutting down; the lease runs out and the event is relayed again
+		return ctx.Err()
+	}
+
+	backoff := outboxMaxBackoff
+	if row.Attempts < 16 {
+		backoff = min(outboxBackoff<<row.Attempts, outboxMaxBackoff)
+	}
+	err := s.repo.MarkOutboxEventFailed(ctx, repository.MarkOutboxEventFailedParams{
+		LastError:      pgtype.Text{String: relayErr.Error(), Valid: true},
+		BackoffSeconds: int32(backoff / time.Second),
+		ID:             row.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record outbox failure: %w", err)
+	}
+	slog.WarnContext(ctx, "Failed to relay outbox event",
+		"event_type", row.EventType,
+		"event_id", row.ID,
+		"attempt", row.Attempts+1,
+		"error", relayErr,
+	)
+	return nil
+}
+
+// decodeOutboxEvent decodes a recorded event, keeping its data as raw JSON
+func decodeOutboxEvent(row repository.Outbox) (Event, error) {
+	var event struct {
+		Event
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(row.Payload, &event); err != nil {
+		return Event{}, fmt.Errorf("failed to decode %s event: %w", row.EventType, err)
+	}
+	event.Event.Data = event.Data
+	return event.Event, nil
+}
+
+// publish offers an event to every subscriber, even if some fail
+func (s *OutboxServiceImpl) publish(ctx context.Context, event Event) error {
+	s.mu.RLock()
+	subscribers := s.subscribers
+	s.mu.RUnlock()
+
+	var failures []string
+	for _, subscriber := range subscribers {
+		if err := subscriber.Publish(ctx, event); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+)
+
+// OverdueServiceImpl implements the OverdueService interface
+type OverdueServiceImpl struct {
+	db   *database.DB
+	repo *repository.Queries
+}
+
+// NewOverdueService creates a new overdue loan service
+func NewOverdueService(db *database.DB, repo *repository.Queries) OverdueService {
+	return &OverdueServiceImpl{
+		db:   db,
+		repo: repo,
+	}
+}
+
+// Run marks loans past their due date overdue, straight away and then every interval until ctx is cancelled
+func (s *OverdueServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.markOverdue(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.ErrorContext(ctx, "Failed to mark overdue loans", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// markOverdue marks loans past their due date overdue and records a loan.overdue event for each
+func (s *OverdueServiceImpl) markOverdue(ctx context.Context) error {
+	return s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		loans, err := q.MarkOverdueLoans(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to mark overdue loans: %w", err)
+		}
+		for _, loan := range loans {
+			// Once per loan and due date, so a loan renewed and overdue again is reported again
+			dedupeKey := fmt.Sprintf("%s:%s:%s", EventLoanOverdue, loan.ID, loan.DueDate.Time.Format(time.DateOnly))
+			if err := recordEvent(ctx, q, EventLoanOverdue, dedupeKey, &loan); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
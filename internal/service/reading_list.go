@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// Reading list kinds. A member has at most one list of each kind other than custom.
+const (
+	ReadingListToRead    = "to_read"
+	ReadingListFavorites = "favorites"
+	ReadingListCustom    = "custom"
+)
+
+var (
+	// ErrReadingListExists is returned when a member creates a second "to read" or "favorites" list
+	ErrReadingListExists = errors.New("user already has a reading list of this kind")
+	// ErrReadingListForbidden is returned when someone other than the list's owner or an admin changes a list
+	ErrReadingListForbidden = errors.New("only the list's owner or an admin may change this reading list")
+	// ErrReadingListItemExists is returned when adding a book that is already on the list
+	ErrReadingListItemExists = errors.New("book is already on this reading list")
+	// ErrReadingListOrder is returned when a new order does not name every item on the list exactly once
+	ErrReadingListOrder = errors.New("order must list every item on the reading list exactly once")
+)
+
+// ReadingListParams describes a reading list's name, description and visibility
+type ReadingListParams struct {
+	Name        string
+	Description string
+	Public      bool
+}
+
+// ReadingListServiceImpl implements the ReadingListService interface
+type ReadingListServiceImpl struct {
+	db    *database.DB
+	repo  *repository.Queries
+	holds HoldService
+}
+
+// NewReadingListService creates a new reading list service. Holds for unavailable items are placed through holds.
+func NewReadingListService(db *database.DB, repo *repository.Queries, holds HoldService) ReadingListService {
+	return &ReadingListServiceImpl{
+		db:    db,
+		repo:  repo,
+		holds: holds,
+	}
+}
+
+// GetByID gets a reading list with its items in order. Private lists are only visible to their owner and admins.
+func (s *ReadingListServiceImpl) GetByID(ctx context.Context, actor *repository.User, id uuid.UUID) (*ReadingListDetails, error) {
+	ctx, span := tracer.Start(ctx, "ReadingListService.GetByID")
+	defer span.End()
+
+	list, err := s.repo.GetReadingList(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading list: %w", err)
+	}
+	if !list.IsPublic && !ownsReadingList(actor, list) {
+		return nil, fmt.Errorf("failed to get reading list: %w", pgx.ErrNoRows)
+	}
+	return s.details(ctx, list)
+}
+
+// GetByShareToken gets a public reading list from its share link
+func (s *ReadingListServiceImpl) GetByShareToken(ctx context.Context, token string) (*ReadingListDetails, error) {
+	ctx, span := tracer.Start(ctx, "ReadingListService.GetByShareToken")
+	defer span.End()
+
+	list, err := s.repo.GetReadingListByShareToken(ctx, util.StringToPgText(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading list: %w", err)
+	}
+	return s.details(ctx, list)
+}
+
+// ListByUserID gets a member's reading lists, "to read" and "favorites" first. Only the member
+// and admins see private lists.
+func (s *ReadingListServiceImpl) ListByUserID(ctx context.Context, actor *repository.User, userID uuid.UUID, limit, offset int32) ([]*repository.ReadingList, error) {
+	ctx, span := tracer.Start(ctx, "ReadingListService.ListByUserID")
+	defer span.End()
+
+	lists, err := s.repo.ListReadingListsByUserID(ctx, repository.ListReadingListsByUserIDParams{
+		UserID:         userID,
+		IncludePrivate: actor != nil && (actor.ID == userID || actor.Role == RoleAdmin),
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reading lists: %w", err)
+	}
+	listPtrs := make([]*repository.ReadingList, len(lists))
+	for i := range lists {
+		listPtrs[i] = &lists[i]
+	}
+	return listPtrs, nil
+}
+
+// Create creates a reading list for the actor. Public lists get a share link.
+func (s *ReadingListServiceImpl) Create(ctx context.Context, actor *repository.User, kind string, params ReadingListParams) (*repository.ReadingList, error) {
+	ctx, span := tracer.Start(ctx, "ReadingListService.Create")
+	defer span.End()
+
+	if kind == "" {
+		kind = ReadingListCustom
+	}
+	shareToken, err := readingListShareToken(params.Public, "")
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.repo.CreateReadingList(ctx, repository.CreateReadingListParams{
+		UserID:      actor.ID,
+		Name:        params.Name,
+		Description: util.StringToPgText(params.Description),
+		Kind:        kind,
+		IsPublic:    params.Public,
+		ShareToken:  shareToken,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrReadingListExists
+		}
+		return nil, fmt.Errorf("failed to create reading list: %w", err)
+	}
+	return &list, nil
+}
+
+// Update renames a reading list and changes its visibility. Making a list private revokes its share link;
+// making it public again issues a new one.
+func (s *ReadingListServiceImpl) Update(ctx context.Context, actor *repository.User, id uuid.UUID, params ReadingListParams) (*repository.ReadingList, error) {
+	ctx, span := tracer.Start(ctx, "ReadingListService.Update")
+	defer span.End()
+
+	existing, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	shareToken, err := readingListShareToken(params.Public, util.PgTextToString(existing.ShareToken))
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.repo.UpdateReadingList(ctx, repository.UpdateReadingListParams{
+		ID:          id,
+		Name:        params.Name,
+		Description: util.StringToPgText(params.Description),
+		IsPublic:    params.Public,
+		ShareToken:  shareToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reading list: %w", err)
+	}
+	return &list, nil
+}
+
+// Delete deletes a reading list and its items
+func (s *ReadingListServiceImpl) Delete(ctx context.Context, actor *repository.User, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "ReadingListService.Delete")
+	defer span.End()
+
+	if _, err := s.authorize(ctx, actor, id); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteReadingList(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete reading list: %w", err)
+	}
+	return nil
+}
+
+// AddItem adds a book to the end of a reading list with an optional note
+func (s *ReadingListServiceImpl) AddItem(ctx context.Context, actor *repository.User, listID, bookID uuid.UUID, note string) (*repository.ReadingListItem, error) {
+	ctx, span := tracer.Start(ctx, "ReadingListService.AddItem")
+	defer span.End()
+
+	if _, err := s.authorize(ctx, actor, listID); err != nil {
+		return nil, err
+	}
+
+	var item repository.ReadingListItem
+	err := s.withList(ctx, listID, func(q *repository.Queries) error {
+		var err error
+		item, err = q.AddReadingListItem(ctx, repository.AddReadingListItemParams{
+			ListID: listID,
+			BookID: bookID,
+			Note:   util.StringToPgText(note),
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				switch pgErr.Code {
+				case "23505":
+					return ErrReadingListItemExists
+				case "23503":
+					return fmt.Errorf("failed to add reading list item: book not found: %w", pgx.ErrNoRows)
+				}
+			}
+			return fmt.Errorf("failed to add reading list item: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// UpdateItem replaces the note on a reading list item
+func (s *ReadingListServiceImpl) UpdateItem(ctx context.Context, actor *repository.User, listID, itemID uuid.UUID, note string) (*repository.ReadingListItem, error) {
+	ctx, span := tracer.Start(ctx, "ReadingListService.UpdateItem")
+	defer span.End()
+
+	if _, err := s.authorize(ctx, actor, listID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.repo.UpdateReadingListItemNote(ctx, repository.UpdateReadingListItemNoteParams{
+		ID:     itemID,
+		ListID: listID,
+		Note:   util.StringToPgText(note),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reading list item: %w", err)
+	}
+	return &item, nil
+}
+
+// RemoveItem removes a book from a reading list, moving the items after it up one place
+func (s *ReadingListServiceImpl) RemoveItem(ctx context.Context, actor *repository.User, listID, itemID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "ReadingListService.RemoveItem")
+	defer span.End()
+
+	if _, err := s.authorize(ctx, actor, listID); err != nil {
+		return err
+	}
+
+	return s.withList(ctx, listID, func(q *repository.Queries) error {
+		item, err := q.DeleteReadingListItem(ctx, repository.DeleteReadingListItemParams{
+			ID:     itemID,
+			ListID: listID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove reading list item: %w", err)
+		}
+		err = q.CloseReadingListGap(ctx, repository.CloseReadingListGapParams{
+			ListID:   listID,
+			Position: item.Position,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to renumber reading list items: %w", err)
+		}
+		return nil
+	})
+}
+
+// Reorder puts a reading list's items in the given order, which must name every item exactly once
+func (s *ReadingListServiceImpl) Reorder(ctx context.Context, actor *repository.User, listID uuid.UUID, itemIDs []uuid.UUID) (*ReadingListDetails, error) {
+	ctx, span := tracer.Start(ctx, "ReadingListService.Reorder")
+	defer span.End()
+
+	list, err := s.authorize(ctx, actor, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withList(ctx, listID, func(q *repository.Queries) error {
+		current, err := q.ListReadingListItemIDs(ctx, listID)
+		if err != nil {
+			return fmt.Errorf("failed to list reading list items: %w", err)
+		}
+		if !sameItems(current, itemIDs) {
+			return ErrReadingListOrder
+		}
+		err = q.ReorderReadingListItems(ctx, repository.ReorderReadingListItemsParams{
+			ItemIds: itemIDs,
+			ListID:  listID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reorder reading list items: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.details(ctx, *list)
+}
+
+// HoldUnavailable places a hold for the list's owner on every item with no copy on the shelf.
+// Items the owner already has an open hold for are skipped, as are any that fail, so one bad
+// item does not stop the rest.
+func (s *ReadingListServiceImpl) HoldUnavailable(ctx context.Context, actor *repository.User, listID uuid.UUID) (*ReadingListHolds, error) {
+	ctx, span := tracer.Start(ctx, "ReadingListService.HoldUnavailable")
+	defer span.End()
+
+	list, err := s.authorize(ctx, actor, listID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListReadingListItems(ctx, listID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reading list items: %w", err)
+	}
+
+	result := &ReadingListHolds{
+		Placed:  []*repository.Hold{},
+		Skipped: []*SkippedHold{},
+	}
+	for _, item := range items {
+		if item.Available {
+			continue
+		}
+		hold, err := s.holds.Place(ctx, PlaceHoldParams{
+			UserID: list.UserID,
+			BookID: &item.BookID,
+		})
+		if err != nil {
+			result.Skipped = append(result.Skipped, &SkippedHold{
+				ItemID: item.ID,
+				BookID: item.BookID,
+				Title:  item.Title,
+				Reason: skippedHoldReason(ctx, item.ID, err),
+			})
+			continue
+		}
+		result.Placed = append(result.Placed, hold)
+	}
+	return result, nil
+}
+
+// skippedHoldReason explains why no hold was placed for a reading list item. Unexpected errors are
+// logged rather than shown, as they may carry database details.
+func skippedHoldReason(ctx context.Context, itemID uuid.UUID, err error) string {
+	switch {
+	case errors.Is(err, ErrHoldExists):
+		return ErrHoldExists.Error()
+	case errors.Is(err, ErrHoldTarget):
+		return ErrHoldTarget.Error()
+	case errors.Is(err, pgx.ErrNoRows):
+		return "book no longer exists"
+	default:
+		slog.ErrorContext(ctx, "Failed to place hold for reading list item", "item_id", itemID, "error", err)
+		return "hold could not be placed"
+	}
+}
+
+// details loads a reading list's items with their availability
+func (s *ReadingListServiceImpl) details(ctx context.Context, list repository.ReadingList) (*ReadingListDetails, error) {
+	items, err := s.repo.ListReadingListItems(ctx, list.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reading list items: %w", err)
+	}
+	details := &ReadingListDetails{
+		List:  &list,
+		Items: make([]*repository.ListReadingListItemsRow, len(items)),
+	}
+	for i := range items {
+		details.Items[i] = &items[i]
+	}
+	return details, nil
+}
+
+// withList runs fn in a transaction holding the reading list's row lock, so concurrent edits
+// to the same list cannot interleave their item positions
+func (s *ReadingListServiceImpl) withList(ctx context.Context, listID uuid.UUID, fn func(q *repository.Queries) error) error {
+	return s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+		if err := q.TouchReadingList(ctx, listID); err != nil {
+			return fmt.Errorf("failed to lock reading list: %w", err)
+		}
+		return fn(q)
+	})
+}
+
+// authorize loads a reading list, returning ErrReadingListForbidden unless the actor owns it or is an admin.
+// Private lists of other members are reported as not found.
+func (s *ReadingListServiceImpl) authorize(ctx context.Context, actor *repository.User, id uuid.UUID) (*repository.ReadingList, error) {
+	list, err := s.repo.GetReadingList(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reading list: %w", err)
+	}
+	if !ownsReadingList(actor, list) {
+		if !list.IsPublic {
+			return nil, fmt.Errorf("failed to get reading list: %w", pgx.ErrNoRows)
+		}
+		return nil, ErrReadingListForbidden
+	}
+	return &list, nil
+}
+
+func ownsReadingList(actor *repository.User, list repository.ReadingList) bool {
+	return actor != nil && (actor.ID == list.UserID || actor.Role == RoleAdmin)
+}
+
+// readingListShareToken returns the share token a list should have: none when private, the current one
+// when it already has one, and a new random token otherwise
+func readingListShareToken(public bool, current string) (pgtype.Text, error) {
+	if !public {
+		return pgtype.Text{}, nil
+	}
+	if current != "" {
+		return util.StringToPgText(current), nil
+	}
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return pgtype.Text{}, fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return util.StringToPgText(base64.RawURLEncoding.EncodeToString(b)), nil
+}
+
+// sameItems reports whether order is a permutation of current
+func sameItems(current, order []uuid.UUID) bool {
+	if len(current) != len(order) {
+		return false
+	}
+	seen := make(map[uuid.UUID]bool, len(order))
+	for _, id := range order {
+		if seen[id] || !slices.Contains(current, id) {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+)
+
+// Recommendation tuning. Similarity between two books is the cosine of their borrower sets, so it lies
+// between 0 and 1; each shared author or category adds its weight, and a book's average rating adds up
+// to ratingWeight.
+const (
+	minCoBorrowers       = 2
+	similarBooksPerBook  = 20
+	recommendationsLimit = 50
+	authorWeight         = 0.3
+	categoryWeight       = 0.1
+	ratingWeight         = 0.2
+	alsoBorrowedLimit    = 5
+)
+
+// RecommendationServiceImpl implements the RecommendationService interface
+type RecommendationServiceImpl struct {
+	db   *database.DB
+	repo *repository.Queries
+}
+
+// NewRecommendationService creates a new recommendation service
+func NewRecommendationService(db *database.DB, repo *repository.Queries) RecommendationService {
+	return &RecommendationServiceImpl{
+		db:   db,
+		repo: repo,
+	}
+}
+
+// ForUser gets a member's precomputed recommendations, best first. Books the member has borrowed or
+// reviewed since the last refresh are left out.
+func (s *RecommendationServiceImpl) ForUser(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]*repository.ListUserRecommendationsRow, error) {
+	ctx, span := tracer.Start(ctx, "RecommendationService.ForUser")
+	defer span.End()
+
+	recommendations, err := s.repo.ListUserRecommendations(ctx, repository.ListUserRecommendationsParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recommendations: %w", err)
+	}
+	recommendationPtrs := make([]*repository.ListUserRecommendationsRow, len(recommendations))
+	for i := range recommendations {
+		recommendationPtrs[i] = &recommendations[i]
+	}
+	return recommendationPtrs, nil
+}
+
+// AlsoBorrowed gets the books most often borrowed by the members who borrowed a book
+func (s *RecommendationServiceImpl) AlsoBorrowed(ctx context.Context, bookID uuid.UUID, limit int32) ([]*repository.ListAlsoBorrowedRow, error) {
+	ctx, span := tracer.Start(ctx, "RecommendationService.AlsoBorrowed")
+	defer span.End()
+
+	return alsoBorrowed(ctx, s.repo, bookID, limit)
+}
+
+// Refresh recomputes book similarities and every member's recommendations. Readers keep seeing the
+// previous results until the new ones are committed.
+func (s *RecommendationServiceImpl) Refresh(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "RecommendationService.Refresh")
+	defer span.End()
+
+	return s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		if err := q.ClearBookSimilarities(ctx); err != nil {
+			return fmt.Errorf("failed to clear book similarities: %w", err)
+		}
+		err := q.ComputeBookSimilarities(ctx, repository.ComputeBookSimilaritiesParams{
+			MinCoBorrowers: minCoBorrowers,
+			PerBook:        similarBooksPerBook,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to compute book similarities: %w", err)
+		}
+
+		if err := q.ClearUserRecommendations(ctx); err != nil {
+			return fmt.Errorf("failed to clear recommendations: %w", err)
+		}
+		err = q.ComputeUserRecommendations(ctx, repository.ComputeUserRecommendationsParams{
+			AuthorWeight:   authorWeight,
+			CategoryWeight: categoryWeight,
+			RatingWeight:   ratingWeight,
+			PerUser:        recommendationsLimit,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to compute recommendations: %w", err)
+		}
+		return nil
+	})
+}
+
+// Run refreshes recommendations straight away and then every interval until ctx is cancelled
+func (s *RecommendationServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		if err := s.Refresh(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.ErrorContext(ctx, "Failed to refresh recommendations", "error", err)
+		} else {
+			slog.InfoContext(ctx, "Refreshed recommendations", "duration", time.Since(start).Round(time.Millisecond))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func alsoBorrowed(ctx context.Context, q *repository.Queries, bookID uuid.UUID, limit int32) ([]*repository.ListAlsoBorrowedRow, error) {
+	books, err := q.ListAlsoBorrowed(ctx, repository.ListAlsoBorrowedParams{
+		BookID: bookID,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list books also borrowed: %w", err)
+	}
+	bookPtrs := make([]*repository.ListAlsoBorrowedRow, len(books))
+	for i := range books {
+		bookPtrs[i] = &books[i]
+	}
+	return bookPtrs, nil
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vasujain275/bookbridge-api/internal/database"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// Review statuses. Only published reviews are public and count towards a book's rating.
+const (
+	ReviewStatusPending   = "pending"
+	ReviewStatusPublished = "published"
+	ReviewStatusRejected  = "rejected"
+	ReviewStatusHidden    = "hidden"
+)
+
+// Review list orderings
+const (
+	ReviewSortRecent  = "recent"
+	ReviewSortHelpful = "helpful"
+	ReviewSortRating  = "rating"
+)
+
+var (
+	// ErrReviewSort is returned for review list orderings other than recent, helpful and rating
+	ErrReviewSort = errors.New("sort must be recent, helpful or rating")
+	// ErrReviewExists is returned when a member reviews a book they have already reviewed
+	ErrReviewExists = errors.New("user has already reviewed this book")
+	// ErrReviewForbidden is returned when someone other than the reviewer or an admin changes a review
+	ErrReviewForbidden = errors.New("only the reviewer or an admin may change this review")
+	// ErrReviewOwnVote is returned when a member votes on their own review
+	ErrReviewOwnVote = errors.New("users cannot vote on their own reviews")
+	// ErrReviewRateLimited is returned when a member with too many recently rejected reviews posts another
+	ErrReviewRateLimited = errors.New("too many of your reviews have been rejected recently; try again later")
+)
+
+// ReviewPolicy configures how new reviews are screened
+type ReviewPolicy struct {
+	// BlockedWords are words or phrases that hold a review for moderation instead of publishing it
+	BlockedWords []string
+	// RejectionLimit is how many rejected reviews within RejectionWindow stop a member from posting; 0 disables the limit
+	RejectionLimit  int
+	RejectionWindow time.Duration
+}
+
+// ReviewServiceImpl implements the ReviewService interface
+type ReviewServiceImpl struct {
+	db     *database.DB
+	repo   *repository.Queries
+	policy ReviewPolicy
+	filter wordFilter
+}
+
+// NewReviewService creates a new review service
+func NewReviewService(db *database.DB, repo *repository.Queries, policy ReviewPolicy) ReviewService {
+	return &ReviewServiceImpl{
+		db:     db,
+		repo:   repo,
+		policy: policy,
+		filter: newWordFilter(policy.BlockedWords),
+	}
+}
+
+// GetByID gets a review by ID
+func (s *ReviewServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*Review, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.GetByID")
+	defer span.End()
+
+	review, err := s.repo.GetReview(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	return s.toReview(ctx, review)
+}
+
+// GetByUserAndBook gets a member's review of a book
+func (s *ReviewServiceImpl) GetByUserAndBook(ctx context.Context, userID, bookID uuid.UUID) (*Review, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.GetByUserAndBook")
+	defer span.End()
+
+	review, err := s.repo.GetReviewByUserAndBook(ctx, repository.GetReviewByUserAndBookParams{
+		UserID: userID,
+		BookID: bookID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	return s.toReview(ctx, review)
+}
+
+// List gets the most recent reviews
+func (s *ReviewServiceImpl) List(ctx context.Context, limit, offset int32) ([]*Review, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.List")
+	defer span.End()
+
+	reviews, err := s.repo.ListReviews(ctx, repository.ListReviewsParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	return s.toReviews(ctx, reviews)
+}
+
+// ListByBookID gets a book's reviews, newest first by default. Sorting by helpful puts the reviews with the
+// most net helpful votes first; sorting by rating puts the highest ratings first. Ties go to the newest.
+func (s *ReviewServiceImpl) ListByBookID(ctx context.Context, bookID uuid.UUID, sort string, limit, offset int32) ([]*Review, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.ListByBookID")
+	defer span.End()
+
+	switch sort {
+	case "":
+		sort = ReviewSortRecent
+	case ReviewSortRecent, ReviewSortHelpful, ReviewSortRating:
+	default:
+		return nil, ErrReviewSort
+	}
+
+	reviews, err := s.repo.ListReviewsByBookID(ctx, repository.ListReviewsByBookIDParams{
+		BookID: bookID,
+		Sort:   sort,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	return s.toReviews(ctx, reviews)
+}
+
+// ListByUserID gets a member's most recent reviews, including those not published when includeUnpublished is set
+func (s *ReviewServiceImpl) ListByUserID(ctx context.Context, userID uuid.UUID, includeUnpublished bool, limit, offset int32) ([]*Review, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.ListByUserID")
+	defer span.End()
+
+	reviews, err := s.repo.ListReviewsByUserID(ctx, repository.ListReviewsByUserIDParams{
+		UserID:             userID,
+		IncludeUnpublished: includeUnpublished,
+		Limit:              limit,
+		Offset:             offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	return s.toReviews(ctx, reviews)
+}
+
+// Create adds a member's review of a book and updates the book's rating. Each member may review a book once.
+// Reviews containing blocked words are held as pending until a moderator approves them.
+func (s *ReviewServiceImpl) Create(ctx context.Context, params repository.CreateReviewParams) (*Review, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.Create")
+	defer span.End()
+
+	if s.policy.RejectionLimit > 0 {
+		rejected, err := s.repo.CountReviewRejectionsSince(ctx, repository.CountReviewRejectionsSinceParams{
+			UserID:    params.UserID,
+			CreatedAt: pgtype.Timestamp{Time: time.Now().Add(-s.policy.RejectionWindow), Valid: true},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count rejected reviews: %w", err)
+		}
+		if rejected >= int64(s.policy.RejectionLimit) {
+			return nil, ErrReviewRateLimited
+		}
+	}
+
+	params.Status = ReviewStatusPublished
+	params.FlagReason = pgtype.Text{}
+	if reason := s.filter.check(util.PgTextToString(params.ReviewText)); reason != "" {
+		params.Status = ReviewStatusPending
+		params.FlagReason = util.StringToPgText(reason)
+	}
+
+	var review repository.BookReview
+	err := s.withBookRating(ctx, params.BookID, func(q *repository.Queries) error {
+		var err error
+		review, err = q.CreateReview(ctx, params)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				switch pgErr.Code {
+				case "23505":
+					return ErrReviewExists
+				case "23503":
+					return fmt.Errorf("failed to create review: book or user not found: %w", pgx.ErrNoRows)
+				}
+			}
+			return fmt.Errorf("failed to create review: %w", err)
+		}
+		return recordReviewCreated(ctx, q, &review)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toReview(ctx, review)
+}
+
+// recordReviewCreated records a review.created event once the review is published. Reviews held for
+// moderation are not announced until a moderator approves them, and a review is announced only once.
+func recordReviewCreated(ctx context.Context, q *repository.Queries, review *repository.BookReview) error {
+	if review.Status != ReviewStatusPublished {
+		return nil
+	}
+	return recordEvent(ctx, q, EventReviewCreated, EventReviewCreated+":"+review.ID.String(), review)
+}
+
+// Update changes a review's rating and text and updates the book's rating.
+// Text containing blocked words sends the review back to moderation, as does editing a rejected review.
+func (s *ReviewServiceImpl) Update(ctx context.Context, actor *repository.User, id uuid.UUID, rating int32, reviewText *string) (*Review, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.Update")
+	defer span.End()
+
+	existing, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var review repository.BookReview
+	err = s.withBookRating(ctx, existing.BookID, func(q *repository.Queries) error {
+		text := existing.ReviewText
+		if reviewText != nil {
+			text = util.StringToPgText(*reviewText)
+		}
+		status, flagReason := existing.Status, existing.FlagReason
+		if reason := s.filter.check(util.PgTextToString(text)); reason != "" {
+			status, flagReason = ReviewStatusPending, util.StringToPgText(reason)
+		} else if status == ReviewStatusRejected {
+			status, flagReason = ReviewStatusPending, pgtype.Text{}
+		}
+
+		review, err = q.UpdateReview(ctx, repository.UpdateReviewParams{
+			ID:         id,
+			Rating:     rating,
+			ReviewText: text,
+			Status:     status,
+			FlagReason: flagReason,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update review: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toReview(ctx, review)
+}
+
+// Delete deletes a review and updates the book's rating
+func (s *ReviewServiceImpl) Delete(ctx context.Context, actor *repository.User, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "ReviewService.Delete")
+	defer span.End()
+
+	existing, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+
+	return s.withBookRating(ctx, existing.BookID, func(q *repository.Queries) error {
+		if err := q.DeleteReview(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete review: %w", err)
+		}
+		return nil
+	})
+}
+
+// Vote records the actor's helpful or unhelpful vote on a published review, replacing any earlier vote
+func (s *ReviewServiceImpl) Vote(ctx context.Context, actor *repository.User, id uuid.UUID, helpful bool) (*Review, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.Vote")
+	defer span.End()
+
+	return s.withReviewVotes(ctx, actor, id, func(q *repository.Queries) error {
+		err := q.UpsertReviewVote(ctx, repository.UpsertReviewVoteParams{
+			ReviewID: id,
+			UserID:   actor.ID,
+			Helpful:  helpful,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record vote: %w", err)
+		}
+		return nil
+	})
+}
+
+// Unvote withdraws the actor's vote on a review
+func (s *ReviewServiceImpl) Unvote(ctx context.Context, actor *repository.User, id uuid.UUID) (*Review, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.Unvote")
+	defer span.End()
+
+	return s.withReviewVotes(ctx, actor, id, func(q *repository.Queries) error {
+		err := q.DeleteReviewVote(ctx, repository.DeleteReviewVoteParams{
+			ReviewID: id,
+			UserID:   actor.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to withdraw vote: %w", err)
+		}
+		return nil
+	})
+}
+
+// withReviewVotes runs fn in a transaction holding the review's row lock, then recounts the review's votes
+func (s *ReviewServiceImpl) withReviewVotes(ctx context.Context, actor *repository.User, id uuid.UUID, fn func(q *repository.Queries) error) (*Review, error) {
+	existing, err := s.repo.GetReview(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if existing.Status != ReviewStatusPublished {
+		return nil, fmt.Errorf("failed to get review: %w", pgx.ErrNoRows)
+	}
+	if existing.UserID == actor.ID {
+		return nil, ErrReviewOwnVote
+	}
+
+	var review repository.BookReview
+	err = s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		if err := q.LockReview(ctx, id); err != nil {
+			return fmt.Errorf("failed to lock review: %w", err)
+		}
+		if err := fn(q); err != nil {
+			return err
+		}
+		review, err = q.RefreshReviewVotes(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to count votes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toReview(ctx, review)
+}
+
+// authorize loads a review and checks the actor wrote it or is an admin
+func (s *ReviewServiceImpl) authorize(ctx context.Context, actor *repository.User, id uuid.UUID) (*repository.BookReview, error) {
+	review, err := s.repo.GetReview(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if actor == nil || (actor.ID != review.UserID && actor.Role != RoleAdmin) {
+		return nil, ErrReviewForbidden
+	}
+	return &review, nil
+}
+
+// withBookRating runs fn in a transaction holding the book's row lock, then recomputes the book's rating.
+// The lock makes concurrent reviews of the same book wait, so each recount sees the others' committed changes.
+func (s *ReviewServiceImpl) withBookRating(ctx context.Context, bookID uuid.UUID, fn func(q *repository.Queries) error) error {
+	return s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		if err := q.LockBook(ctx, bookID); err != nil {
+			return fmt.Errorf("failed to lock book: %w", err)
+		}
+		if err := fn(q); err != nil {
+			return err
+		}
+		if err := q.RefreshBookRating(ctx, bookID); err != nil {
+			return fmt.Errorf("failed to update book rating: %w", err)
+		}
+		return nil
+	})
+}
+
+// toReviews marks the reviews whose authors have borrowed and returned the book they reviewed
+func (s *ReviewServiceImpl) toReviews(ctx context.Context, reviews []repository.BookReview) ([]*Review, error) {
+	ids := make([]uuid.UUID, len(reviews))
+	for i := range reviews {
+		ids[i] = reviews[i].ID
+	}
+	verified, err := s.repo.ListVerifiedReviewIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check verified borrowers: %w", err)
+	}
+
+	result := make([]*Review, len(reviews))
+	for i := range reviews {
+		result[i] = &Review{
+			BookReview:       reviews[i],
+			VerifiedBorrower: slices.Contains(verified, reviews[i].ID),
+		}
+	}
+	return result, nil
+}
+
+func (s *ReviewServiceImpl) toReview(ctx context.Context, review repository.BookReview) (*Review, error) {
+	reviews, err := s.toReviews(ctx, []repository.BookReview{review})
+	if err != nil {
+		return nil, err
+	}
+	return reviews[0], nil
+}
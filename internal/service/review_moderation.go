@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// Moderation actions and the review status each one sets
+const (
+	ModerationApprove = "approve"
+	ModerationReject  = "reject"
+	ModerationHide    = "hide"
+)
+
+// Reasons a member may give when reporting a review
+const (
+	ReportReasonSpam      = "spam"
+	ReportReasonOffensive = "offensive"
+	ReportReasonSpoiler   = "spoiler"
+	ReportReasonOffTopic  = "off_topic"
+	ReportReasonOther     = "other"
+)
+
+var (
+	// ErrModerationAction is returned for moderation actions other than approve, reject and hide
+	ErrModerationAction = errors.New("action must be approve, reject or hide")
+	// ErrReviewReported is returned when a member reports a review they have already reported
+	ErrReviewReported = errors.New("user has already reported this review")
+)
+
+var moderationStatuses = map[string]string{
+	ModerationApprove: ReviewStatusPublished,
+	ModerationReject:  ReviewStatusRejected,
+	ModerationHide:    ReviewStatusHidden,
+}
+
+// Report records a member's report of a published review for moderators to look at
+func (s *ReviewServiceImpl) Report(ctx context.Context, actor *repository.User, reviewID uuid.UUID, reason, details string) (*repository.ReviewReport, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.Report")
+	defer span.End()
+
+	review, err := s.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != ReviewStatusPublished {
+		return nil, fmt.Errorf("failed to get review: %w", pgx.ErrNoRows)
+	}
+
+	report, err := s.repo.CreateReviewReport(ctx, repository.CreateReviewReportParams{
+		ReviewID: reviewID,
+		UserID:   actor.ID,
+		Reason:   reason,
+		Details:  util.StringToPgText(details),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrReviewReported
+		}
+		return nil, fmt.Errorf("failed to report review: %w", err)
+	}
+	return &report, nil
+}
+
+// ModerationQueue gets the reviews awaiting a moderator, oldest first: those held as pending
+// and published ones with open reports
+func (s *ReviewServiceImpl) ModerationQueue(ctx context.Context, limit, offset int32) ([]*ModerationItem, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.ModerationQueue")
+	defer span.End()
+
+	reviews, err := s.repo.ListModerationQueue(ctx, repository.ListModerationQueueParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list moderation queue: %w", err)
+	}
+
+	items := make([]*ModerationItem, len(reviews))
+	for i := range reviews {
+		reports, err := s.repo.ListReviewReports(ctx, reviews[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list review reports: %w", err)
+		}
+		item := &ModerationItem{
+			Review:  &reviews[i],
+			Reports: make([]*repository.ReviewReport, len(reports)),
+		}
+		for j := range reports {
+			item.Reports[j] = &reports[j]
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// Moderate approves, rejects or hides a review with a note, resolves its open reports and updates the book's rating.
+// Approving a review held for moderation records its review.created event.
+func (s *ReviewServiceImpl) Moderate(ctx context.Context, moderator *repository.User, id uuid.UUID, action, note string) (*Review, error) {
+	ctx, span := tracer.Start(ctx, "ReviewService.Moderate")
+	defer span.End()
+
+	status, ok := moderationStatuses[action]
+	if !ok {
+		return nil, ErrModerationAction
+	}
+
+	existing, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	moderatorID := pgtype.UUID{Bytes: moderator.ID, Valid: true}
+	var review repository.BookReview
+	err = s.withBookRating(ctx, existing.BookID, func(q *repository.Queries) error {
+		var err error
+		review, err = q.ModerateReview(ctx, repository.ModerateReviewParams{
+			ID:             id,
+			Status:         status,
+			ModerationNote: util.StringToPgText(note),
+			ModeratedBy:    moderatorID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to moderate review: %w", err)
+		}
+		err = q.ResolveReviewReports(ctx, repository.ResolveReviewReportsParams{
+			ReviewID:   id,
+			ResolvedBy: moderatorID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to resolve review reports: %w", err)
+		}
+
+		if err := recordReviewCreated(ctx, q, &review); err != nil {
+			return err
+		}
+
+		// Rejections are kept apart from the review, so editing or deleting it does not lift the posting limit
+		if status == ReviewStatusRejected && existing.Status != ReviewStatusRejected {
+			err = q.CreateReviewRejection(ctx, repository.CreateReviewRejectionParams{
+				UserID:      review.UserID,
+				ReviewID:    review.ID,
+				ModeratedBy: moderatorID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record review rejection: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toReview(ctx, review)
+}
+
+// ReviewVisibleTo reports whether a review may be shown to the actor: published reviews to anyone,
+// others only to their author and admins
+func ReviewVisibleTo(review *repository.BookReview, actor *repository.User) bool {
+	if review.Status == ReviewStatusPublished {
+		return true
+	}
+	return actor != nil && (actor.ID == review.UserID || actor.Role == RoleAdmin)
+}
+
+// wordFilter matches review text against blocked words and phrases, ignoring case and punctuation.
+// Terms only match whole words, so "ass" does not flag "class".
+type wordFilter struct {
+	terms []string
+}
+
+func newWordFilter(words []string) wordFilter {
+	var f wordFilter
+	for _, word := range words {
+		if term := normalizeWords(word); term != "" {
+			f.terms = append(f.terms, term)
+		}
+	}
+	return f
+}
+
+// check returns a flag reason naming the blocked terms found in text, or "" if there are none
+func (f wordFilter) check(text string) string {
+	if len(f.terms) == 0 || text == "" {
+		return ""
+	}
+	padded := " " + normalizeWords(text) + " "
+	var matched []string
+	for _, term := range f.terms {
+		if strings.Contains(padded, " "+term+" ") {
+			matched = append(matched, term)
+		}
+	}
+	if len(matched) == 0 {
+		return ""
+	}
+	return "contains blocked words: " + strings.Join(matched, ", ")
+}
+
+// normalizeWords lower-cases s and reduces it to words separated by single spaces
+func normalizeWords(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// ErrSeriesExists is returned when creating or renaming a series to a name already in use
+var ErrSeriesExists = errors.New("series with this name already exists")
+
+// seriesVolumePattern splits Open Library series strings such as "Discworld ; 3",
+// "Harry Potter -- 1", "The Expanse, book 2" or "Dune Chronicles (#4)" into name and volume
+var seriesVolumePattern = regexp.MustCompile(`(?i)^(.+?)[\s,;:.\-(#]+(?:volume|vol\.?|v\.|book|bk\.?|number|no\.?|part|pt\.?)?\s*#?\s*(\d+)\s*\)?\.?$`)
+
+// SeriesServiceImpl implements the SeriesService interface
+type SeriesServiceImpl struct {
+	repo *repository.Queries
+}
+
+// NewSeriesService creates a new series service
+func NewSeriesService(repo *repository.Queries) SeriesService {
+	return &SeriesServiceImpl{
+		repo: repo,
+	}
+}
+
+// GetByID gets a series with its volumes in reading order
+func (s *SeriesServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*SeriesDetails, error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.GetByID")
+	defer span.End()
+
+	series, err := s.repo.GetSeries(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series: %w", err)
+	}
+
+	volumes, err := s.repo.ListSeriesVolumes(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list series volumes: %w", err)
+	}
+
+	details := &SeriesDetails{
+		Series:  &series,
+		Volumes: make([]*repository.ListSeriesVolumesRow, len(volumes)),
+	}
+	for i := range volumes {
+		details.Volumes[i] = &volumes[i]
+	}
+	return details, nil
+}
+
+// List gets a list of series
+func (s *SeriesServiceImpl) List(ctx context.Context, limit, offset int32) ([]*repository.Series, error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.List")
+	defer span.End()
+
+	series, err := s.repo.ListSeries(ctx, repository.ListSeriesParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list series: %w", err)
+	}
+	seriesPtrs := make([]*repository.Series, len(series))
+	for i := range series {
+		seriesPtrs[i] = &series[i]
+	}
+	return seriesPtrs, nil
+}
+
+// Create creates a new series
+func (s *SeriesServiceImpl) Create(ctx context.Context, name, description string) (*repository.Series, error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.Create")
+	defer span.End()
+
+	series, err := s.repo.CreateSeries(ctx, repository.CreateSeriesParams{
+		Name:        name,
+		Description: util.StringToPgText(description),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrSeriesExists
+		}
+		return nil, fmt.Errorf("failed to create series: %w", err)
+	}
+	return &series, nil
+}
+
+// Update renames a series or changes its description
+func (s *SeriesServiceImpl) Update(ctx context.Context, id uuid.UUID, name, description string) (*repository.Series, error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.Update")
+	defer span.End()
+
+	series, err := s.repo.UpdateSeries(ctx, repository.UpdateSeriesParams{
+		ID:          id,
+		Name:        name,
+		Description: util.StringToPgText(description),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrSeriesExists
+		}
+		return nil, fmt.Errorf("failed to update series: %w", err)
+	}
+	return &series, nil
+}
+
+// Delete deletes a series. The works in it are kept.
+func (s *SeriesServiceImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "SeriesService.Delete")
+	defer span.End()
+
+	err := s.repo.DeleteSeries(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete series: %w", err)
+	}
+	return nil
+}
+
+// SetVolume adds a work to a series, or moves it if it is already a member
+func (s *SeriesServiceImpl) SetVolume(ctx context.Context, seriesID, workID uuid.UUID, volume int32) error {
+	ctx, span := tracer.Start(ctx, "SeriesService.SetVolume")
+	defer span.End()
+
+	err := s.repo.SetSeriesWorkVolume(ctx, repository.SetSeriesWorkVolumeParams{
+		SeriesID: seriesID,
+		WorkID:   workID,
+		Volume:   volume,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set series volume: %w", err)
+	}
+	return nil
+}
+
+// RemoveWork removes a work from a series
+func (s *SeriesServiceImpl) RemoveWork(ctx context.Context, seriesID, workID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "SeriesService.RemoveWork")
+	defer span.End()
+
+	err := s.repo.RemoveSeriesWork(ctx, repository.RemoveSeriesWorkParams{
+		SeriesID: seriesID,
+		WorkID:   workID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove work from series: %w", err)
+	}
+	return nil
+}
+
+// ListByWorkID gets every series a work belongs to, with the volumes either side of it
+func (s *SeriesServiceImpl) ListByWorkID(ctx context.Context, workID uuid.UUID) ([]*SeriesNavigation, error) {
+	ctx, span := tracer.Start(ctx, "SeriesService.ListByWorkID")
+	defer span.End()
+
+	return seriesNavigation(ctx, s.repo, workID)
+}
+
+// seriesNavigation places a work within each of its series, looking up the previous and next volumes
+func seriesNavigation(ctx context.Context, q *repository.Queries, workID uuid.UUID) ([]*SeriesNavigation, error) {
+	memberships, err := q.ListSeriesByWorkID(ctx, workID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list series for work: %w", err)
+	}
+
+	navigation := make([]*SeriesNavigation, 0, len(memberships))
+	for _, m := range memberships {
+		volumes, err := q.ListSeriesVolumes(ctx, m.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list series volumes: %w", err)
+		}
+
+		nav := &SeriesNavigation{
+			Series: &repository.Series{
+				ID:          m.ID,
+				Name:        m.Name,
+				Description: m.Description,
+				CreatedAt:   m.CreatedAt,
+				UpdatedAt:   m.UpdatedAt,
+			},
+			Volume: m.Volume,
+		}
+		// Volumes are ordered, so the last lower volume is the previous one and the first higher is the next
+		for i := range volumes {
+			v := &volumes[i]
+			if v.Volume < m.Volume {
+				nav.Previous = v
+			} else if v.Volume > m.Volume && nav.Next == nil {
+				nav.Next = v
+			}
+		}
+		navigation = append(navigation, nav)
+	}
+	return navigation, nil
+}
+
+// attachSeries records a work's membership of the series Open Library lists for it.
+// Entries without a volume number, such as publisher imprints, are skipped.
+func attachSeries(ctx context.Context, q *repository.Queries, workID uuid.UUID, entries []string) error {
+	for _, entry := range entries {
+		name, volume, ok := parseSeries(entry)
+		if !ok {
+			continue
+		}
+
+		series, err := q.GetSeriesByName(ctx, name)
+		if errors.Is(err, pgx.ErrNoRows) {
+			series, err = q.CreateSeries(ctx, repository.CreateSeriesParams{Name: name})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to resolve series %q: %w", name, err)
+		}
+
+		err = q.AddSeriesWork(ctx, repository.AddSeriesWorkParams{
+			SeriesID: series.ID,
+			WorkID:   workID,
+			Volume:   volume,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add work to series: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseSeries splits an Open Library series entry into its name and volume number
+func parseSeries(entry string) (string, int32, bool) {
+	m := seriesVolumePattern.FindStringSubmatch(strings.TrimSpace(entry))
+	if m == nil {
+		return "", 0, false
+	}
+	volume, err := strconv.ParseInt(m[2], 10, 32)
+	if err != nil {
+		return "", 0, false
+	}
+	name := strings.TrimSpace(m[1])
+	if name == "" {
+		return "", 0, false
+	}
+	return name, int32(volume), true
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
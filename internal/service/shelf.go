@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vasujain275/bookbridge-api/internal/callnumber"
+	"github.com/vasujain275/bookbridge-api/internal/citation"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// ErrShelfScheme is returned for classification schemes other than Dewey and LC
+var ErrShelfScheme = errors.New("scheme must be dewey or lc")
+
+// shelfRangeEnd is appended to the key of the end of a shelf range so that everything filed under it is included,
+// e.g. "899" still covers "899.5 SMI 2001"
+const shelfRangeEnd = "~"
+
+// ShelfServiceImpl implements the ShelfService interface
+type ShelfServiceImpl struct {
+	repo   *repository.Queries
+	scheme string
+}
+
+// NewShelfService creates a new shelf service. Call numbers are built in the given scheme when a book has both classifications.
+func NewShelfService(repo *repository.Queries, scheme string) ShelfService {
+	return &ShelfServiceImpl{
+		repo:   repo,
+		scheme: scheme,
+	}
+}
+
+// Browse lists books in call-number order between two call numbers, like walking the stacks.
+// Either end may be empty or a partial call number such as "800" or "PR"; the scheme is inferred from each end unless given.
+func (s *ShelfServiceImpl) Browse(ctx context.Context, from, to, scheme string, limit, offset int32) ([]*ShelfEntry, error) {
+	ctx, span := tracer.Start(ctx, "ShelfService.Browse")
+	defer span.End()
+
+	if scheme != "" && scheme != callnumber.SchemeDewey && scheme != callnumber.SchemeLC {
+		return nil, ErrShelfScheme
+	}
+
+	var fromKey, toKey string
+	if from != "" {
+		fromKey = callnumber.ShelfKey(shelfScheme(scheme, from), from)
+	}
+	if to != "" {
+		toKey = callnumber.ShelfKey(shelfScheme(scheme, to), to) + shelfRangeEnd
+	}
+
+	books, err := s.repo.ListShelf(ctx, repository.ListShelfParams{
+		FromKey: fromKey,
+		ToKey:   toKey,
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shelf: %w", err)
+	}
+
+	entries := make([]*ShelfEntry, len(books))
+	for i := range books {
+		entries[i] = toShelfEntry(&books[i])
+	}
+	return entries, nil
+}
+
+// Classify replaces a book's Dewey and LC classifications and regenerates its call number
+func (s *ShelfServiceImpl) Classify(ctx context.Context, bookID uuid.UUID, dewey, lc string) (*ShelfEntry, error) {
+	ctx, span := tracer.Start(ctx, "ShelfService.Classify")
+	defer span.End()
+
+	book, err := s.repo.GetBook(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get book: %w", err)
+	}
+
+	book, err = classifyBook(ctx, s.repo, book, dewey, lc, s.scheme)
+	if err != nil {
+		return nil, err
+	}
+	return toShelfEntry(&book), nil
+}
+
+// classifyBook stores a book's classifications with a call number cut from its first author, or its title when it has none
+func classifyBook(ctx context.Context, q *repository.Queries, book repository.Book, dewey, lc, scheme string) (repository.Book, error) {
+	authors, err := q.ListAuthorsByBookID(ctx, book.ID)
+	if err != nil {
+		return book, fmt.Errorf("failed to list authors: %w", err)
+	}
+	var author string
+	if len(authors) > 0 {
+		author = authors[0].Name
+	}
+
+	params := repository.UpdateBookClassificationParams{
+		ID:                book.ID,
+		DeweyDecimalClass: util.StringToPgText(callnumber.NormalizeDewey(dewey)),
+		LcClassification:  util.StringToPgText(callnumber.NormalizeLC(lc)),
+	}
+	callNumber, ok := callnumber.Build(scheme, callnumber.Input{
+		Dewey:     dewey,
+		LC:        lc,
+		MainEntry: callnumber.MainEntry(author, book.Title),
+		Year:      citation.ExtractYear(util.PgTextToString(book.PublishedDate)),
+	})
+	if ok {
+		params.CallNumber = util.StringToPgText(callNumber.Value)
+		params.ShelfKey = util.StringToPgText(callNumber.Key)
+	}
+
+	book, err = q.UpdateBookClassification(ctx, params)
+	if err != nil {
+		return book, fmt.Errorf("failed to update book classification: %w", err)
+	}
+	return book, nil
+}
+
+func toShelfEntry(book *repository.Book) *ShelfEntry {
+	return &ShelfEntry{
+		Book:        book,
+		CallNumbers: callnumber.Copies(util.PgTextToString(book.CallNumber), int(book.TotalCopies)),
+	}
+}
+
+func shelfScheme(scheme, callNumber string) string {
+	if scheme != "" {
+		return scheme
+	}
+	return callnumber.InferScheme(callNumber)
+}
@@ -0,0 +1,7 @@
+package service
+
+import "go.opentelemetry.io/otel"
+
+// tracer starts the span around each service method, named after the service and method, e.g.
+// "BookService.Create"
+var tracer = otel.Tracer("github.com/vasujain275/bookbridge-api/internal/service")
@@ -1,28 +1,50 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/database"
 	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// User roles
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// ErrInvalidCredentials is returned when a username and password do not match a user
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// unknownUserHash is compared against when the username does not exist, so the response takes as long
+// as for a wrong password and does not reveal which usernames are taken
+var unknownUserHash = []byte("$2a$10$VogoVQGhuCi2KNFxWveHcu.l3GXKaFPBUzuYqihkUJLzO60bTg0em")
+
 // UserServiceImpl implements the UserService interface
 type UserServiceImpl struct {
+	db   *database.DB
 	repo *repository.Queries
 }
 
 // NewUserService creates a new user service
-func NewUserService(repo *repository.Queries) UserService {
+func NewUserService(db *database.DB, repo *repository.Queries) UserService {
 	return &UserServiceImpl{
+		db:   db,
 		repo: repo,
 	}
 }
 
 // GetByID gets a user by ID
 func (s *UserServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*repository.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetByID")
+	defer span.End()
+
 	user, err := s.repo.GetUser(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
@@ -32,6 +54,9 @@ func (s *UserServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*repositor
 
 // GetByUsername gets a user by username
 func (s *UserServiceImpl) GetByUsername(ctx context.Context, username string) (*repository.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetByUsername")
+	defer span.End()
+
 	user, err := s.repo.GetUserByUsername(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by username: %w", err)
@@ -41,6 +66,9 @@ func (s *UserServiceImpl) GetByUsername(ctx context.Context, username string) (*
 
 // GetByEmail gets a user by email
 func (s *UserServiceImpl) GetByEmail(ctx context.Context, email string) (*repository.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetByEmail")
+	defer span.End()
+
 	user, err := s.repo.GetUserByEmail(ctx, email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
@@ -48,8 +76,30 @@ func (s *UserServiceImpl) GetByEmail(ctx context.Context, email string) (*reposi
 	return &user, nil
 }
 
+// Authenticate gets the user with the given username if password matches their stored hash
+func (s *UserServiceImpl) Authenticate(ctx context.Context, username, password string) (*repository.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Authenticate")
+	defer span.End()
+
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			bcrypt.CompareHashAndPassword(unknownUserHash, []byte(password))
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to get user by username: %w", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return &user, nil
+}
+
 // List gets a list of users
 func (s *UserServiceImpl) List(ctx context.Context, limit, offset int32) ([]*repository.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.List")
+	defer span.End()
+
 	users, err := s.repo.ListUsers(ctx, repository.ListUsersParams{
 		Limit:  limit,
 		Offset: offset,
@@ -67,10 +117,20 @@ func (s *UserServiceImpl) List(ctx context.Context, limit, offset int32) ([]*rep
 	return userPtrs, nil
 }
 
-// Create creates a new user
+// Create creates a new user. params.PasswordHash carries the plain password, which is stored hashed.
+// The user.created event it records sends the welcome email.
 func (s *UserServiceImpl) Create(ctx context.Context, params repository.CreateUserParams) (*repository.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Create")
+	defer span.End()
+
+	hash, err := hashPassword(params.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+	params.PasswordHash = hash
+
 	// Check if user with username already exists
-	_, err := s.repo.GetUserByUsername(ctx, params.Username)
+	_, err = s.repo.GetUserByUsername(ctx, params.Username)
 	if err == nil {
 		return nil, errors.New("username already exists")
 	}
@@ -81,33 +141,139 @@ func (s *UserServiceImpl) Create(ctx context.Context, params repository.CreateUs
 		return nil, errors.New("email already exists")
 	}
 
-	user, err := s.repo.CreateUser(ctx, params)
+	var user repository.User
+	err = s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		user, err = q.CreateUser(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		err = recordAudit(ctx, q, AuditRecord{
+			Action:     AuditUserCreate,
+			EntityType: AuditEntityUser,
+			EntityID:   user.ID.String(),
+			After:      auditUser(&user),
+		})
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, q, EventUserCreated, EventUserCreated+":"+user.ID.String(), userEventData(&user))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+		return nil, err
 	}
 	return &user, nil
 }
 
-// Update updates a user
-func (s *UserServiceImpl) Update(ctx context.Context, params repository.UpdateUserParams) (*repository.User, error) {
-	// Check if user exists
-	_, err := s.repo.GetUser(ctx, params.ID)
-	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+// EnsureAdmin creates an admin with the given details unless a user with the username exists, so a
+// new installation has someone who can sign in and create other users
+func (s *UserServiceImpl) EnsureAdmin(ctx context.Context, params repository.CreateUserParams) error {
+	ctx, span := tracer.Start(ctx, "UserService.EnsureAdmin")
+	defer span.End()
+
+	_, err := s.repo.GetUserByUsername(ctx, params.Username)
+	if err == nil {
+		return nil
 	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to get user by username: %w", err)
+	}
+
+	params.Role = RoleAdmin
+	_, err = s.Create(ctx, params)
+	return err
+}
+
+// Update updates a user. Fields left empty keep their current values; params.PasswordHash carries a
+// new plain password, which is stored hashed.
+func (s *UserServiceImpl) Update(ctx context.Context, params repository.UpdateUserParams) (*repository.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Update")
+	defer span.End()
 
-	user, err := s.repo.UpdateUser(ctx, params)
+	var user repository.User
+	err := s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		// Check if user exists
+		before, err := q.GetUser(ctx, params.ID)
+		if err != nil {
+			return fmt.Errorf("user not found: %w", err)
+		}
+
+		if params.PasswordHash == "" {
+			params.PasswordHash = before.PasswordHash
+		} else if params.PasswordHash, err = hashPassword(params.PasswordHash); err != nil {
+			return err
+		}
+		params.Username = cmp.Or(params.Username, before.Username)
+		params.Email = cmp.Or(params.Email, before.Email)
+		params.Role = cmp.Or(params.Role, before.Role)
+		params.FirstName = cmp.Or(params.FirstName, before.FirstName)
+		params.LastName = cmp.Or(params.LastName, before.LastName)
+
+		user, err = q.UpdateUser(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		return recordAudit(ctx, q, AuditRecord{
+			Action:     AuditUserUpdate,
+			EntityType: AuditEntityUser,
+			EntityID:   user.ID.String(),
+			Before:     auditUser(&before),
+			After:      auditUser(&user),
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update user: %w", err)
+		return nil, err
 	}
 	return &user, nil
 }
 
 // Delete deletes a user
 func (s *UserServiceImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	err := s.repo.DeleteUser(ctx, id)
+	ctx, span := tracer.Start(ctx, "UserService.Delete")
+	defer span.End()
+
+	return s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		user, err := q.GetUser(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		// Remove the user's reviews explicitly so the ratings of the books they reviewed can be recounted
+		bookIDs, err := q.DeleteReviewsByUserID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete user reviews: %w", err)
+		}
+		for _, bookID := range bookIDs {
+			if err := q.RefreshBookRating(ctx, bookID); err != nil {
+				return fmt.Errorf("failed to update book rating: %w", err)
+			}
+		}
+
+		if err := q.DeleteUser(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+		return recordAudit(ctx, q, AuditRecord{
+			Action:     AuditUserDelete,
+			EntityType: AuditEntityUser,
+			EntityID:   id.String(),
+			Before:     auditUser(&user),
+		})
+	})
+}
+
+// hashPassword hashes a plain password for storing
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+		return "", fmt.Errorf("failed to hash password: %w", err)
 	}
-	return nil
+	return string(hash), nil
 }
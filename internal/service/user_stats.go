@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// favoriteLimit is how many favourite categories and authors the reading statistics name
+const favoriteLimit = 5
+
+// Stats gets a member's reading statistics, with a month-by-month breakdown of the given year
+// (the current year when zero). A book counts as read when a loan of it is returned, or when the
+// member reviews a book they never borrowed. Streaks count consecutive months with a book read;
+// the current streak survives until a month ends without one.
+func (s *UserServiceImpl) Stats(ctx context.Context, id uuid.UUID, year int) (*ReadingStats, error) {
+	ctx, span := tracer.Start(ctx, "UserService.Stats")
+	defer span.End()
+
+	user, err := s.repo.GetUser(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	now := time.Now()
+	if year == 0 {
+		year = now.Year()
+	}
+
+	reads, err := s.repo.ListUserReads(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reading history: %w", err)
+	}
+	summary, err := s.repo.GetUserLoanSummary(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize loans: %w", err)
+	}
+
+	stats := &ReadingStats{
+		UserID:          id,
+		Year:            year,
+		Months:          make([]*MonthlyReading, 12),
+		TotalLoans:      summary.TotalLoans,
+		OpenLoans:       summary.OpenLoans,
+		AverageLoanDays: summary.AverageLoanDays,
+		HistoryRetained: user.RetainHistory,
+	}
+	for m := range stats.Months {
+		stats.Months[m] = &MonthlyReading{Month: fmt.Sprintf("%04d-%02d", year, m+1)}
+	}
+
+	bookIDs := make([]uuid.UUID, len(reads))
+	months := make(map[int]bool)
+	for i, read := range reads {
+		bookIDs[i] = read.BookID
+		pages := util.PgIntToInt32(read.PageCount)
+		stats.BooksRead++
+		stats.PagesRead += pages
+
+		finished := read.FinishedOn.Time
+		months[monthIndex(finished)] = true
+		if finished.Year() == year {
+			month := stats.Months[finished.Month()-1]
+			month.Books++
+			month.Pages += pages
+			stats.BooksReadInYear++
+			stats.PagesReadInYear += pages
+		}
+	}
+	stats.CurrentStreak, stats.LongestStreak = readingStreaks(months, monthIndex(now))
+
+	categories, err := s.repo.ListFavoriteCategories(ctx, repository.ListFavoriteCategoriesParams{
+		BookIds: bookIDs,
+		Limit:   favoriteLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list favourite categories: %w", err)
+	}
+	stats.FavoriteCategories = make([]*repository.ListFavoriteCategoriesRow, len(categories))
+	for i := range categories {
+		stats.FavoriteCategories[i] = &categories[i]
+	}
+
+	authors, err := s.repo.ListFavoriteAuthors(ctx, repository.ListFavoriteAuthorsParams{
+		BookIds: bookIDs,
+		Limit:   favoriteLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list favourite authors: %w", err)
+	}
+	stats.FavoriteAuthors = make([]*repository.ListFavoriteAuthorsRow, len(authors))
+	for i := range authors {
+		stats.FavoriteAuthors[i] = &authors[i]
+	}
+
+	return stats, nil
+}
+
+// SetHistoryRetention opts a member in to or out of keeping their reading history. Opting out erases
+// the dates of their returned loans, keeping only which books they borrowed, so their reviews stay
+// verified and recommendations still skip those books.
+func (s *UserServiceImpl) SetHistoryRetention(ctx context.Context, id uuid.UUID, retain bool) (*repository.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.SetHistoryRetention")
+	defer span.End()
+
+	var user repository.User
+	err := s.db.WithTx(ctx, func(tx pgx.Tx) error {
+		q := s.repo.WithTx(tx)
+
+		before, err := q.GetUser(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		user, err = q.SetUserHistoryRetention(ctx, repository.SetUserHistoryRetentionParams{
+			ID:            id,
+			RetainHistory: retain,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update history retention: %w", err)
+		}
+		err = recordAudit(ctx, q, AuditRecord{
+			Action:     AuditUserHistoryRetention,
+			EntityType: AuditEntityUser,
+			EntityID:   id.String(),
+			Before:     &before,
+			After:      &user,
+		})
+		if err != nil {
+			return err
+		}
+		if retain {
+			return nil
+		}
+		if err := q.AnonymizeReturnedLoansByUserID(ctx, id); err != nil {
+			return fmt.Errorf("failed to erase reading history: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// monthIndex numbers months consecutively so that adjacent months differ by one
+func monthIndex(t time.Time) int {
+	return t.Year()*12 + int(t.Month()) - 1
+}
+
+// readingStreaks returns the current and longest runs of consecutive months with a book read.
+// The current run may end in the month before current, since the current month is not over yet.
+func readingStreaks(months map[int]bool, current int) (int, int) {
+	longest := 0
+	for m := range months {
+		if months[m-1] {
+			continue
+		}
+		run := 1
+		for months[m+run] {
+			run++
+		}
+		longest = max(longest, run)
+	}
+
+	end := current
+	if !months[end] {
+		end--
+	}
+	streak := 0
+	for months[end-streak] {
+		streak++
+	}
+	return streak, longest
+}
@@ -0,0 +1,448 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Headers sent with every webhook delivery. The signature is "sha256=" followed by the hex
+// HMAC-SHA256, keyed with the webhook's secret, of the timestamp header, a period and the body.
+const (
+	WebhookEventHeader     = "X-BookBridge-Event"
+	WebhookDeliveryHeader  = "X-BookBridge-Delivery"
+	WebhookTimestampHeader = "X-BookBridge-Timestamp"
+	WebhookSignatureHeader = "X-BookBridge-Signature"
+)
+
+// Webhook delivery tuning, as for notifications
+const (
+	webhookBatchSize   = 50
+	webhookLease       = 5 * time.Minute
+	webhookBackoff     = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+	webhookSecretBytes = 32
+	webhookMaxResponse = 64 << 10
+)
+
+var (
+	// ErrWebhookURL is returned when a webhook URL is not an absolute http or https URL
+	ErrWebhookURL = errors.New("url must be an absolute http or https URL")
+	// ErrWebhookEventTypes is returned when a webhook subscribes to no events or to unknown ones
+	ErrWebhookEventTypes = errors.New("event_types must name at least one known event type")
+	// ErrWebhookDeliveryStatus is returned when listing deliveries by a status other than pending, delivered and failed
+	ErrWebhookDeliveryStatus = errors.New("status must be pending, delivered or failed")
+	// ErrWebhookDeliveryPending is returned when redelivering a delivery that is still pending
+	ErrWebhookDeliveryPending = errors.New("delivery is already pending")
+)
+
+// WebhookParams describes a webhook registration. A new webhook gets a random secret unless one is given;
+// the secret of an existing webhook is never changed.
+type WebhookParams struct {
+	URL         string
+	Secret      string
+	EventTypes  []string
+	Description string
+	IsActive    bool
+}
+
+// WebhookPolicy configures how webhooks are delivered
+type WebhookPolicy struct {
+	// MaxAttempts is how many times delivery is tried before it is marked failed
+	MaxAttempts int
+	// Timeout bounds each delivery request
+	Timeout time.Duration
+}
+
+// WebhookServiceImpl implements the WebhookService interface
+type WebhookServiceImpl struct {
+	repo   *repository.Queries
+	client *http.Client
+	policy WebhookPolicy
+	wake   chan struct{}
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo *repository.Queries, policy WebhookPolicy) WebhookService {
+	return &WebhookServiceImpl{
+		repo:   repo,
+		client: &http.Client{Timeout: policy.Timeout},
+		policy: policy,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// GetByID gets a webhook by ID
+func (s *WebhookServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*repository.Webhook, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.GetByID")
+	defer span.End()
+
+	webhook, err := s.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+// List gets every webhook, oldest first
+func (s *WebhookServiceImpl) List(ctx context.Context) ([]*repository.Webhook, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.List")
+	defer span.End()
+
+	webhooks, err := s.repo.ListWebhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	webhookPtrs := make([]*repository.Webhook, len(webhooks))
+	for i := range webhooks {
+		webhookPtrs[i] = &webhooks[i]
+	}
+	return webhookPtrs, nil
+}
+
+// Create registers a webhook
+func (s *WebhookServiceImpl) Create(ctx context.Context, params WebhookParams) (*repository.Webhook, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.Create")
+	defer span.End()
+
+	if err := validateWebhook(params); err != nil {
+		return nil, err
+	}
+
+	secret := params.Secret
+	if secret == "" {
+		var err error
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook, err := s.repo.CreateWebhook(ctx, repository.CreateWebhookParams{
+		Url:         params.URL,
+		Secret:      secret,
+		EventTypes:  params.EventTypes,
+		Description: util.StringToPgText(params.Description),
+		IsActive:    params.IsActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+// Update changes a webhook's URL, events, description and whether it is active
+func (s *WebhookServiceImpl) Update(ctx context.Context, id uuid.UUID, params WebhookParams) (*repository.Webhook, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.Update")
+	defer span.End()
+
+	if err := validateWebhook(params); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.repo.UpdateWebhook(ctx, repository.UpdateWebhookParams{
+		ID:          id,
+		Url:         params.URL,
+		EventTypes:  params.EventTypes,
+		Description: util.StringToPgText(params.Description),
+		IsActive:    params.IsActive,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+// Delete removes a webhook and its delivery log
+func (s *WebhookServiceImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "WebhookService.Delete")
+	defer span.End()
+
+	if _, err := s.repo.GetWebhook(ctx, id); err != nil {
+		return fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if err := s.repo.DeleteWebhook(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries gets a webhook's delivery log, newest first, optionally only those with a status
+func (s *WebhookServiceImpl) ListDeliveries(ctx context.Context, webhookID uuid.UUID, status string, limit, offset int32) ([]*repository.WebhookDelivery, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.ListDeliveries")
+	defer span.End()
+
+	switch status {
+	case "", WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryFailed:
+	default:
+		return nil, ErrWebhookDeliveryStatus
+	}
+
+	if _, err := s.repo.GetWebhook(ctx, webhookID); err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	deliveries, err := s.repo.ListWebhookDeliveries(ctx, repository.ListWebhookDeliveriesParams{
+		WebhookID: webhookID,
+		Status:    status,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	deliveryPtrs := make([]*repository.WebhookDelivery, len(deliveries))
+	for i := range deliveries {
+		deliveryPtrs[i] = &deliveries[i]
+	}
+	return deliveryPtrs, nil
+}
+
+// Redeliver sends a delivered or failed event to its webhook once more, straight away
+func (s *WebhookServiceImpl) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*repository.WebhookDelivery, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.Redeliver")
+	defer span.End()
+
+	delivery, err := s.repo.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if delivery.WebhookID != webhookID {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", pgx.ErrNoRows)
+	}
+	if delivery.Status == WebhookDeliveryPending {
+		return nil, ErrWebhookDeliveryPending
+	}
+
+	delivery, err = s.repo.RedeliverWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWebhookDeliveryPending
+		}
+		return nil, fmt.Errorf("failed to redeliver webhook delivery: %w", err)
+	}
+	s.notify()
+	return &delivery, nil
+}
+
+// Publish queues an event for every active webhook subscribed to its type. An event published again
+// is not queued twice for the same webhook.
+func (s *WebhookServiceImpl) Publish(ctx context.Context, event Event) error {
+	ctx, span := tracer.Start(ctx, "WebhookService.Publish")
+	defer span.End()
+
+	webhooks, err := s.repo.ListWebhooksForEvent(ctx, event.Type)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+	}
+	for _, webhook := range webhooks {
+		_, err := s.repo.CreateWebhookDelivery(ctx, repository.CreateWebhookDeliveryParams{
+			WebhookID: webhook.ID,
+			EventID:   event.ID,
+			EventType: event.Type,
+			Payload:   payload,
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to queue webhook delivery: %w", err)
+		}
+	}
+	s.notify()
+	return nil
+}
+
+// Run delivers pending webhook deliveries straight away and then every interval until ctx is
+// cancelled. Newly queued deliveries are sent without waiting for the next interval.
+func (s *WebhookServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.deliver(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Failed to deliver webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// deliver sends pending deliveries whose next attempt is due, batch by batch
+func (s *WebhookServiceImpl) deliver(ctx context.Context) error {
+	for {
+		deliveries, err := s.repo.ClaimPendingWebhookDeliveries(ctx, repository.ClaimPendingWebhookDeliveriesParams{
+			LeaseSeconds: int32(webhookLease / time.Second),
+			Limit:        webhookBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to claim webhook deliveries: %w", err)
+		}
+
+		webhooks := make(map[uuid.UUID]repository.Webhook)
+		for _, delivery := range deliveries {
+			webhook, ok := webhooks[delivery.WebhookID]
+			if !ok {
+				webhook, err = s.repo.GetWebhook(ctx, delivery.WebhookID)
+				if err != nil {
+					return fmt.Errorf("failed to get webhook: %w", err)
+				}
+				webhooks[webhook.ID] = webhook
+			}
+			if err := s.send(ctx, webhook, delivery); err != nil {
+				return err
+			}
+		}
+		if len(deliveries) < webhookBatchSize {
+			return nil
+		}
+	}
+}
+
+// send posts one delivery to its webhook and records the outcome
+func (s *WebhookServiceImpl) send(ctx context.Context, webhook repository.Webhook, delivery repository.WebhookDelivery) error {
+	status, sendErr := s.post(ctx, webhook, delivery)
+	responseStatus := pgtype.Int4{Int32: int32(status), Valid: status != 0}
+	if sendErr == nil {
+		err := s.repo.MarkWebhookDelivered(ctx, repository.MarkWebhookDeliveredParams{
+			ID:             delivery.ID,
+			ResponseStatus: responseStatus,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to mark webhook delivered: %w", err)
+		}
+		return nil
+	}
+	if ctx.Err() != nil {
+		// Shutting down; the lease runs out and the delivery is tried again
+		return ctx.Err()
+	}
+
+	backoff := webhookMaxBackoff
+	if delivery.Attempts < 16 {
+		backoff = min(webhookBackoff<<delivery.Attempts, webhookMaxBackoff)
+	}
+	failed, err := s.repo.MarkWebhookDeliveryFailed(ctx, repository.MarkWebhookDeliveryFailedParams{
+		MaxAttempts:    int32(s.policy.MaxAttempts),
+		ResponseStatus: responseStatus,
+		LastError:      pgtype.Text{String: sendErr.Error(), Valid: true},
+		BackoffSeconds: int32(backoff / time.Second),
+		ID:             delivery.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record webhook failure: %w", err)
+	}
+	if failed.Status == WebhookDeliveryFailed {
+		slog.WarnContext(ctx, "Giving up on webhook delivery",
+			"event_type", failed.EventType,
+			"delivery_id", failed.ID,
+			"url", webhook.Url,
+			"attempts", failed.Attempts,
+			"error", sendErr,
+		)
+	}
+	return nil
+}
+
+// post sends a signed delivery, returning the response status if the endpoint answered.
+// Any status other than 2xx is a failure.
+func (s *WebhookServiceImpl) post(ctx context.Context, webhook repository.Webhook, delivery repository.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "BookBridge-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, webhookMaxResponse))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// notify wakes the dispatcher without blocking when it is already due to run
+func (s *WebhookServiceImpl) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// SignWebhookPayload returns the signature header value for a payload sent at timestamp
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func validateWebhook(params WebhookParams) error {
+	u, err := url.Parse(params.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrWebhookURL
+	}
+	if len(params.EventTypes) == 0 {
+		return ErrWebhookEventTypes
+	}
+	for _, eventType := range params.EventTypes {
+		if !isEventType(eventType) {
+			return ErrWebhookEventTypes
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/vasujain275/bookbridge-api/internal/repository"
+	"github.com/vasujain275/bookbridge-api/internal/util"
+)
+
+// WorkServiceImpl implements the WorkService interface
+type WorkServiceImpl struct {
+	repo *repository.Queries
+}
+
+// NewWorkService creates a new work service
+func NewWorkService(repo *repository.Queries) WorkService {
+	return &WorkServiceImpl{
+		repo: repo,
+	}
+}
+
+// GetByID gets a work with all of its editions
+func (s *WorkServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*WorkDetails, error) {
+	ctx, span := tracer.Start(ctx, "WorkService.GetByID")
+	defer span.End()
+
+	work, err := s.repo.GetWork(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work: %w", err)
+	}
+	return s.details(ctx, &work)
+}
+
+// Search finds works whose title, or any edition's title, publisher or description, matches the query.
+// Each work is returned once with its editions listed.
+func (s *WorkServiceImpl) Search(ctx context.Context, query string, limit, offset int32) ([]*WorkDetails, error) {
+	ctx, span := tracer.Start(ctx, "WorkService.Search")
+	defer span.End()
+
+	works, err := s.repo.SearchWorks(ctx, repository.SearchWorksParams{
+		Column1: util.StringToPgText(query),
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search works: %w", err)
+	}
+
+	results := make([]*WorkDetails, len(works))
+	for i := range works {
+		details, err := s.details(ctx, &works[i])
+		if err != nil {
+			return nil, err
+		}
+		results[i] = details
+	}
+	return results, nil
+}
+
+// details loads the editions of a work and totals their available copies
+func (s *WorkServiceImpl) details(ctx context.Context, work *repository.Work) (*WorkDetails, error) {
+	books, err := s.repo.ListBooksByWorkID(ctx, work.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list editions: %w", err)
+	}
+
+	details := &WorkDetails{
+		Work:     work,
+		Editions: make([]*repository.Book, len(books)),
+	}
+	for i := range books {
+		details.Editions[i] = &books[i]
+		details.AvailableCopies += books[i].AvailableCopies
+	}
+	return details, nil
+}
+
+// resolveWork finds the work with the given Open Library key, creating it if it does not exist.
+// Without a key a new work is always created.
+func resolveWork(ctx context.Context, q *repository.Queries, title, openLibraryKey string) (repository.Work, error) {
+	if openLibraryKey != "" {
+		work, err := q.GetWorkByOpenLibraryKey(ctx, util.StringToPgText(openLibraryKey))
+		if err == nil {
+			return work, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return repository.Work{}, fmt.Errorf("failed to get work: %w", err)
+		}
+	}
+
+	work, err := q.CreateWork(ctx, repository.CreateWorkParams{
+		Title:          title,
+		OpenlibraryKey: util.StringToPgText(openLibraryKey),
+	})
+	if err != nil {
+		return repository.Work{}, fmt.Errorf("failed to create work: %w", err)
+	}
+	return work, nil
+}
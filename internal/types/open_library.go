@@ -15,6 +15,7 @@ type OpenLibraryBook struct {
 	Publishers    []string `json:"publishers,omitempty"`
 	Covers        []int    `json:"covers,omitempty"`
 	Contributions []string `json:"contributions,omitempty"`
+	Series        []string `json:"series,omitempty"`
 	Languages     []struct {
 		Key string `json:"key,omitempty"`
 	} `json:"languages,omitempty"`
@@ -32,13 +33,15 @@ type OpenLibraryBook struct {
 	Works         []struct {
 		Key string `json:"key,omitempty"`
 	} `json:"works,omitempty"`
-	Classifications map[string]interface{} `json:"classifications,omitempty"`
-	OCAID           string                 `json:"ocaid,omitempty"`
-	ISBN10          []string               `json:"isbn_10,omitempty"`
-	ISBN13          []string               `json:"isbn_13,omitempty"`
-	LatestRevision  int                    `json:"latest_revision,omitempty"`
-	Revision        int                    `json:"revision,omitempty"`
-	Created         struct {
+	Classifications   map[string]interface{} `json:"classifications,omitempty"`
+	DeweyDecimalClass []string               `json:"dewey_decimal_class,omitempty"`
+	LCClassifications []string               `json:"lc_classifications,omitempty"`
+	OCAID             string                 `json:"ocaid,omitempty"`
+	ISBN10            []string               `json:"isbn_10,omitempty"`
+	ISBN13            []string               `json:"isbn_13,omitempty"`
+	LatestRevision    int                    `json:"latest_revision,omitempty"`
+	Revision          int                    `json:"revision,omitempty"`
+	Created           struct {
 		Type  string `json:"type,omitempty"`
 		Value string `json:"value,omitempty"`
 	} `json:"created,omitempty"`
@@ -47,3 +50,30 @@ type OpenLibraryBook struct {
 		Value string `json:"value,omitempty"`
 	} `json:"last_modified,omitempty"`
 }
+
+// Classification returns the first value Open Library holds for a classification scheme such as
+// "dewey_decimal_class" or "lc_classifications", looking in the top-level field and then in Classifications
+func (b *OpenLibraryBook) Classification(scheme string) string {
+	var values []string
+	switch scheme {
+	case "dewey_decimal_class":
+		values = b.DeweyDecimalClass
+	case "lc_classifications":
+		values = b.LCClassifications
+	}
+	if len(values) == 0 {
+		if list, ok := b.Classifications[scheme].([]interface{}); ok {
+			for _, v := range list {
+				if s, ok := v.(string); ok {
+					values = append(values, s)
+				}
+			}
+		}
+	}
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
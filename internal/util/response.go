@@ -5,15 +5,17 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/vasujain275/bookbridge-api/internal/logger"
 )
 
 // Response is the standard API response structure
 type Response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   interface{} `json:"error,omitempty"`
-	Time    time.Time   `json:"timestamp"`
+	Success   bool        `json:"success"`
+	Message   string      `json:"message,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     interface{} `json:"error,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+	Time      time.Time   `json:"timestamp"`
 }
 
 // NewSuccessResponse creates a new success response
@@ -38,12 +40,16 @@ func NewErrorResponse(message string, err interface{}) Response {
 
 // SendSuccess sends a success response
 func SendSuccess(c *gin.Context, code int, message string, data interface{}) {
-	c.JSON(code, NewSuccessResponse(message, data))
+	response := NewSuccessResponse(message, data)
+	response.RequestID = logger.RequestID(c.Request.Context())
+	c.JSON(code, response)
 }
 
 // SendError sends an error response
 func SendError(c *gin.Context, code int, message string, err interface{}) {
-	c.JSON(code, NewErrorResponse(message, err))
+	response := NewErrorResponse(message, err)
+	response.RequestID = logger.RequestID(c.Request.Context())
+	c.JSON(code, response)
 }
 
 // SendBadRequest sends a bad request response
@@ -66,6 +72,11 @@ func SendUnauthorized(c *gin.Context) {
 	SendError(c, http.StatusUnauthorized, "Unauthorized", nil)
 }
 
+// SendForbidden sends a forbidden response
+func SendForbidden(c *gin.Context) {
+	SendError(c, http.StatusForbidden, "Forbidden", nil)
+}
+
 // SendCreated sends a created response
 func SendCreated(c *gin.Context, message string, data interface{}) {
 	SendSuccess(c, http.StatusCreated, message, data)
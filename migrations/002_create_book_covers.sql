@@ -0,0 +1,15 @@
+-- +goose Up
+-- book_covers table, one row per book with a locally stored cover
+CREATE TABLE book_covers (
+  book_id UUID PRIMARY KEY,
+  etag VARCHAR NOT NULL,       -- Content hash of the original image, used for HTTP caching
+  source_url VARCHAR,          -- Where the cover was downloaded from, NULL for uploads
+  width INT NOT NULL,
+  height INT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS book_covers;
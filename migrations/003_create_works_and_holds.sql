@@ -0,0 +1,48 @@
+-- +goose Up
+-- works table, grouping the editions (books) of the same title
+CREATE TABLE works (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  title VARCHAR NOT NULL,
+  openlibrary_key VARCHAR UNIQUE,  -- e.g. "/works/OL27448W"
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Every existing book becomes the only edition of its own work
+ALTER TABLE books ADD COLUMN work_id UUID;
+INSERT INTO works (id, title, created_at, updated_at)
+SELECT id, title, created_at, updated_at FROM books;
+UPDATE books SET work_id = id;
+ALTER TABLE books ALTER COLUMN work_id SET NOT NULL;
+ALTER TABLE books ADD CONSTRAINT fk_books_work FOREIGN KEY (work_id) REFERENCES works(id);
+
+-- holds table, a member's place in the queue for a work or a specific edition
+CREATE TABLE holds (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL,
+  work_id UUID NOT NULL,
+  book_id UUID,            -- Requested edition, NULL when any edition of the work will do
+  assigned_book_id UUID,   -- Edition whose copy was set aside once the hold became ready
+  status VARCHAR NOT NULL DEFAULT 'waiting',
+  ready_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (work_id) REFERENCES works(id) ON DELETE CASCADE,
+  FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE,
+  FOREIGN KEY (assigned_book_id) REFERENCES books(id) ON DELETE SET NULL
+);
+
+ALTER TABLE holds ADD CONSTRAINT valid_hold_status CHECK (status IN ('waiting', 'ready', 'fulfilled', 'cancelled'));
+
+CREATE INDEX idx_books_work_id ON books(work_id);
+CREATE INDEX idx_works_title ON works(title);
+CREATE INDEX idx_holds_work_id_status ON holds(work_id, status);
+CREATE INDEX idx_holds_user_id ON holds(user_id);
+-- A member can only have one open hold per work
+CREATE UNIQUE INDEX idx_holds_open_user_work ON holds(user_id, work_id) WHERE status IN ('waiting', 'ready');
+
+-- +goose Down
+DROP TABLE IF EXISTS holds;
+ALTER TABLE books DROP COLUMN IF EXISTS work_id;
+DROP TABLE IF EXISTS works;
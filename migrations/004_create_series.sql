@@ -0,0 +1,26 @@
+-- +goose Up
+-- series table, e.g. "Discworld"
+CREATE TABLE series (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name VARCHAR UNIQUE NOT NULL,
+  description TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- series_works junction table, ordering works within a series
+CREATE TABLE series_works (
+  series_id UUID NOT NULL,
+  work_id UUID NOT NULL,
+  volume INT NOT NULL,
+  PRIMARY KEY (series_id, work_id),
+  FOREIGN KEY (series_id) REFERENCES series(id) ON DELETE CASCADE,
+  FOREIGN KEY (work_id) REFERENCES works(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_series_works_work_id ON series_works(work_id);
+CREATE INDEX idx_series_works_series_volume ON series_works(series_id, volume);
+
+-- +goose Down
+DROP TABLE IF EXISTS series_works;
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- merge_redirects table, pointing the IDs of merged-away records at the record that absorbed them
+CREATE TABLE merge_redirects (
+  entity_type VARCHAR NOT NULL,
+  old_id UUID NOT NULL,
+  new_id UUID NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (entity_type, old_id)
+);
+
+ALTER TABLE merge_redirects ADD CONSTRAINT valid_merge_entity_type CHECK (entity_type IN ('author', 'book'));
+
+-- isbn_core reduces an ISBN-10 or 978-prefixed ISBN-13 to the nine digits they share, so both forms compare equal
+-- +goose StatementBegin
+CREATE FUNCTION isbn_core(isbn TEXT) RETURNS TEXT AS $$
+  SELECT CASE
+    WHEN length(d) = 13 AND left(d, 3) = '978' THEN substr(d, 4, 9)
+    WHEN length(d) = 10 THEN left(d, 9)
+  END
+  FROM (SELECT upper(regexp_replace(isbn, '[^0-9Xx]', '', 'g')) AS d) AS digits
+$$ LANGUAGE SQL IMMUTABLE;
+-- +goose StatementEnd
+
+CREATE INDEX idx_merge_redirects_new_id ON merge_redirects(entity_type, new_id);
+CREATE INDEX idx_authors_name_trgm ON authors USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_books_title_trgm ON books USING GIN (title gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_books_title_trgm;
+DROP INDEX IF EXISTS idx_authors_name_trgm;
+DROP FUNCTION IF EXISTS isbn_core(TEXT);
+DROP TABLE IF EXISTS merge_redirects;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Categories form a tree; root categories have no parent
+ALTER TABLE categories ADD COLUMN parent_id UUID;
+ALTER TABLE categories ADD CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories(id);
+ALTER TABLE categories ADD CONSTRAINT category_not_own_parent CHECK (parent_id <> id);
+
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+CREATE INDEX idx_book_categories_category_id ON book_categories(category_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_book_categories_category_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Classification from Open Library and the call number generated from it
+ALTER TABLE books ADD COLUMN dewey_decimal_class VARCHAR;  -- e.g. "823.914"
+ALTER TABLE books ADD COLUMN lc_classification VARCHAR;    -- e.g. "PR6066.R34 C6 1983"
+ALTER TABLE books ADD COLUMN call_number VARCHAR;          -- Class + cutter + year, shared by every copy
+ALTER TABLE books ADD COLUMN shelf_key VARCHAR;            -- Normalized call number that sorts in shelf order
+
+CREATE INDEX idx_books_shelf_key ON books(shelf_key);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_books_shelf_key;
+ALTER TABLE books DROP COLUMN IF EXISTS shelf_key;
+ALTER TABLE books DROP COLUMN IF EXISTS call_number;
+ALTER TABLE books DROP COLUMN IF EXISTS lc_classification;
+ALTER TABLE books DROP COLUMN IF EXISTS dewey_decimal_class;
@@ -0,0 +1,32 @@
+-- +goose Up
+-- Keep only each member's latest review of a book before enforcing one review per member per book
+DELETE FROM book_reviews r
+USING book_reviews newer
+WHERE r.user_id = newer.user_id
+  AND r.book_id = newer.book_id
+  AND (r.created_at, r.id) < (newer.created_at, newer.id);
+
+ALTER TABLE book_reviews ADD CONSTRAINT unique_user_book_review UNIQUE (user_id, book_id);
+ALTER TABLE book_reviews ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- Denormalized rating aggregates, kept in step with book_reviews by the review service
+ALTER TABLE books ADD COLUMN rating_avg DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE books ADD COLUMN rating_count INT NOT NULL DEFAULT 0;
+
+UPDATE books b
+SET rating_avg = r.rating_avg, rating_count = r.rating_count
+FROM (
+  SELECT book_id, AVG(rating)::DOUBLE PRECISION AS rating_avg, COUNT(*)::INT AS rating_count
+  FROM book_reviews
+  GROUP BY book_id
+) r
+WHERE b.id = r.book_id;
+
+CREATE INDEX idx_books_rating ON books(rating_avg DESC, rating_count DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_books_rating;
+ALTER TABLE books DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE books DROP COLUMN IF EXISTS rating_avg;
+ALTER TABLE book_reviews DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE book_reviews DROP CONSTRAINT IF EXISTS unique_user_book_review;
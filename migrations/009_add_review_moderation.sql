@@ -0,0 +1,45 @@
+-- +goose Up
+-- Review moderation; reviews written before moderation existed stay published
+ALTER TABLE book_reviews ADD COLUMN status VARCHAR NOT NULL DEFAULT 'published';
+ALTER TABLE book_reviews ADD COLUMN flag_reason TEXT;        -- Why the word filter held the review for moderation
+ALTER TABLE book_reviews ADD COLUMN moderation_note TEXT;    -- Moderator's note on the last decision
+ALTER TABLE book_reviews ADD COLUMN moderated_by UUID;
+ALTER TABLE book_reviews ADD COLUMN moderated_at TIMESTAMP;
+ALTER TABLE book_reviews ADD CONSTRAINT valid_review_status CHECK (status IN ('pending', 'published', 'rejected', 'hidden'));
+ALTER TABLE book_reviews ADD CONSTRAINT fk_book_reviews_moderator FOREIGN KEY (moderated_by) REFERENCES users(id) ON DELETE SET NULL;
+
+-- review_reports table, members flagging reviews for a moderator to look at
+CREATE TABLE review_reports (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  review_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  reason VARCHAR NOT NULL,
+  details TEXT,
+  status VARCHAR NOT NULL DEFAULT 'open',
+  resolved_by UUID,
+  resolved_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (review_id, user_id),
+  FOREIGN KEY (review_id) REFERENCES book_reviews(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+ALTER TABLE review_reports ADD CONSTRAINT valid_report_reason CHECK (reason IN ('spam', 'offensive', 'spoiler', 'off_topic', 'other'));
+ALTER TABLE review_reports ADD CONSTRAINT valid_report_status CHECK (status IN ('open', 'resolved'));
+
+CREATE INDEX idx_book_reviews_status ON book_reviews(status, created_at);
+CREATE INDEX idx_book_reviews_user_status ON book_reviews(user_id, status);
+CREATE INDEX idx_review_reports_open ON review_reports(review_id) WHERE status = 'open';
+
+-- +goose Down
+DROP TABLE IF EXISTS review_reports;
+DROP INDEX IF EXISTS idx_book_reviews_user_status;
+DROP INDEX IF EXISTS idx_book_reviews_status;
+ALTER TABLE book_reviews DROP CONSTRAINT IF EXISTS fk_book_reviews_moderator;
+ALTER TABLE book_reviews DROP CONSTRAINT IF EXISTS valid_review_status;
+ALTER TABLE book_reviews DROP COLUMN IF EXISTS moderated_at;
+ALTER TABLE book_reviews DROP COLUMN IF EXISTS moderated_by;
+ALTER TABLE book_reviews DROP COLUMN IF EXISTS moderation_note;
+ALTER TABLE book_reviews DROP COLUMN IF EXISTS flag_reason;
+ALTER TABLE book_reviews DROP COLUMN IF EXISTS status;
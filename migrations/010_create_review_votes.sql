@@ -0,0 +1,26 @@
+-- +goose Up
+-- review_votes table, one helpful or unhelpful vote per member per review
+CREATE TABLE review_votes (
+  review_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  helpful BOOLEAN NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (review_id, user_id),
+  FOREIGN KEY (review_id) REFERENCES book_reviews(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Denormalized vote tallies, kept in step with review_votes by the review service
+ALTER TABLE book_reviews ADD COLUMN helpful_count INT NOT NULL DEFAULT 0;
+ALTER TABLE book_reviews ADD COLUMN unhelpful_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_review_votes_user_id ON review_votes(user_id);
+-- Verified-borrower checks look up a member's returned loans of a book
+CREATE INDEX idx_loans_user_book_returned ON loans(user_id, book_id) WHERE returned_date IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_loans_user_book_returned;
+ALTER TABLE book_reviews DROP COLUMN IF EXISTS unhelpful_count;
+ALTER TABLE book_reviews DROP COLUMN IF EXISTS helpful_count;
+DROP TABLE IF EXISTS review_votes;
@@ -0,0 +1,40 @@
+-- +goose Up
+-- reading_lists table, a member's "to read", "favorites" and custom lists of books
+CREATE TABLE reading_lists (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL,
+  name VARCHAR NOT NULL,
+  description TEXT,
+  kind VARCHAR NOT NULL DEFAULT 'custom',
+  is_public BOOLEAN NOT NULL DEFAULT FALSE,
+  share_token VARCHAR UNIQUE,  -- Set while the list is public; making it private revokes the link
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+ALTER TABLE reading_lists ADD CONSTRAINT valid_reading_list_kind CHECK (kind IN ('to_read', 'favorites', 'custom'));
+
+-- reading_list_items table, the books on a list in the member's order
+CREATE TABLE reading_list_items (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  list_id UUID NOT NULL,
+  book_id UUID NOT NULL,
+  position INT NOT NULL,
+  note TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (list_id) REFERENCES reading_lists(id) ON DELETE CASCADE,
+  FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE,
+  CONSTRAINT unique_reading_list_book UNIQUE (list_id, book_id)
+);
+
+CREATE INDEX idx_reading_lists_user_id ON reading_lists(user_id);
+-- A member has at most one "to read" and one "favorites" list
+CREATE UNIQUE INDEX idx_reading_lists_user_kind ON reading_lists(user_id, kind) WHERE kind <> 'custom';
+CREATE INDEX idx_reading_list_items_list_position ON reading_list_items(list_id, position);
+CREATE INDEX idx_reading_list_items_book_id ON reading_list_items(book_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS reading_list_items;
+DROP TABLE IF EXISTS reading_lists;
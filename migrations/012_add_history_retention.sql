@@ -0,0 +1,10 @@
+-- +goose Up
+-- Members who opt out of history retention have their returned loans deleted
+ALTER TABLE users ADD COLUMN retain_history BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Reading statistics scan a member's returned loans by return date
+CREATE INDEX idx_loans_user_returned_date ON loans(user_id, returned_date) WHERE returned_date IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_loans_user_returned_date;
+ALTER TABLE users DROP COLUMN IF EXISTS retain_history;
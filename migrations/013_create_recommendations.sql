@@ -0,0 +1,31 @@
+-- +goose Up
+-- book_similarities table, precomputed "patrons who borrowed this also borrowed" pairs
+CREATE TABLE book_similarities (
+  book_id UUID NOT NULL,
+  similar_book_id UUID NOT NULL,
+  co_borrowers INT NOT NULL,          -- Members who borrowed both books
+  score DOUBLE PRECISION NOT NULL,    -- Cosine similarity of the two books' borrowers
+  computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (book_id, similar_book_id),
+  FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE,
+  FOREIGN KEY (similar_book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+-- user_recommendations table, precomputed personal recommendations
+CREATE TABLE user_recommendations (
+  user_id UUID NOT NULL,
+  book_id UUID NOT NULL,
+  score DOUBLE PRECISION NOT NULL,
+  reason VARCHAR NOT NULL,            -- Signal that contributed most: also_borrowed, author or category
+  computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, book_id),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_book_similarities_book_score ON book_similarities(book_id, score DESC);
+CREATE INDEX idx_user_recommendations_user_score ON user_recommendations(user_id, score DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_recommendations;
+DROP TABLE IF EXISTS book_similarities;
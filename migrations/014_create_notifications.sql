@@ -0,0 +1,42 @@
+-- +goose Up
+-- notification_preferences table, a member's choice of emails; members without a row get every email
+CREATE TABLE notification_preferences (
+  user_id UUID PRIMARY KEY,
+  due_reminders BOOLEAN NOT NULL DEFAULT TRUE,
+  due_reminder_days INT,            -- Days before the due date to remind; NULL uses the library default
+  overdue_notices BOOLEAN NOT NULL DEFAULT TRUE,
+  hold_ready BOOLEAN NOT NULL DEFAULT TRUE,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT valid_due_reminder_days CHECK (due_reminder_days BETWEEN 0 AND 30)
+);
+
+-- notifications table, every email queued for a member with its delivery status
+CREATE TABLE notifications (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL,
+  kind VARCHAR NOT NULL,
+  dedupe_key VARCHAR UNIQUE NOT NULL,  -- Identifies the event, e.g. "overdue:<loan id>", so it is only emailed once
+  recipient VARCHAR NOT NULL,
+  subject VARCHAR NOT NULL,
+  body_text TEXT NOT NULL,
+  body_html TEXT NOT NULL,
+  status VARCHAR NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  sent_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_kind CHECK (kind IN ('welcome', 'due_reminder', 'overdue', 'hold_ready'));
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_status CHECK (status IN ('pending', 'sent', 'failed'));
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_pending ON notifications(next_attempt_at) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_preferences;
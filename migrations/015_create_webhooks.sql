@@ -0,0 +1,39 @@
+-- +goose Up
+-- webhooks table, endpoints registered by admins to receive domain events
+CREATE TABLE webhooks (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  url VARCHAR NOT NULL,
+  secret VARCHAR NOT NULL,          -- Key for the HMAC-SHA256 signature sent with every delivery
+  event_types TEXT[] NOT NULL,      -- Event types the endpoint subscribes to, e.g. {book.created,hold.ready}
+  description TEXT,
+  is_active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- webhook_deliveries table, every event sent or to be sent to a webhook with its delivery status
+CREATE TABLE webhook_deliveries (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  webhook_id UUID NOT NULL,
+  event_id UUID NOT NULL,           -- Shared by the deliveries of one event to every webhook
+  event_type VARCHAR NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  response_status INT,              -- HTTP status of the last attempt, if the endpoint answered
+  last_error TEXT,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  delivered_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+ALTER TABLE webhook_deliveries ADD CONSTRAINT valid_webhook_delivery_status CHECK (status IN ('pending', 'delivered', 'failed'));
+
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
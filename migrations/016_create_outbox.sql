@@ -0,0 +1,24 @@
+-- +goose Up
+-- outbox table, domain events written in the same transaction as the change they describe and
+-- relayed to webhooks, email and in-process subscribers by the dispatcher
+CREATE TABLE outbox (
+  id UUID PRIMARY KEY,              -- The event ID sent to subscribers
+  event_type VARCHAR NOT NULL,
+  dedupe_key VARCHAR UNIQUE NOT NULL,  -- Identifies the change, e.g. "loan.overdue:<loan id>:<due date>", so it is only recorded once
+  payload JSONB NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  dispatched_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at) WHERE dispatched_at IS NULL;
+CREATE INDEX idx_outbox_dispatched_at ON outbox(dispatched_at) WHERE dispatched_at IS NOT NULL;
+
+-- An event relayed more than once is only queued once per webhook
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries(webhook_id, event_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_webhook_deliveries_event;
+DROP TABLE IF EXISTS outbox;
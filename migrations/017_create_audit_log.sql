@@ -0,0 +1,23 @@
+-- +goose Up
+-- audit_log table, who changed what, when and from where. Entries are never updated or deleted,
+-- and keep the actor's ID and username after the user is deleted.
+CREATE TABLE audit_log (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  actor_id UUID,                  -- NULL for anonymous requests and background jobs
+  actor_username VARCHAR,
+  action VARCHAR NOT NULL,        -- e.g. "users.update", or "POST /books/:id/cover" for requests no service recorded
+  entity_type VARCHAR NOT NULL,   -- e.g. "users", "books"
+  entity_id VARCHAR,
+  before JSONB,                   -- The changed fields before the change; NULL when created
+  after JSONB,                    -- The changed fields after the change; NULL when deleted
+  request_id VARCHAR,
+  ip VARCHAR,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id, created_at);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_log;
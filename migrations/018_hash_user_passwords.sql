@@ -0,0 +1,12 @@
+-- +goose Up
+-- Passwords were stored as given; replace each with its bcrypt hash, the format the server now
+-- checks passwords against. pgcrypto's "bf" salts produce hashes Go's bcrypt package accepts.
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+UPDATE users
+SET password_hash = crypt(password_hash, gen_salt('bf', 10))
+WHERE password_hash !~ '^\$2[aby]\$';
+
+-- +goose Down
+-- Hashed passwords cannot be recovered; members must have their passwords reset after rolling back.
+SELECT 1;
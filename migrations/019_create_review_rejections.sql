@@ -0,0 +1,23 @@
+-- +goose Up
+-- review_rejections table, one row each time a moderator rejects a review. Rows outlive the review
+-- being edited back to pending or deleted, so rejections keep counting towards the posting limit.
+CREATE TABLE review_rejections (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id UUID NOT NULL,          -- The review's author
+  review_id UUID NOT NULL,        -- Not a foreign key; the review may since have been deleted
+  moderated_by UUID,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (moderated_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_review_rejections_user ON review_rejections(user_id, created_at);
+
+-- Reviews rejected so far count from when they were rejected
+INSERT INTO review_rejections (user_id, review_id, moderated_by, created_at)
+SELECT user_id, id, moderated_by, moderated_at
+FROM book_reviews
+WHERE status = 'rejected' AND moderated_at IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS review_rejections;
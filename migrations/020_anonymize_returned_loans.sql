@@ -0,0 +1,17 @@
+-- +goose Up
+-- Members who opt out of history retention keep a record of each book they borrowed and returned,
+-- without its dates: enough for verified-borrower reviews and recommendations, but not a reading
+-- history. Anonymized loans have status 'returned' and no dates.
+ALTER TABLE loans ALTER COLUMN borrowed_date DROP NOT NULL;
+ALTER TABLE loans ALTER COLUMN due_date DROP NOT NULL;
+ALTER TABLE loans ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE loans ALTER COLUMN updated_at DROP NOT NULL;
+
+-- +goose Down
+DELETE FROM loans WHERE borrowed_date IS NULL;
+UPDATE loans SET updated_at = CURRENT_TIMESTAMP WHERE updated_at IS NULL;
+UPDATE loans SET created_at = updated_at WHERE created_at IS NULL;
+ALTER TABLE loans ALTER COLUMN updated_at SET NOT NULL;
+ALTER TABLE loans ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE loans ALTER COLUMN due_date SET NOT NULL;
+ALTER TABLE loans ALTER COLUMN borrowed_date SET NOT NULL;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Live event streams on every instance read recorded events in order of creation
+CREATE INDEX idx_outbox_created_at ON outbox(created_at, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_created_at;
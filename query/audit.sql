@@ -0,0 +1,18 @@
+-- name: CreateAuditEntry :one
+INSERT INTO audit_log (
+  actor_id, actor_username, action, entity_type, entity_id, before, after, request_id, ip
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: ListAuditEntries :many
+-- Filters are skipped when empty; actor matches the actor's ID or username
+SELECT * FROM audit_log
+WHERE (@entity_type::text = '' OR entity_type = @entity_type)
+  AND (@entity_id::text = '' OR entity_id = @entity_id)
+  AND (@actor::text = '' OR actor_id::text = @actor OR actor_username = @actor)
+  AND (@created_from::timestamp IS NULL OR created_at >= @created_from)
+  AND (@created_to::timestamp IS NULL OR created_at < @created_to)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
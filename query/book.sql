@@ -6,11 +6,20 @@ WHERE id = $1;
 SELECT * FROM books
 WHERE isbn_13 = $1;
 
+-- name: GetBookByISBN10 :one
+SELECT * FROM books
+WHERE isbn_10 = $1;
+
 -- name: ListBooks :many
 SELECT * FROM books
 ORDER BY title
 LIMIT $1 OFFSET $2;
 
+-- name: ListBooksByRating :many
+SELECT * FROM books
+ORDER BY rating_avg DESC, rating_count DESC, title
+LIMIT $1 OFFSET $2;
+
 -- name: SearchBooks :many
 SELECT * FROM books
 WHERE 
@@ -24,9 +33,9 @@ LIMIT $2 OFFSET $3;
 INSERT INTO books (
   isbn_10, isbn_13, title, publisher,
   published_date, description, page_count, language,
-  thumbnail_url, total_copies, available_copies
+  thumbnail_url, total_copies, available_copies, work_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
 RETURNING *;
 
@@ -60,3 +69,91 @@ SET
   updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
+
+-- name: UpdateBookThumbnail :exec
+UPDATE books
+SET
+  thumbnail_url = $2,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListBooksByWorkID :many
+SELECT * FROM books
+WHERE work_id = $1
+ORDER BY published_date, title;
+
+-- name: GetAvailableBookForWork :one
+SELECT * FROM books
+WHERE work_id = $1 AND available_copies > 0
+ORDER BY available_copies DESC
+LIMIT 1
+FOR UPDATE;
+
+-- name: ReserveBookCopy :one
+UPDATE books
+SET
+  available_copies = available_copies - 1,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND available_copies > 0
+RETURNING *;
+
+-- name: ReleaseBookCopy :one
+UPDATE books
+SET
+  available_copies = available_copies + 1,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND available_copies < total_copies
+RETURNING *;
+
+-- name: ListBooksByAuthorID :many
+SELECT b.* FROM books b
+JOIN book_authors ba ON b.id = ba.book_id
+WHERE ba.author_id = $1
+ORDER BY b.title;
+
+-- name: ListBooksByCategoryTree :many
+WITH RECURSIVE subtree AS (
+  SELECT id FROM categories
+  WHERE id = @category_id
+  UNION ALL
+  SELECT c.id FROM categories c
+  JOIN subtree s ON c.parent_id = s.id
+)
+SELECT b.* FROM books b
+WHERE EXISTS (
+  SELECT 1 FROM book_categories bc
+  JOIN subtree s ON bc.category_id = s.id
+  WHERE bc.book_id = b.id
+)
+ORDER BY b.title
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: UpdateBookClassification :one
+UPDATE books
+SET
+  dewey_decimal_class = $2,
+  lc_classification = $3,
+  call_number = $4,
+  shelf_key = $5,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: ListShelf :many
+SELECT * FROM books
+WHERE shelf_key >= @from_key
+  AND (@to_key::text = '' OR shelf_key <= @to_key)
+ORDER BY shelf_key, title
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: LockBook :exec
+SELECT id FROM books
+WHERE id = $1
+FOR UPDATE;
+
+-- name: RefreshBookRating :exec
+UPDATE books
+SET
+  rating_avg = COALESCE((SELECT AVG(rating) FROM book_reviews WHERE book_id = $1 AND status = 'published'), 0),
+  rating_count = (SELECT COUNT(*) FROM book_reviews WHERE book_id = $1 AND status = 'published')
+WHERE id = $1;
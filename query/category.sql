@@ -12,8 +12,11 @@ ORDER BY name
 LIMIT $1 OFFSET $2;
 
 -- name: CreateCategory :one
-INSERT INTO categories (name)
-VALUES ($1)
+INSERT INTO categories (
+  name, parent_id
+) VALUES (
+  $1, $2
+)
 RETURNING *;
 
 -- name: UpdateCategory :one
@@ -24,6 +27,25 @@ SET
 WHERE id = $1
 RETURNING *;
 
+-- name: LockCategoryTree :exec
+-- Serializes moves within the transaction, so two concurrent moves cannot both pass the cycle check
+SELECT pg_advisory_xact_lock(hashtext('categories'));
+
+-- name: MoveCategory :one
+UPDATE categories
+SET 
+  parent_id = $2,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: ReparentCategoryChildren :exec
+UPDATE categories
+SET 
+  parent_id = @new_parent_id,
+  updated_at = CURRENT_TIMESTAMP
+WHERE parent_id = @category_id;
+
 -- name: DeleteCategory :exec
 DELETE FROM categories
 WHERE id = $1;
@@ -48,3 +70,42 @@ WHERE book_id = $1 AND category_id = $2;
 -- name: RemoveAllBookCategories :exec
 DELETE FROM book_categories
 WHERE book_id = $1;
+
+-- name: ListAllCategories :many
+SELECT * FROM categories
+ORDER BY name;
+
+-- name: ListCategoryChildren :many
+SELECT * FROM categories
+WHERE parent_id = $1
+ORDER BY name;
+
+-- name: ListCategoryAncestors :many
+WITH RECURSIVE ancestors AS (
+  SELECT id, parent_id, 0 AS depth, ARRAY[id] AS path FROM categories
+  WHERE id = @category_id
+  UNION ALL
+  SELECT p.id, p.parent_id, a.depth + 1, a.path || p.id FROM categories p
+  JOIN ancestors a ON p.id = a.parent_id
+  WHERE p.id <> ALL(a.path)
+)
+SELECT c.* FROM categories c
+JOIN ancestors a ON c.id = a.id
+ORDER BY a.depth DESC;
+
+-- name: ListCategoryBookCounts :many
+SELECT category_id, COUNT(*)::int AS book_count FROM book_categories
+GROUP BY category_id;
+
+-- name: ListCategoryTreeBookCounts :many
+WITH RECURSIVE closure AS (
+  SELECT id AS ancestor_id, id AS descendant_id, ARRAY[id] AS path FROM categories
+  UNION ALL
+  SELECT cl.ancestor_id, c.id, cl.path || c.id FROM closure cl
+  JOIN categories c ON c.parent_id = cl.descendant_id
+  WHERE c.id <> ALL(cl.path)
+)
+SELECT cl.ancestor_id AS category_id, COUNT(DISTINCT bc.book_id)::int AS book_count
+FROM closure cl
+JOIN book_categories bc ON bc.category_id = cl.descendant_id
+GROUP BY cl.ancestor_id;
@@ -0,0 +1,22 @@
+-- name: GetBookCover :one
+SELECT * FROM book_covers
+WHERE book_id = $1;
+
+-- name: UpsertBookCover :one
+INSERT INTO book_covers (
+  book_id, etag, source_url, width, height
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (book_id) DO UPDATE
+SET
+  etag = EXCLUDED.etag,
+  source_url = EXCLUDED.source_url,
+  width = EXCLUDED.width,
+  height = EXCLUDED.height,
+  updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteBookCover :exec
+DELETE FROM book_covers
+WHERE book_id = $1;
@@ -0,0 +1,49 @@
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1;
+
+-- name: ListHoldsByUserID :many
+SELECT * FROM holds
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListHoldsByWorkID :many
+SELECT * FROM holds
+WHERE work_id = $1 AND status IN ('waiting', 'ready')
+ORDER BY created_at;
+
+-- name: CreateHold :one
+INSERT INTO holds (
+  user_id, work_id, book_id
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetNextWaitingHold :one
+SELECT * FROM holds
+WHERE status = 'waiting'
+  AND work_id = $1
+  AND (book_id IS NULL OR book_id = $2)
+ORDER BY created_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkHoldReady :one
+UPDATE holds
+SET
+  status = 'ready',
+  assigned_book_id = $2,
+  ready_at = CURRENT_TIMESTAMP,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateHoldStatus :one
+UPDATE holds
+SET
+  status = $2,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
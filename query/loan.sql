@@ -64,3 +64,66 @@ RETURNING *;
 -- name: DeleteLoan :exec
 DELETE FROM loans
 WHERE id = $1;
+
+-- name: ListUserReads :many
+SELECT r.book_id, r.finished_on, b.page_count
+FROM (
+  SELECT l.book_id, l.returned_date AS finished_on
+  FROM loans l
+  WHERE l.user_id = @user_id AND l.returned_date IS NOT NULL
+  UNION ALL
+  SELECT br.book_id, br.created_at::date AS finished_on
+  FROM book_reviews br
+  WHERE br.user_id = @user_id
+    AND NOT EXISTS (
+      SELECT 1 FROM loans l
+      WHERE l.user_id = br.user_id AND l.book_id = br.book_id AND l.returned_date IS NOT NULL
+    )
+) r
+JOIN books b ON b.id = r.book_id
+ORDER BY r.finished_on;
+
+-- name: GetUserLoanSummary :one
+SELECT
+  COUNT(*)::int AS total_loans,
+  COUNT(*) FILTER (WHERE status <> 'returned')::int AS open_loans,
+  COALESCE(AVG(returned_date - borrowed_date) FILTER (WHERE returned_date IS NOT NULL), 0)::float8 AS average_loan_days
+FROM loans
+WHERE user_id = $1;
+
+-- name: ListFavoriteCategories :many
+SELECT c.id, c.name, COUNT(*)::int AS books
+FROM unnest(@book_ids::uuid[]) AS r(book_id)
+JOIN book_categories bc ON bc.book_id = r.book_id
+JOIN categories c ON c.id = bc.category_id
+GROUP BY c.id, c.name
+ORDER BY books DESC, c.name
+LIMIT sqlc.arg(limit);
+
+-- name: ListFavoriteAuthors :many
+SELECT a.id, a.name, COUNT(*)::int AS books
+FROM unnest(@book_ids::uuid[]) AS r(book_id)
+JOIN book_authors ba ON ba.book_id = r.book_id
+JOIN authors a ON a.id = ba.author_id
+GROUP BY a.id, a.name
+ORDER BY books DESC, a.name
+LIMIT sqlc.arg(limit);
+
+-- name: AnonymizeReturnedLoansByUserID :exec
+-- Keeps only who borrowed which book of each returned loan
+UPDATE loans
+SET
+  borrowed_date = NULL,
+  due_date = NULL,
+  returned_date = NULL,
+  created_at = NULL,
+  updated_at = NULL
+WHERE user_id = $1 AND status = 'returned';
+
+-- name: MarkOverdueLoans :many
+UPDATE loans
+SET
+  status = 'overdue',
+  updated_at = CURRENT_TIMESTAMP
+WHERE status = 'active' AND due_date < CURRENT_DATE
+RETURNING *;
@@ -0,0 +1,136 @@
+-- name: ListDuplicateAuthorCandidates :many
+SELECT
+  a1.id AS author_id,
+  a1.name AS author_name,
+  a2.id AS duplicate_id,
+  a2.name AS duplicate_name,
+  similarity(a1.name, a2.name)::float8 AS name_similarity,
+  COALESCE(a1.openlibrary_key = a2.openlibrary_key, false) AS same_openlibrary_key,
+  regexp_replace(lower(a1.name), '[^a-z0-9]', '', 'g') = regexp_replace(lower(a2.name), '[^a-z0-9]', '', 'g') AS same_normalized_name
+FROM authors a1
+JOIN authors a2 ON a1.id < a2.id
+WHERE
+  a1.openlibrary_key = a2.openlibrary_key
+  OR regexp_replace(lower(a1.name), '[^a-z0-9]', '', 'g') = regexp_replace(lower(a2.name), '[^a-z0-9]', '', 'g')
+  OR (a1.name % a2.name AND similarity(a1.name, a2.name) >= @min_similarity::float8)
+ORDER BY same_openlibrary_key DESC, same_normalized_name DESC, name_similarity DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: ListDuplicateBookCandidates :many
+SELECT
+  b1.id AS book_id,
+  b1.title AS book_title,
+  b2.id AS duplicate_id,
+  b2.title AS duplicate_title,
+  similarity(b1.title, b2.title)::float8 AS title_similarity,
+  COALESCE(
+    isbn_core(b1.isbn_13) IN (isbn_core(b2.isbn_13), isbn_core(b2.isbn_10))
+    OR isbn_core(b1.isbn_10) IN (isbn_core(b2.isbn_13), isbn_core(b2.isbn_10)),
+    false
+  ) AS same_isbn,
+  COALESCE(b1.publisher = b2.publisher, false) AS same_publisher
+FROM books b1
+JOIN books b2 ON b1.id < b2.id
+WHERE
+  isbn_core(b1.isbn_13) IN (isbn_core(b2.isbn_13), isbn_core(b2.isbn_10))
+  OR isbn_core(b1.isbn_10) IN (isbn_core(b2.isbn_13), isbn_core(b2.isbn_10))
+  OR (b1.title % b2.title AND similarity(b1.title, b2.title) >= @min_similarity::float8)
+ORDER BY same_isbn DESC, title_similarity DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: GetMergeRedirect :one
+SELECT new_id FROM merge_redirects
+WHERE entity_type = $1 AND old_id = $2;
+
+-- name: CreateMergeRedirect :exec
+INSERT INTO merge_redirects (
+  entity_type, old_id, new_id
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (entity_type, old_id) DO UPDATE
+SET new_id = EXCLUDED.new_id;
+
+-- name: RepointMergeRedirects :exec
+UPDATE merge_redirects
+SET new_id = @survivor_id
+WHERE entity_type = @entity_type AND new_id = @merged_id;
+
+-- name: MoveAuthorBooks :exec
+INSERT INTO book_authors (book_id, author_id)
+SELECT book_id, @survivor_id::uuid FROM book_authors
+WHERE author_id = @merged_id
+ON CONFLICT DO NOTHING;
+
+-- name: MoveBookAuthors :exec
+INSERT INTO book_authors (book_id, author_id)
+SELECT @survivor_id::uuid, author_id FROM book_authors
+WHERE book_id = @merged_id
+ON CONFLICT DO NOTHING;
+
+-- name: MoveBookCategories :exec
+INSERT INTO book_categories (book_id, category_id)
+SELECT @survivor_id::uuid, category_id FROM book_categories
+WHERE book_id = @merged_id
+ON CONFLICT DO NOTHING;
+
+-- name: MoveBookLoans :exec
+UPDATE loans
+SET
+  book_id = @survivor_id,
+  updated_at = CURRENT_TIMESTAMP
+WHERE book_id = @merged_id;
+
+-- name: DeleteSupersededBookReviews :exec
+-- Where a member reviewed both books, deletes the review updated less recently, so the newer one is
+-- kept when the reviews move to the survivor
+DELETE FROM book_reviews r
+USING book_reviews o
+WHERE r.user_id = o.user_id
+  AND (
+    (r.book_id = @survivor_id AND o.book_id = @merged_id)
+    OR (r.book_id = @merged_id AND o.book_id = @survivor_id)
+  )
+  AND (r.updated_at, r.id) < (o.updated_at, o.id);
+
+-- name: MoveBookReviews :exec
+UPDATE book_reviews
+SET book_id = @survivor_id
+WHERE book_id = @merged_id
+  AND NOT EXISTS (
+    SELECT 1 FROM book_reviews r
+    WHERE r.book_id = @survivor_id AND r.user_id = book_reviews.user_id
+  );
+
+-- name: MoveBookHolds :exec
+UPDATE holds
+SET
+  book_id = CASE WHEN book_id = @merged_id::uuid THEN @survivor_id::uuid ELSE book_id END,
+  assigned_book_id = CASE WHEN assigned_book_id = @merged_id::uuid THEN @survivor_id::uuid ELSE assigned_book_id END,
+  work_id = @survivor_work_id,
+  updated_at = CURRENT_TIMESTAMP
+WHERE book_id = @merged_id::uuid OR assigned_book_id = @merged_id::uuid;
+
+-- name: MoveWorkHolds :exec
+UPDATE holds
+SET
+  work_id = @survivor_id,
+  updated_at = CURRENT_TIMESTAMP
+WHERE work_id = @merged_id;
+
+-- name: MoveWorkSeries :exec
+INSERT INTO series_works (series_id, work_id, volume)
+SELECT series_id, @survivor_id::uuid, volume FROM series_works
+WHERE work_id = @merged_id
+ON CONFLICT DO NOTHING;
+
+-- name: MoveBookReadingListItems :exec
+UPDATE reading_list_items
+SET
+  book_id = @survivor_id,
+  updated_at = CURRENT_TIMESTAMP
+WHERE book_id = @merged_id
+  AND NOT EXISTS (
+    SELECT 1 FROM reading_list_items i
+    WHERE i.book_id = @survivor_id AND i.list_id = reading_list_items.list_id
+  );
@@ -0,0 +1,7 @@
+-- name: CountCirculation :one
+-- Open loans and holds by status, for the circulation gauges
+SELECT
+  (SELECT COUNT(*) FROM loans WHERE status = 'active')::int AS active_loans,
+  (SELECT COUNT(*) FROM loans WHERE status = 'overdue')::int AS overdue_loans,
+  (SELECT COUNT(*) FROM holds WHERE status = 'waiting')::int AS waiting_holds,
+  (SELECT COUNT(*) FROM holds WHERE status = 'ready')::int AS ready_holds;
@@ -0,0 +1,142 @@
+-- name: GetNotificationPreferences :one
+SELECT * FROM notification_preferences
+WHERE user_id = $1;
+
+-- name: UpsertNotificationPreferences :one
+INSERT INTO notification_preferences (
+  user_id, due_reminders, due_reminder_days, overdue_notices, hold_ready
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (user_id) DO UPDATE
+SET
+  due_reminders = EXCLUDED.due_reminders,
+  due_reminder_days = EXCLUDED.due_reminder_days,
+  overdue_notices = EXCLUDED.overdue_notices,
+  hold_ready = EXCLUDED.hold_ready,
+  updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetNotification :one
+SELECT * FROM notifications
+WHERE id = $1;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE @status::text = '' OR status = @status
+ORDER BY created_at DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: ListNotificationsByUserID :many
+SELECT * FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CreateNotification :one
+INSERT INTO notifications (
+  user_id, kind, dedupe_key, recipient, subject, body_text, body_html
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (dedupe_key) DO NOTHING
+RETURNING *;
+
+-- name: ClaimPendingNotifications :many
+-- Leases up to limit pending notifications that are due, pushing their next attempt back so another
+-- dispatcher does not pick them up while they are being sent
+UPDATE notifications
+SET
+  next_attempt_at = CURRENT_TIMESTAMP + (@lease_seconds::int * interval '1 second'),
+  updated_at = CURRENT_TIMESTAMP
+WHERE id IN (
+  SELECT id FROM notifications
+  WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+  ORDER BY next_attempt_at
+  LIMIT sqlc.arg(limit)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkNotificationSent :exec
+UPDATE notifications
+SET
+  status = 'sent',
+  attempts = attempts + 1,
+  last_error = NULL,
+  sent_at = CURRENT_TIMESTAMP,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MarkNotificationFailed :one
+UPDATE notifications
+SET
+  status = CASE WHEN attempts + 1 >= @max_attempts::int THEN 'failed' ELSE 'pending' END,
+  attempts = attempts + 1,
+  last_error = @last_error,
+  next_attempt_at = CURRENT_TIMESTAMP + (@backoff_seconds::int * interval '1 second'),
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = @id
+RETURNING *;
+
+-- name: RetryNotification :one
+UPDATE notifications
+SET
+  status = 'pending',
+  next_attempt_at = CURRENT_TIMESTAMP,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'failed'
+RETURNING *;
+
+-- name: ListDueReminderCandidates :many
+SELECT
+  'due_reminder:' || l.id::text || ':' || l.due_date::text AS dedupe_key,
+  u.id AS user_id, u.email, u.first_name, b.title AS book_title, l.due_date,
+  (l.due_date - CURRENT_DATE)::int AS days
+FROM loans l
+JOIN users u ON u.id = l.user_id
+JOIN books b ON b.id = l.book_id
+LEFT JOIN notification_preferences p ON p.user_id = u.id
+WHERE l.returned_date IS NULL
+  AND l.due_date >= CURRENT_DATE
+  AND l.due_date <= CURRENT_DATE + COALESCE(p.due_reminder_days, @default_days::int)
+  AND COALESCE(p.due_reminders, TRUE)
+  AND NOT EXISTS (
+    SELECT 1 FROM notifications n
+    WHERE n.dedupe_key = 'due_reminder:' || l.id::text || ':' || l.due_date::text
+  )
+ORDER BY l.due_date;
+
+-- name: ListOverdueCandidates :many
+SELECT
+  'overdue:' || l.id::text AS dedupe_key,
+  u.id AS user_id, u.email, u.first_name, b.title AS book_title, l.due_date,
+  (CURRENT_DATE - l.due_date)::int AS days
+FROM loans l
+JOIN users u ON u.id = l.user_id
+JOIN books b ON b.id = l.book_id
+LEFT JOIN notification_preferences p ON p.user_id = u.id
+WHERE l.returned_date IS NULL
+  AND l.due_date < CURRENT_DATE
+  AND COALESCE(p.overdue_notices, TRUE)
+  AND NOT EXISTS (
+    SELECT 1 FROM notifications n
+    WHERE n.dedupe_key = 'overdue:' || l.id::text
+  )
+ORDER BY l.due_date;
+
+-- name: ListHoldReadyCandidates :many
+SELECT
+  'hold_ready:' || h.id::text || ':' || h.ready_at::text AS dedupe_key,
+  u.id AS user_id, u.email, u.first_name, b.title AS book_title, b.call_number
+FROM holds h
+JOIN users u ON u.id = h.user_id
+JOIN books b ON b.id = h.assigned_book_id
+LEFT JOIN notification_preferences p ON p.user_id = u.id
+WHERE h.status = 'ready'
+  AND COALESCE(p.hold_ready, TRUE)
+  AND NOT EXISTS (
+    SELECT 1 FROM notifications n
+    WHERE n.dedupe_key = 'hold_ready:' || h.id::text || ':' || h.ready_at::text
+  )
+ORDER BY h.ready_at;
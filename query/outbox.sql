@@ -0,0 +1,62 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox (
+  id, event_type, dedupe_key, payload
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (dedupe_key) DO NOTHING
+RETURNING *;
+
+-- name: NotifyOutbox :exec
+-- Wakes listening dispatchers once the transaction commits
+SELECT pg_notify('outbox', '');
+
+-- name: ClaimOutboxEvents :many
+-- Leases up to limit undispatched events that are due, oldest first, pushing their next attempt back
+-- so another dispatcher does not relay them at the same time
+UPDATE outbox
+SET next_attempt_at = CURRENT_TIMESTAMP + (@lease_seconds::int * interval '1 second')
+WHERE id IN (
+  SELECT id FROM outbox
+  WHERE dispatched_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP
+  ORDER BY created_at
+  LIMIT sqlc.arg(limit)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkOutboxEventDispatched :exec
+UPDATE outbox
+SET
+  attempts = attempts + 1,
+  last_error = NULL,
+  dispatched_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MarkOutboxEventFailed :exec
+UPDATE outbox
+SET
+  attempts = attempts + 1,
+  last_error = @last_error,
+  next_attempt_at = CURRENT_TIMESTAMP + (@backoff_seconds::int * interval '1 second')
+WHERE id = @id;
+
+-- name: DeleteDispatchedOutboxEvents :exec
+DELETE FROM outbox
+WHERE dispatched_at < $1;
+
+-- name: ListOutboxEventsAfter :many
+-- Recorded events of the given types after the (created_at, id) position, oldest first. Every instance
+-- reads these for its live event streams, whichever dispatcher relays them.
+SELECT * FROM outbox
+WHERE (created_at, id) > (@after_created_at::timestamp, @after_id::uuid)
+  AND event_type = ANY(@event_types::text[])
+ORDER BY created_at, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListLatestOutboxEvents :many
+-- The most recent events of the given types, newest first
+SELECT * FROM outbox
+WHERE event_type = ANY(@event_types::text[])
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit);
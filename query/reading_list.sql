@@ -0,0 +1,97 @@
+-- name: GetReadingList :one
+SELECT * FROM reading_lists
+WHERE id = $1;
+
+-- name: GetReadingListByShareToken :one
+SELECT * FROM reading_lists
+WHERE share_token = $1 AND is_public;
+
+-- name: ListReadingListsByUserID :many
+SELECT * FROM reading_lists
+WHERE user_id = @user_id
+  AND (@include_private::bool OR is_public)
+ORDER BY
+  CASE kind WHEN 'to_read' THEN 0 WHEN 'favorites' THEN 1 ELSE 2 END,
+  name
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CreateReadingList :one
+INSERT INTO reading_lists (
+  user_id, name, description, kind, is_public, share_token
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: UpdateReadingList :one
+UPDATE reading_lists
+SET
+  name = $2,
+  description = $3,
+  is_public = $4,
+  share_token = $5,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteReadingList :exec
+DELETE FROM reading_lists
+WHERE id = $1;
+
+-- name: TouchReadingList :exec
+UPDATE reading_lists
+SET updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ListReadingListItems :many
+SELECT i.*, b.title, b.work_id, b.call_number, b.total_copies, b.available_copies,
+  (b.available_copies > 0)::boolean AS available,
+  (
+    SELECT COALESCE(SUM(e.available_copies), 0) FROM books e
+    WHERE e.work_id = b.work_id
+  )::int AS work_available_copies
+FROM reading_list_items i
+JOIN books b ON b.id = i.book_id
+WHERE i.list_id = $1
+ORDER BY i.position;
+
+-- name: ListReadingListItemIDs :many
+SELECT id FROM reading_list_items
+WHERE list_id = $1
+ORDER BY position;
+
+-- name: AddReadingListItem :one
+INSERT INTO reading_list_items (
+  list_id, book_id, note, position
+) VALUES (
+  @list_id, @book_id, @note,
+  (SELECT COALESCE(MAX(position), 0) + 1 FROM reading_list_items WHERE list_id = @list_id)
+)
+RETURNING *;
+
+-- name: UpdateReadingListItemNote :one
+UPDATE reading_list_items
+SET
+  note = $3,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND list_id = $2
+RETURNING *;
+
+-- name: DeleteReadingListItem :one
+DELETE FROM reading_list_items
+WHERE id = $1 AND list_id = $2
+RETURNING *;
+
+-- name: CloseReadingListGap :exec
+UPDATE reading_list_items
+SET position = position - 1
+WHERE list_id = $1 AND position > $2;
+
+-- name: ReorderReadingListItems :exec
+UPDATE reading_list_items
+SET
+  position = o.ord::int,
+  updated_at = CURRENT_TIMESTAMP
+FROM unnest(@item_ids::uuid[]) WITH ORDINALITY AS o(id, ord)
+WHERE reading_list_items.id = o.id
+  AND reading_list_items.list_id = @list_id;
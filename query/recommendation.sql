@@ -0,0 +1,115 @@
+-- name: ClearBookSimilarities :exec
+DELETE FROM book_similarities;
+
+-- name: ComputeBookSimilarities :exec
+-- Pairs of books borrowed by the same members, scored by the cosine similarity of their
+-- borrower sets and limited to the closest per_book for each book. Other editions of the
+-- same work are left out.
+INSERT INTO book_similarities (book_id, similar_book_id, co_borrowers, score)
+WITH borrowers AS (
+  SELECT DISTINCT user_id, book_id FROM loans
+),
+counts AS (
+  SELECT book_id, COUNT(*) AS borrowers FROM borrowers
+  GROUP BY book_id
+),
+pairs AS (
+  SELECT a.book_id, b.book_id AS similar_book_id, COUNT(*) AS co_borrowers
+  FROM borrowers a
+  JOIN borrowers b ON b.user_id = a.user_id AND b.book_id <> a.book_id
+  GROUP BY a.book_id, b.book_id
+  HAVING COUNT(*) >= @min_co_borrowers::int
+),
+ranked AS (
+  SELECT p.book_id, p.similar_book_id, p.co_borrowers,
+    p.co_borrowers / sqrt(ca.borrowers * cb.borrowers) AS score,
+    ROW_NUMBER() OVER (
+      PARTITION BY p.book_id
+      ORDER BY p.co_borrowers / sqrt(ca.borrowers * cb.borrowers) DESC, p.similar_book_id
+    ) AS rank
+  FROM pairs p
+  JOIN counts ca ON ca.book_id = p.book_id
+  JOIN counts cb ON cb.book_id = p.similar_book_id
+  JOIN books ba ON ba.id = p.book_id
+  JOIN books bb ON bb.id = p.similar_book_id
+  WHERE ba.work_id <> bb.work_id
+)
+SELECT book_id, similar_book_id, co_borrowers::int, score::float8
+FROM ranked
+WHERE rank <= @per_book::int;
+
+-- name: ClearUserRecommendations :exec
+DELETE FROM user_recommendations;
+
+-- name: ComputeUserRecommendations :exec
+-- Scores books for each member from the books they borrowed or reviewed: similarity to those
+-- books, shared authors and shared categories, plus a small boost for well-rated books. Books
+-- the member already borrowed or reviewed are left out.
+INSERT INTO user_recommendations (user_id, book_id, score, reason)
+WITH history AS (
+  SELECT DISTINCT user_id, book_id FROM loans
+  UNION
+  SELECT user_id, book_id FROM book_reviews
+),
+candidates AS (
+  SELECT h.user_id, s.similar_book_id AS book_id, SUM(s.score) AS score, 'also_borrowed' AS reason
+  FROM history h
+  JOIN book_similarities s ON s.book_id = h.book_id
+  GROUP BY h.user_id, s.similar_book_id
+  UNION ALL
+  SELECT h.user_id, ba2.book_id, COUNT(*) * @author_weight::float8, 'author'
+  FROM history h
+  JOIN book_authors ba1 ON ba1.book_id = h.book_id
+  JOIN book_authors ba2 ON ba2.author_id = ba1.author_id AND ba2.book_id <> h.book_id
+  GROUP BY h.user_id, ba2.book_id
+  UNION ALL
+  SELECT h.user_id, bc2.book_id, COUNT(*) * @category_weight::float8, 'category'
+  FROM history h
+  JOIN book_categories bc1 ON bc1.book_id = h.book_id
+  JOIN book_categories bc2 ON bc2.category_id = bc1.category_id AND bc2.book_id <> h.book_id
+  GROUP BY h.user_id, bc2.book_id
+),
+scored AS (
+  SELECT c.user_id, c.book_id,
+    SUM(c.score) + CASE WHEN b.rating_count > 0 THEN b.rating_avg / 5 * @rating_weight::float8 ELSE 0 END AS score,
+    (array_agg(c.reason ORDER BY c.score DESC))[1] AS reason
+  FROM candidates c
+  JOIN books b ON b.id = c.book_id
+  WHERE NOT EXISTS (
+    SELECT 1 FROM history h
+    WHERE h.user_id = c.user_id AND h.book_id = c.book_id
+  )
+  GROUP BY c.user_id, c.book_id, b.rating_avg, b.rating_count
+),
+ranked AS (
+  SELECT user_id, book_id, score, reason,
+    ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY score DESC, book_id) AS rank
+  FROM scored
+)
+SELECT user_id, book_id, score::float8, reason
+FROM ranked
+WHERE rank <= @per_user::int;
+
+-- name: ListAlsoBorrowed :many
+SELECT b.*, s.co_borrowers, s.score
+FROM book_similarities s
+JOIN books b ON b.id = s.similar_book_id
+WHERE s.book_id = $1
+ORDER BY s.score DESC, b.title
+LIMIT $2;
+
+-- name: ListUserRecommendations :many
+SELECT b.*, r.score, r.reason, r.computed_at
+FROM user_recommendations r
+JOIN books b ON b.id = r.book_id
+WHERE r.user_id = @user_id
+  AND NOT EXISTS (
+    SELECT 1 FROM loans l
+    WHERE l.user_id = r.user_id AND l.book_id = r.book_id
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM book_reviews br
+    WHERE br.user_id = r.user_id AND br.book_id = r.book_id
+  )
+ORDER BY r.score DESC, b.title
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
@@ -8,26 +8,32 @@ WHERE user_id = $1 AND book_id = $2;
 
 -- name: ListReviews :many
 SELECT * FROM book_reviews
+WHERE status = 'published'
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2;
 
 -- name: ListReviewsByBookID :many
 SELECT * FROM book_reviews
-WHERE book_id = $1
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3;
+WHERE book_id = @book_id AND status = 'published'
+ORDER BY
+  CASE WHEN @sort::text = 'helpful' THEN helpful_count - unhelpful_count END DESC,
+  CASE WHEN @sort::text = 'helpful' THEN helpful_count END DESC,
+  CASE WHEN @sort::text = 'rating' THEN rating END DESC,
+  created_at DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
 -- name: ListReviewsByUserID :many
 SELECT * FROM book_reviews
-WHERE user_id = $1
+WHERE user_id = @user_id
+  AND (@include_unpublished::bool OR status = 'published')
 ORDER BY created_at DESC
-LIMIT $2 OFFSET $3;
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
 -- name: CreateReview :one
 INSERT INTO book_reviews (
-  book_id, user_id, rating, review_text
+  book_id, user_id, rating, review_text, status, flag_reason
 ) VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6
 )
 RETURNING *;
 
@@ -35,10 +41,108 @@ RETURNING *;
 UPDATE book_reviews
 SET 
   rating = $2,
-  review_text = $3
+  review_text = $3,
+  status = $4,
+  flag_reason = $5,
+  updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
 
 -- name: DeleteReview :exec
 DELETE FROM book_reviews
 WHERE id = $1;
+
+-- name: DeleteReviewsByUserID :many
+DELETE FROM book_reviews
+WHERE user_id = $1
+RETURNING book_id;
+
+-- name: ModerateReview :one
+UPDATE book_reviews
+SET
+  status = $2,
+  moderation_note = $3,
+  moderated_by = $4,
+  moderated_at = CURRENT_TIMESTAMP,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateReviewRejection :exec
+INSERT INTO review_rejections (
+  user_id, review_id, moderated_by
+) VALUES (
+  $1, $2, $3
+);
+
+-- name: CountReviewRejectionsSince :one
+SELECT COUNT(*) FROM review_rejections
+WHERE user_id = $1
+  AND created_at >= $2;
+
+-- name: ListModerationQueue :many
+SELECT r.* FROM book_reviews r
+WHERE r.status = 'pending'
+  OR (r.status = 'published' AND EXISTS (
+    SELECT 1 FROM review_reports rr
+    WHERE rr.review_id = r.id AND rr.status = 'open'
+  ))
+ORDER BY r.created_at
+LIMIT $1 OFFSET $2;
+
+-- name: CreateReviewReport :one
+INSERT INTO review_reports (
+  review_id, user_id, reason, details
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListReviewReports :many
+SELECT * FROM review_reports
+WHERE review_id = $1
+ORDER BY created_at;
+
+-- name: ResolveReviewReports :exec
+UPDATE review_reports
+SET
+  status = 'resolved',
+  resolved_by = $2,
+  resolved_at = CURRENT_TIMESTAMP
+WHERE review_id = $1 AND status = 'open';
+
+-- name: ListVerifiedReviewIDs :many
+SELECT r.id FROM book_reviews r
+WHERE r.id = ANY(@review_ids::uuid[])
+  AND EXISTS (
+    SELECT 1 FROM loans l
+    WHERE l.user_id = r.user_id
+      AND l.book_id = r.book_id
+      AND l.status = 'returned'
+  );
+
+-- name: LockReview :exec
+SELECT id FROM book_reviews
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpsertReviewVote :exec
+INSERT INTO review_votes (
+  review_id, user_id, helpful
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (review_id, user_id) DO UPDATE
+SET helpful = EXCLUDED.helpful, updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteReviewVote :exec
+DELETE FROM review_votes
+WHERE review_id = $1 AND user_id = $2;
+
+-- name: RefreshReviewVotes :one
+UPDATE book_reviews
+SET
+  helpful_count = (SELECT COUNT(*) FROM review_votes WHERE review_id = $1 AND helpful),
+  unhelpful_count = (SELECT COUNT(*) FROM review_votes WHERE review_id = $1 AND NOT helpful)
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,69 @@
+-- name: GetSeries :one
+SELECT * FROM series
+WHERE id = $1;
+
+-- name: GetSeriesByName :one
+SELECT * FROM series
+WHERE name = $1;
+
+-- name: ListSeries :many
+SELECT * FROM series
+ORDER BY name
+LIMIT $1 OFFSET $2;
+
+-- name: CreateSeries :one
+INSERT INTO series (
+  name, description
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: UpdateSeries :one
+UPDATE series
+SET 
+  name = $2,
+  description = $3,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteSeries :exec
+DELETE FROM series
+WHERE id = $1;
+
+-- name: AddSeriesWork :exec
+INSERT INTO series_works (
+  series_id, work_id, volume
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (series_id, work_id) DO NOTHING;
+
+-- name: SetSeriesWorkVolume :exec
+INSERT INTO series_works (
+  series_id, work_id, volume
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (series_id, work_id) DO UPDATE
+SET volume = EXCLUDED.volume;
+
+-- name: RemoveSeriesWork :exec
+DELETE FROM series_works
+WHERE series_id = $1 AND work_id = $2;
+
+-- name: ListSeriesVolumes :many
+SELECT sw.volume, w.id AS work_id, w.title, COALESCE(SUM(b.available_copies), 0)::int AS available_copies
+FROM series_works sw
+JOIN works w ON w.id = sw.work_id
+LEFT JOIN books b ON b.work_id = w.id
+WHERE sw.series_id = $1
+GROUP BY sw.volume, w.id, w.title
+ORDER BY sw.volume, w.title;
+
+-- name: ListSeriesByWorkID :many
+SELECT s.*, sw.volume FROM series s
+JOIN series_works sw ON s.id = sw.series_id
+WHERE sw.work_id = $1
+ORDER BY s.name;
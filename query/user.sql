@@ -39,3 +39,11 @@ RETURNING *;
 -- name: DeleteUser :exec
 DELETE FROM users
 WHERE id = $1;
+
+-- name: SetUserHistoryRetention :one
+UPDATE users
+SET
+  retain_history = $2,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
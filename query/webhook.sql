@@ -0,0 +1,101 @@
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = $1;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks
+ORDER BY created_at;
+
+-- name: ListWebhooksForEvent :many
+SELECT * FROM webhooks
+WHERE is_active AND @event_type::text = ANY(event_types);
+
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+  url, secret, event_types, description, is_active
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET
+  url = $2,
+  event_types = $3,
+  description = $4,
+  is_active = $5,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWebhook :exec
+DELETE FROM webhooks
+WHERE id = $1;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = @webhook_id AND (@status::text = '' OR status = @status)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  webhook_id, event_id, event_type, payload
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (webhook_id, event_id) DO NOTHING
+RETURNING *;
+
+-- name: ClaimPendingWebhookDeliveries :many
+-- Leases up to limit pending deliveries that are due, pushing their next attempt back so another
+-- dispatcher does not pick them up while they are being sent
+UPDATE webhook_deliveries
+SET
+  next_attempt_at = CURRENT_TIMESTAMP + (@lease_seconds::int * interval '1 second'),
+  updated_at = CURRENT_TIMESTAMP
+WHERE id IN (
+  SELECT id FROM webhook_deliveries
+  WHERE status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+  ORDER BY next_attempt_at
+  LIMIT sqlc.arg(limit)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkWebhookDelivered :exec
+UPDATE webhook_deliveries
+SET
+  status = 'delivered',
+  attempts = attempts + 1,
+  response_status = $2,
+  last_error = NULL,
+  delivered_at = CURRENT_TIMESTAMP,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MarkWebhookDeliveryFailed :one
+UPDATE webhook_deliveries
+SET
+  status = CASE WHEN attempts + 1 >= @max_attempts::int THEN 'failed' ELSE 'pending' END,
+  attempts = attempts + 1,
+  response_status = @response_status,
+  last_error = @last_error,
+  next_attempt_at = CURRENT_TIMESTAMP + (@backoff_seconds::int * interval '1 second'),
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = @id
+RETURNING *;
+
+-- name: RedeliverWebhookDelivery :one
+UPDATE webhook_deliveries
+SET
+  status = 'pending',
+  next_attempt_at = CURRENT_TIMESTAMP,
+  updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status <> 'pending'
+RETURNING *;
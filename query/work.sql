@@ -0,0 +1,52 @@
+-- name: GetWork :one
+SELECT * FROM works
+WHERE id = $1;
+
+-- name: GetWorkByOpenLibraryKey :one
+SELECT * FROM works
+WHERE openlibrary_key = $1;
+
+-- name: GetWorkByTitleAndAuthor :one
+-- Finds the oldest work with the title, ignoring case, that has an edition by the named author
+SELECT w.* FROM works w
+WHERE lower(w.title) = lower(@title)
+  AND EXISTS (
+    SELECT 1 FROM books b
+    JOIN book_authors ba ON ba.book_id = b.id
+    JOIN authors a ON a.id = ba.author_id
+    WHERE b.work_id = w.id AND lower(a.name) = lower(@author_name)
+  )
+ORDER BY w.created_at
+LIMIT 1;
+
+-- name: CreateWork :one
+INSERT INTO works (
+  title, openlibrary_key
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: SearchWorks :many
+SELECT w.* FROM works w
+WHERE
+  w.title ILIKE '%' || $1 || '%'
+  OR EXISTS (
+    SELECT 1 FROM books b
+    WHERE b.work_id = w.id
+      AND (
+        b.title ILIKE '%' || $1 || '%'
+        OR b.publisher ILIKE '%' || $1 || '%'
+        OR b.description ILIKE '%' || $1 || '%'
+      )
+  )
+ORDER BY w.title
+LIMIT $2 OFFSET $3;
+
+-- name: DeleteWork :exec
+DELETE FROM works
+WHERE id = $1;
+
+-- name: CountWorkEditions :one
+SELECT COUNT(*) FROM books
+WHERE work_id = $1;